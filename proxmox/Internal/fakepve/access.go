@@ -0,0 +1,644 @@
+package fakepve
+
+import (
+	"crypto/rand"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// privileges are all the privileges the fake user holds on every path.
+var privileges = []string{
+	"Datastore.Allocate",
+	"Datastore.AllocateSpace",
+	"Datastore.AllocateTemplate",
+	"Datastore.Audit",
+	"Group.Allocate",
+	"Mapping.Audit",
+	"Mapping.Modify",
+	"Mapping.Use",
+	"Permissions.Modify",
+	"Pool.Allocate",
+	"Pool.Audit",
+	"Realm.Allocate",
+	"Realm.AllocateUser",
+	"SDN.Allocate",
+	"SDN.Audit",
+	"SDN.Use",
+	"Sys.Audit",
+	"Sys.Console",
+	"Sys.Incoming",
+	"Sys.Modify",
+	"Sys.PowerMgmt",
+	"Sys.Syslog",
+	"User.Modify",
+	"VM.Allocate",
+	"VM.Audit",
+	"VM.Backup",
+	"VM.Clone",
+	"VM.Config.CDROM",
+	"VM.Config.CPU",
+	"VM.Config.Cloudinit",
+	"VM.Config.Disk",
+	"VM.Config.HWType",
+	"VM.Config.Memory",
+	"VM.Config.Network",
+	"VM.Config.Options",
+	"VM.Console",
+	"VM.GuestAgent.Audit",
+	"VM.GuestAgent.FileRead",
+	"VM.GuestAgent.FileSystemMgmt",
+	"VM.GuestAgent.FileWrite",
+	"VM.GuestAgent.Unrestricted",
+	"VM.Migrate",
+	"VM.PowerMgmt",
+	"VM.Snapshot",
+	"VM.Snapshot.Rollback",
+}
+
+func (s *Server) registerAccess() {
+	s.handle("POST", `/access/ticket`, func(r *request) (any, error) {
+		if r.get("username") != User || r.get("password") != Password {
+			return nil, errorf(401, "authentication failure")
+		}
+		return map[string]any{
+			"username":            User,
+			"ticket":              "PVE:" + User + ":" + strings.Repeat("0", 8) + "::fake",
+			"CSRFPreventionToken": "fake:csrf",
+			"cap":                 map[string]any{}}, nil
+	})
+	s.handle("GET", `/version`, func(r *request) (any, error) {
+		return map[string]any{
+			"version": "8.4.1",
+			"release": "8.4",
+			"repoid":  "fake"}, nil
+	})
+	s.handle("GET", `/access/permissions`, func(r *request) (any, error) {
+		privs := make(map[string]any, len(privileges))
+		for _, p := range privileges {
+			privs[p] = 1
+		}
+		path := "/"
+		if v := r.get("path"); v != "" {
+			path = v
+		}
+		return map[string]any{path: privs}, nil
+	})
+	s.registerUsers()
+	s.registerGroups()
+	s.registerRoles()
+	s.registerACL()
+}
+
+// userKeys are the user settings that are stored as they are sent.
+var userKeys = []string{"comment", "email", "enable", "expire", "firstname", "lastname", "keys"}
+
+type user struct {
+	id       string
+	config   map[string]string
+	groups   []string
+	tokens   map[string]map[string]string
+	password string
+}
+
+// aclEntry is a single role assignment, PVE stores one line per path, role and user, group or token.
+type aclEntry struct {
+	path      string
+	kind      string // user, group or token
+	ugid      string
+	role      string
+	propagate bool
+}
+
+// builtinRoles are the special roles of PVE, they can not be changed or deleted.
+var builtinRoles = map[string][]string{
+	"Administrator": privileges,
+	"NoAccess":      {},
+	"PVEAuditor":    {"Datastore.Audit", "Mapping.Audit", "Pool.Audit", "SDN.Audit", "Sys.Audit", "VM.Audit"},
+	"PVEVMAdmin":    {"VM.Allocate", "VM.Audit", "VM.Backup", "VM.Clone", "VM.Config.CDROM", "VM.Config.CPU", "VM.Config.Cloudinit", "VM.Config.Disk", "VM.Config.HWType", "VM.Config.Memory", "VM.Config.Network", "VM.Config.Options", "VM.Console", "VM.Migrate", "VM.PowerMgmt", "VM.Snapshot", "VM.Snapshot.Rollback"},
+	"PVEVMUser":     {"VM.Audit", "VM.Backup", "VM.Config.CDROM", "VM.Config.Cloudinit", "VM.Console", "VM.PowerMgmt"},
+}
+
+func (s *Server) user(id string) (*user, error) {
+	if u, ok := s.users[id]; ok {
+		return u, nil
+	}
+	return nil, errorf(500, "no such user ('%s')", id)
+}
+
+func (u *user) api(full bool) map[string]any {
+	item := map[string]any{"userid": u.id, "realm-type": realmOf(u.id)}
+	for k, v := range u.config {
+		item[k] = typed(k, v, map[string]struct{}{"enable": {}, "expire": {}})
+	}
+	if full {
+		item["groups"] = strings.Join(u.groups, ",")
+		tokens := []any{}
+		for _, name := range sortedKeys(u.tokens) {
+			tokens = append(tokens, tokenAPI(name, u.tokens[name]))
+		}
+		item["tokens"] = tokens
+	}
+	return item
+}
+
+func realmOf(id string) string {
+	_, realm, _ := strings.Cut(id, "@")
+	return realm
+}
+
+func tokenAPI(name string, token map[string]string) map[string]any {
+	item := map[string]any{"tokenid": name}
+	for k, v := range token {
+		item[k] = typed(k, v, map[string]struct{}{"expire": {}, "privsep": {}})
+	}
+	return item
+}
+
+// setGroups replaces the groups of a user, all groups have to exist.
+func (s *Server) setGroups(u *user, groups string) error {
+	list := splitList(groups)
+	for _, g := range list {
+		if _, ok := s.groups[g]; !ok {
+			return errorf(500, "no such group '%s'", g)
+		}
+	}
+	slices.Sort(list)
+	u.groups = slices.Compact(list)
+	return nil
+}
+
+func (s *Server) registerUsers() {
+	s.users = map[string]*user{User: {
+		id:       User,
+		config:   map[string]string{"enable": "1", "expire": "0"},
+		tokens:   map[string]map[string]string{},
+		password: Password}}
+	s.handle("GET", `/access/users`, func(r *request) (any, error) {
+		list := []any{}
+		for _, id := range sortedKeys(s.users) {
+			list = append(list, s.users[id].api(r.get("full") == "1"))
+		}
+		return list, nil
+	})
+	s.handle("GET", `/access/users/([^/]+)`, func(r *request) (any, error) {
+		u, err := s.user(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		item := u.api(false)
+		delete(item, "userid")
+		groups := make([]any, len(u.groups))
+		for i := range u.groups {
+			groups[i] = u.groups[i]
+		}
+		item["groups"] = groups
+		tokens := map[string]any{}
+		for name, token := range u.tokens {
+			tokens[name] = tokenAPI(name, token)
+		}
+		item["tokens"] = tokens
+		return item, nil
+	})
+	s.handle("POST", `/access/users`, func(r *request) (any, error) {
+		id := r.get("userid")
+		if !strings.Contains(id, "@") {
+			return nil, errorf(400, "invalid format - value '%s' does not look like a valid user name", id)
+		}
+		if _, ok := s.users[id]; ok {
+			return nil, errorf(500, "create user failed: user '%s' already exists", id)
+		}
+		u := &user{id: id, config: map[string]string{"enable": "1", "expire": "0"}, tokens: map[string]map[string]string{}}
+		for _, key := range userKeys {
+			if r.has(key) {
+				u.config[key] = r.get(key)
+			}
+		}
+		if err := s.setGroups(u, r.get("groups")); err != nil {
+			return nil, err
+		}
+		u.password = r.get("password")
+		s.users[id] = u
+		return nil, nil
+	})
+	s.handle("PUT", `/access/users/([^/]+)`, func(r *request) (any, error) {
+		u, err := s.user(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		if r.has("groups") {
+			groups := r.get("groups")
+			if r.get("append") == "1" {
+				groups += "," + strings.Join(u.groups, ",")
+			}
+			if err := s.setGroups(u, groups); err != nil {
+				return nil, err
+			}
+		}
+		for _, key := range userKeys {
+			if r.has(key) {
+				u.config[key] = r.get(key)
+			}
+		}
+		for _, key := range splitList(r.get("delete")) {
+			delete(u.config, key)
+		}
+		return nil, nil
+	})
+	s.handle("DELETE", `/access/users/([^/]+)`, func(r *request) (any, error) {
+		if _, err := s.user(r.vars[0]); err != nil {
+			return nil, err
+		}
+		delete(s.users, r.vars[0])
+		s.acl = slices.DeleteFunc(s.acl, func(e aclEntry) bool {
+			return (e.kind == "user" && e.ugid == r.vars[0]) || (e.kind == "token" && strings.HasPrefix(e.ugid, r.vars[0]+"!"))
+		})
+		return nil, nil
+	})
+	s.handle("PUT", `/access/password`, func(r *request) (any, error) {
+		u, err := s.user(r.get("userid"))
+		if err != nil {
+			return nil, err
+		}
+		if len(r.get("password")) < 8 {
+			return nil, errorf(400, "password: value must have at least 8 characters")
+		}
+		u.password = r.get("password")
+		return nil, nil
+	})
+	s.registerTokens()
+}
+
+func (s *Server) registerTokens() {
+	token := func(r *request) (*user, map[string]string, error) {
+		u, err := s.user(r.vars[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		t, ok := u.tokens[r.vars[1]]
+		if !ok {
+			return nil, nil, errorf(500, "no such token '%s' for user '%s'", r.vars[1], u.id)
+		}
+		return u, t, nil
+	}
+	set := func(t map[string]string, r *request) {
+		for _, key := range []string{"comment", "expire", "privsep"} {
+			if r.has(key) {
+				t[key] = strings.TrimSpace(r.get(key))
+			}
+		}
+	}
+	s.handle("GET", `/access/users/([^/]+)/token`, func(r *request) (any, error) {
+		u, err := s.user(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		list := []any{}
+		for _, name := range sortedKeys(u.tokens) {
+			list = append(list, tokenAPI(name, u.tokens[name]))
+		}
+		return list, nil
+	})
+	s.handle("GET", `/access/users/([^/]+)/token/([^/]+)`, func(r *request) (any, error) {
+		_, t, err := token(r)
+		if err != nil {
+			return nil, err
+		}
+		item := tokenAPI(r.vars[1], t)
+		delete(item, "tokenid")
+		return item, nil
+	})
+	s.handle("POST", `/access/users/([^/]+)/token/([^/]+)`, func(r *request) (any, error) {
+		u, err := s.user(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := u.tokens[r.vars[1]]; ok {
+			return nil, errorf(500, "Token already exists.")
+		}
+		t := map[string]string{"expire": "0", "privsep": "1"}
+		set(t, r)
+		u.tokens[r.vars[1]] = t
+		return map[string]any{
+			"full-tokenid": u.id + "!" + r.vars[1],
+			"info":         tokenAPI(r.vars[1], t),
+			"value":        rand.Text()}, nil
+	})
+	s.handle("PUT", `/access/users/([^/]+)/token/([^/]+)`, func(r *request) (any, error) {
+		_, t, err := token(r)
+		if err != nil {
+			return nil, err
+		}
+		set(t, r)
+		return tokenAPI(r.vars[1], t), nil
+	})
+	s.handle("DELETE", `/access/users/([^/]+)/token/([^/]+)`, func(r *request) (any, error) {
+		u, _, err := token(r)
+		if err != nil {
+			return nil, err
+		}
+		delete(u.tokens, r.vars[1])
+		id := u.id + "!" + r.vars[1]
+		s.acl = slices.DeleteFunc(s.acl, func(e aclEntry) bool { return e.kind == "token" && e.ugid == id })
+		return nil, nil
+	})
+}
+
+// members returns the users that are in the group.
+func (s *Server) members(group string) []string {
+	members := []string{}
+	for _, id := range sortedKeys(s.users) {
+		if slices.Contains(s.users[id].groups, group) {
+			members = append(members, id)
+		}
+	}
+	return members
+}
+
+func (s *Server) registerGroups() {
+	s.groups = map[string]map[string]string{}
+	s.handle("GET", `/access/groups`, func(r *request) (any, error) {
+		list := []any{}
+		for _, name := range sortedKeys(s.groups) {
+			list = append(list, map[string]any{
+				"groupid": name,
+				"comment": s.groups[name]["comment"],
+				"users":   strings.Join(s.members(name), ",")})
+		}
+		return list, nil
+	})
+	s.handle("GET", `/access/groups/([^/]+)`, func(r *request) (any, error) {
+		group, ok := s.groups[r.vars[0]]
+		if !ok {
+			return nil, errorf(500, "group '%s' does not exist", r.vars[0])
+		}
+		members := []any{}
+		for _, id := range s.members(r.vars[0]) {
+			members = append(members, id)
+		}
+		return map[string]any{"comment": group["comment"], "members": members}, nil
+	})
+	s.handle("POST", `/access/groups`, func(r *request) (any, error) {
+		name := r.get("groupid")
+		if name == "" {
+			return nil, errorf(400, "missing groupid")
+		}
+		if _, ok := s.groups[name]; ok {
+			return nil, errorf(500, "create group failed: group '%s' already exists", name)
+		}
+		s.groups[name] = map[string]string{"comment": r.get("comment")}
+		return nil, nil
+	})
+	s.handle("PUT", `/access/groups/([^/]+)`, func(r *request) (any, error) {
+		group, ok := s.groups[r.vars[0]]
+		if !ok {
+			return nil, errorf(500, "update group failed: group '%s' does not exist", r.vars[0])
+		}
+		if r.has("comment") {
+			group["comment"] = r.get("comment")
+		}
+		return nil, nil
+	})
+	s.handle("DELETE", `/access/groups/([^/]+)`, func(r *request) (any, error) {
+		name := r.vars[0]
+		if _, ok := s.groups[name]; !ok {
+			return nil, errorf(500, "delete group failed: group '%s' does not exist", name)
+		}
+		delete(s.groups, name)
+		for _, u := range s.users {
+			u.groups = slices.DeleteFunc(u.groups, func(g string) bool { return g == name })
+		}
+		s.acl = slices.DeleteFunc(s.acl, func(e aclEntry) bool { return e.kind == "group" && e.ugid == name })
+		return nil, nil
+	})
+}
+
+// checkPrivileges validates a privilege list and returns it sorted and without duplicates.
+func checkPrivileges(raw string) ([]string, error) {
+	list := strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || r == ';' || r == ' ' })
+	for _, p := range list {
+		if !slices.Contains(privileges, p) {
+			return nil, errorf(400, "invalid privilege '%s'", p)
+		}
+	}
+	slices.Sort(list)
+	return slices.Compact(list), nil
+}
+
+func (s *Server) registerRoles() {
+	s.roles = map[string][]string{}
+	for name, privs := range builtinRoles {
+		s.roles[name] = privs
+	}
+	s.handle("GET", `/access/roles`, func(r *request) (any, error) {
+		list := []any{}
+		for _, name := range sortedKeys(s.roles) {
+			_, special := builtinRoles[name]
+			list = append(list, map[string]any{
+				"roleid":  name,
+				"privs":   strings.Join(s.roles[name], ","),
+				"special": map[bool]int{true: 1, false: 0}[special]})
+		}
+		return list, nil
+	})
+	s.handle("GET", `/access/roles/([^/]+)`, func(r *request) (any, error) {
+		privs, ok := s.roles[r.vars[0]]
+		if !ok {
+			return nil, errorf(500, "role '%s' does not exist", r.vars[0])
+		}
+		item := map[string]any{}
+		for _, p := range privs {
+			item[p] = 1
+		}
+		return item, nil
+	})
+	s.handle("POST", `/access/roles`, func(r *request) (any, error) {
+		name := r.get("roleid")
+		if name == "" {
+			return nil, errorf(400, "missing roleid")
+		}
+		if _, ok := s.roles[name]; ok {
+			return nil, errorf(500, "role '%s' already exists", name)
+		}
+		privs, err := checkPrivileges(r.get("privs"))
+		if err != nil {
+			return nil, err
+		}
+		s.roles[name] = privs
+		return nil, nil
+	})
+	s.handle("PUT", `/access/roles/([^/]+)`, func(r *request) (any, error) {
+		name := r.vars[0]
+		current, ok := s.roles[name]
+		if !ok {
+			return nil, errorf(500, "role '%s' does not exist", name)
+		}
+		if _, special := builtinRoles[name]; special {
+			return nil, errorf(500, "cannot modify special role '%s'", name)
+		}
+		raw := r.get("privs")
+		if r.get("append") == "1" {
+			raw += "," + strings.Join(current, ",")
+		}
+		privs, err := checkPrivileges(raw)
+		if err != nil {
+			return nil, err
+		}
+		s.roles[name] = privs
+		return nil, nil
+	})
+	s.handle("DELETE", `/access/roles/([^/]+)`, func(r *request) (any, error) {
+		name := r.vars[0]
+		if _, ok := s.roles[name]; !ok {
+			return nil, errorf(500, "role '%s' does not exist", name)
+		}
+		if _, special := builtinRoles[name]; special {
+			return nil, errorf(500, "cannot delete special role '%s'", name)
+		}
+		delete(s.roles, name)
+		s.acl = slices.DeleteFunc(s.acl, func(e aclEntry) bool { return e.role == name })
+		return nil, nil
+	})
+}
+
+// checkUgid validates that the user, group or token of an ACL entry exists.
+func (s *Server) checkUgid(kind, ugid string) error {
+	switch kind {
+	case "user":
+		_, err := s.user(ugid)
+		return err
+	case "group":
+		if _, ok := s.groups[ugid]; !ok {
+			return errorf(500, "group '%s' does not exist", ugid)
+		}
+	case "token":
+		id, name, _ := strings.Cut(ugid, "!")
+		u, err := s.user(id)
+		if err != nil {
+			return err
+		}
+		if _, ok := u.tokens[name]; !ok {
+			return errorf(500, "no such token '%s' for user '%s'", name, id)
+		}
+	}
+	return nil
+}
+
+func (s *Server) registerACL() {
+	s.handle("GET", `/access/acl`, func(r *request) (any, error) {
+		list := []any{}
+		for _, e := range s.acl {
+			list = append(list, map[string]any{
+				"path":      e.path,
+				"type":      e.kind,
+				"ugid":      e.ugid,
+				"roleid":    e.role,
+				"propagate": map[bool]int{true: 1, false: 0}[e.propagate]})
+		}
+		return list, nil
+	})
+	s.handle("PUT", `/access/acl`, func(r *request) (any, error) {
+		path := r.get("path")
+		if !strings.HasPrefix(path, "/") {
+			return nil, errorf(400, "path: invalid ACL path '%s'", path)
+		}
+		roles := splitList(r.get("roles"))
+		for _, role := range roles {
+			if _, ok := s.roles[role]; !ok {
+				return nil, errorf(500, "role '%s' does not exist", role)
+			}
+		}
+		var targets []aclEntry
+		for kind, key := range map[string]string{"user": "users", "group": "groups", "token": "tokens"} {
+			for _, ugid := range splitList(r.get(key)) {
+				if err := s.checkUgid(kind, ugid); err != nil {
+					return nil, err
+				}
+				for _, role := range roles {
+					targets = append(targets, aclEntry{path: path, kind: kind, ugid: ugid, role: role})
+				}
+			}
+		}
+		if len(targets) == 0 {
+			return nil, errorf(400, "either 'users', 'groups' or 'tokens' is required")
+		}
+		propagate := !r.has("propagate") || r.get("propagate") == "1"
+		for _, target := range targets {
+			i := slices.IndexFunc(s.acl, func(e aclEntry) bool {
+				return e.path == target.path && e.kind == target.kind && e.ugid == target.ugid && e.role == target.role
+			})
+			switch {
+			case r.get("delete") == "1" && i >= 0:
+				s.acl = slices.Delete(s.acl, i, i+1)
+			case r.get("delete") == "1":
+			case i >= 0:
+				s.acl[i].propagate = propagate
+			default:
+				target.propagate = propagate
+				s.acl = append(s.acl, target)
+			}
+		}
+		slices.SortFunc(s.acl, func(a, b aclEntry) int {
+			return strings.Compare(a.path+"\x00"+a.kind+"\x00"+a.ugid+"\x00"+a.role, b.path+"\x00"+b.kind+"\x00"+b.ugid+"\x00"+b.role)
+		})
+		return nil, nil
+	})
+}
+
+// AccessUser returns the stored settings of a user, the groups of the user are returned as a comma separated list under "groups".
+func (s *Server) AccessUser(id string) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[id]
+	if !ok {
+		return nil, false
+	}
+	config := map[string]string{"groups": strings.Join(u.groups, ","), "password": u.password}
+	for k, v := range u.config {
+		config[k] = v
+	}
+	return config, true
+}
+
+// AccessGroup returns the comment and the members of a group.
+func (s *Server) AccessGroup(name string) (string, []string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	group, ok := s.groups[name]
+	if !ok {
+		return "", nil, false
+	}
+	return group["comment"], s.members(name), true
+}
+
+// AccessRole returns the privileges of a role.
+func (s *Server) AccessRole(name string) ([]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	privs, ok := s.roles[name]
+	return privs, ok
+}
+
+// AccessToken returns the stored settings of the API token `user@realm!name`.
+func (s *Server) AccessToken(id string) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	userID, name, _ := strings.Cut(id, "!")
+	u, ok := s.users[userID]
+	if !ok {
+		return nil, false
+	}
+	token, ok := u.tokens[name]
+	return token, ok
+}
+
+// AccessACL returns the ACL entries in the format `path:type:ugid:role:propagate`.
+func (s *Server) AccessACL() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]string, len(s.acl))
+	for i, e := range s.acl {
+		list[i] = fmt.Sprintf("%s:%s:%s:%s:%t", e.path, e.kind, e.ugid, e.role, e.propagate)
+	}
+	return list
+}
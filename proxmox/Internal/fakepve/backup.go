@@ -0,0 +1,120 @@
+package fakepve
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (s *Server) registerBackup() {
+	jobs := newCollection("backup job", "id", "all", "enabled", "protected", "bwlimit", "ionice", "pigz", "zstd")
+	jobs.defaults = map[string]string{"type": "vzdump", "enabled": "1"}
+	jobs.newID = func() string {
+		s.taskSeq++
+		return fmt.Sprintf("backup-%08x-%04x", s.taskSeq, s.taskSeq)
+	}
+	jobs.validate = func(id string, job map[string]string) error {
+		if job["schedule"] == "" {
+			return errorf(400, "parameter verification failed: schedule: property is missing and it is not optional")
+		}
+		if st, ok := s.storages[job["storage"]]; !ok || !st.supports("backup") {
+			return errorf(500, "storage '%s' does not support backups", job["storage"])
+		}
+		if job["all"] != "1" && !hasAny(job, "vmid", "pool") {
+			return errorf(400, "one of 'all', 'vmid' or 'pool' must be set")
+		}
+		if job["all"] == "1" && hasAny(job, "vmid", "pool") {
+			return errorf(400, "option 'all' conflicts with 'vmid' and 'pool'")
+		}
+		if hasAny(job, "exclude") && job["all"] != "1" && !hasAny(job, "pool") {
+			return errorf(400, "option 'exclude' requires 'all' or 'pool'")
+		}
+		if node := job["node"]; node != "" {
+			if _, ok := s.nodes[node]; !ok {
+				return errorf(400, "no such node '%s'", node)
+			}
+		}
+		return nil
+	}
+	s.backupJobs = jobs
+	s.registerCollection(`/cluster/backup`, jobs)
+}
+
+// BackupJob returns the stored config of a backup job, the way it would be written to jobs.cfg.
+func (s *Server) BackupJob(id string) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.backupJobs.items[id]
+	return job, ok
+}
+
+// PutBackup stores a vzdump archive of a guest with the given config on a storage and returns its volume ID.
+// Disks are allocated anew when the archive is restored, only their storage and options are taken from the config.
+func (s *Server) PutBackup(node, storageName, guestType string, id int, config map[string]string) string {
+	var data strings.Builder
+	for _, key := range sortedKeys(config) {
+		data.WriteString(key + ": " + config[key] + "\n")
+	}
+	filename := fmt.Sprintf("vzdump-%s-%d-%s.vma.zst", guestType, id, time.Now().Format("2006_01_02-15_04_05"))
+	if guestType == guestLxc {
+		filename = strings.Replace(filename, ".vma.", ".tar.", 1)
+	}
+	s.PutFile(node, storageName, "backup", filename, []byte(data.String()))
+	return storageName + ":backup/" + filename
+}
+
+// restoreGuest creates a guest from a backup archive created by PutBackup.
+func (s *Server) restoreGuest(node, guestType string, r *request) (any, error) {
+	if _, err := s.node(node); err != nil {
+		return nil, err
+	}
+	id := r.int("vmid")
+	taskType := map[string]string{guestQemu: "qmrestore", guestLxc: "vzrestore"}[guestType]
+	if _, ok := s.guests[id]; ok && r.get("force") != "1" {
+		return nil, errorf(500, "unable to restore VM %d - VM %d already exists on node '%s'", id, id, s.guests[id].node)
+	}
+	archive := r.get("archive")
+	storageName, _, _ := strings.Cut(archive, ":")
+	st, err := s.storage(node, storageName)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := st.volume(node, archive)
+	if !ok || v.content != "backup" {
+		return s.failedTask(node, taskType, strconv.Itoa(id), fmt.Sprintf("unable to parse volume ID '%s'", archive)), nil
+	}
+
+	upid := s.newTask(node, taskType, strconv.Itoa(id), "restore vma archive: "+archive)
+	if s.taskFailed(upid) {
+		return upid, nil
+	}
+	g := &guest{id: id, node: node, guestType: guestType, config: map[string]string{}, status: "stopped"}
+	for _, line := range strings.Split(string(v.data), "\n") {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			continue
+		}
+		switch {
+		case diskKey.MatchString(key) && !strings.Contains(value, "media=cdrom"):
+			storage, rest, _ := strings.Cut(value, ":")
+			if override := r.get("storage"); override != "" {
+				storage = override
+			}
+			_, options, _ := strings.Cut(rest, ",")
+			value = s.cloneVolume(g, storage, key, options)
+		case strings.HasPrefix(key, "net") && r.get("unique") == "1":
+			index, _ := strconv.Atoi(strings.TrimPrefix(key, "net"))
+			value = macAddress.ReplaceAllString(value, newMAC(id, index))
+		}
+		g.config[key] = value
+	}
+	s.guests[id] = g
+	if p, ok := s.pools[r.get("pool")]; ok {
+		p.add(g)
+	}
+	if r.get("start") == "1" {
+		g.status, g.started = "running", time.Now()
+	}
+	return upid, nil
+}
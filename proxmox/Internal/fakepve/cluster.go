@@ -0,0 +1,159 @@
+package fakepve
+
+import (
+	"sort"
+	"strconv"
+)
+
+func (s *Server) registerCluster() {
+	s.handle("GET", `/cluster/nextid`, func(r *request) (any, error) {
+		if r.has("vmid") {
+			id := r.int("vmid")
+			if _, ok := s.guests[id]; ok {
+				return nil, errorf(400, "VM %d already exists", id)
+			}
+			return strconv.Itoa(id), nil
+		}
+		return strconv.Itoa(s.nextID()), nil
+	})
+	s.handle("GET", `/cluster/resources`, func(r *request) (any, error) {
+		resources := []any{}
+		if t := r.get("type"); t == "" || t == "vm" {
+			for _, id := range s.guestIDs() {
+				resources = append(resources, s.guests[id].resource())
+			}
+		}
+		if t := r.get("type"); t == "" || t == "node" {
+			for _, name := range sortedKeys(s.nodes) {
+				resources = append(resources, s.nodes[name].resource())
+			}
+		}
+		if t := r.get("type"); t == "" || t == "storage" {
+			for _, name := range sortedKeys(s.storages) {
+				for _, n := range sortedKeys(s.nodes) {
+					if st := s.storages[name]; st.availableOn(n) {
+						resources = append(resources, st.resource(n))
+					}
+				}
+			}
+		}
+		return resources, nil
+	})
+	s.handle("GET", `/cluster/ha/resources`, func(r *request) (any, error) {
+		list := []any{}
+		for _, id := range s.haIDs() {
+			list = append(list, s.ha[id])
+		}
+		return list, nil
+	})
+	s.handle("GET", `/cluster/ha/resources/(?:vm:|ct:)?(\d+)`, func(r *request) (any, error) {
+		id, _ := strconv.Atoi(r.vars[0])
+		if res, ok := s.ha[id]; ok {
+			return res, nil
+		}
+		return nil, errorf(500, "no such resource '%d'", id)
+	})
+	s.handle("POST", `/cluster/ha/resources`, func(r *request) (any, error) {
+		guestType, id := parseHaSid(r.get("sid"))
+		if _, ok := s.ha[id]; ok {
+			return nil, errorf(500, "resource ID '%s' already defined", r.get("sid"))
+		}
+		if _, ok := s.guests[id]; !ok {
+			return nil, errorf(500, "no such guest '%d'", id)
+		}
+		if err := s.checkHa(r); err != nil {
+			return nil, err
+		}
+		s.ha[id] = map[string]any{"sid": guestType + ":" + strconv.Itoa(id), "type": guestType}
+		s.setHa(id, r)
+		return nil, nil
+	})
+	s.handle("PUT", `/cluster/ha/resources/(?:vm:|ct:)?(\d+)`, func(r *request) (any, error) {
+		id, _ := strconv.Atoi(r.vars[0])
+		if _, ok := s.ha[id]; !ok {
+			return nil, errorf(500, "no such resource '%d'", id)
+		}
+		if err := s.checkHa(r); err != nil {
+			return nil, err
+		}
+		s.setHa(id, r)
+		return nil, nil
+	})
+	s.handle("DELETE", `/cluster/ha/resources/(?:vm:|ct:)?(\d+)`, func(r *request) (any, error) {
+		id, _ := strconv.Atoi(r.vars[0])
+		delete(s.ha, id)
+		return nil, nil
+	})
+}
+
+func (s *Server) nextID() int {
+	id := defaultNextID
+	for {
+		if _, ok := s.guests[id]; !ok {
+			return id
+		}
+		id++
+	}
+}
+
+func (s *Server) guestIDs() []int {
+	ids := make([]int, 0, len(s.guests))
+	for id := range s.guests {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (s *Server) haIDs() []int {
+	ids := make([]int, 0, len(s.ha))
+	for id := range s.ha {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// haNumeric are the keys of an HA resource PVE returns as JSON numbers.
+var haNumeric = map[string]struct{}{"max_restart": {}, "max_relocate": {}, "failback": {}}
+
+// checkHa validates the parameters of an HA resource request.
+func (s *Server) checkHa(r *request) error {
+	if g := r.get("group"); g != "" {
+		if _, ok := s.haGroups.items[g]; !ok {
+			return errorf(500, "HA group '%s' does not exist", g)
+		}
+	}
+	switch r.get("state") {
+	case "", "started", "stopped", "enabled", "disabled", "ignored":
+		return nil
+	}
+	return errorf(400, "parameter verification failed: state: value '%s' does not have a value in the enumeration", r.get("state"))
+}
+
+func (s *Server) setHa(id int, r *request) {
+	for _, k := range []string{"state", "group", "comment", "max_restart", "max_relocate", "failback"} {
+		if r.has(k) {
+			s.ha[id][k] = typed(k, r.get(k), haNumeric)
+		}
+	}
+	for _, k := range splitList(r.get("delete")) {
+		delete(s.ha[id], k)
+	}
+	if _, ok := s.ha[id]["state"]; !ok {
+		s.ha[id]["state"] = "started"
+	}
+	if g, ok := s.guests[id]; ok {
+		g.haState = s.ha[id]["state"].(string)
+	}
+}
+
+func parseHaSid(sid string) (string, int) {
+	guestType := "vm"
+	if len(sid) > 3 && (sid[:3] == "vm:" || sid[:3] == "ct:") {
+		guestType = sid[:2]
+		sid = sid[3:]
+	}
+	id, _ := strconv.Atoi(sid)
+	return guestType, id
+}
@@ -0,0 +1,164 @@
+package fakepve
+
+import (
+	"strings"
+)
+
+// collection is a generic set of config objects, like backup jobs or HA groups, that PVE manages with plain CRUD endpoints.
+type collection struct {
+	// kind is used in error messages, e.g. "backup job".
+	kind string
+	// idKey is the parameter that holds the ID of an object.
+	idKey string
+	// numeric are the keys PVE returns as JSON numbers.
+	numeric map[string]struct{}
+	// hidden are the keys that are stored but never returned, like passwords.
+	hidden map[string]struct{}
+	// defaults are set on every new object.
+	defaults map[string]string
+	// fixed are the keys that can only be set when an object is created.
+	fixed map[string]struct{}
+	// newID generates an ID when the create request does not contain one.
+	newID func() string
+	// validate is called with the ID and the object after every change, an error rejects the change.
+	validate func(id string, item map[string]string) error
+	// remove is called with the ID before an object is deleted, an error keeps the object.
+	remove func(id string) error
+	items  map[string]map[string]string
+}
+
+func newCollection(kind, idKey string, numeric ...string) *collection {
+	c := &collection{
+		kind:     kind,
+		idKey:    idKey,
+		numeric:  map[string]struct{}{},
+		hidden:   map[string]struct{}{},
+		defaults: map[string]string{},
+		fixed:    map[string]struct{}{},
+		items:    map[string]map[string]string{}}
+	for _, key := range numeric {
+		c.numeric[key] = struct{}{}
+	}
+	return c
+}
+
+func (c *collection) api(id string) map[string]any {
+	item := map[string]any{c.idKey: id}
+	for k, v := range c.items[id] {
+		if _, ok := c.hidden[k]; ok {
+			continue
+		}
+		item[k] = typed(k, v, c.numeric)
+	}
+	return item
+}
+
+func (c *collection) get(id string) (map[string]string, error) {
+	if item, ok := c.items[id]; ok {
+		return item, nil
+	}
+	return nil, errorf(500, "%s '%s' does not exist", c.kind, id)
+}
+
+// apply sets the parameters of the request on the object and removes the keys listed in `delete`.
+func (c *collection) apply(item map[string]string, r *request) {
+	for key := range r.params {
+		switch key {
+		case c.idKey, "delete", "digest":
+			continue
+		}
+		item[key] = r.get(key)
+	}
+	for _, key := range splitList(r.get("delete")) {
+		delete(item, key)
+	}
+}
+
+func (c *collection) check(id string, item map[string]string) error {
+	if c.validate == nil {
+		return nil
+	}
+	return c.validate(id, item)
+}
+
+// registerCollection registers the list, read, create, update and delete endpoints of a collection under path.
+func (s *Server) registerCollection(path string, c *collection) {
+	s.handle("GET", path, func(r *request) (any, error) {
+		list := []any{}
+		for _, id := range sortedKeys(c.items) {
+			list = append(list, c.api(id))
+		}
+		return list, nil
+	})
+	s.handle("GET", path+`/([^/]+)`, func(r *request) (any, error) {
+		if _, err := c.get(r.vars[0]); err != nil {
+			return nil, err
+		}
+		return c.api(r.vars[0]), nil
+	})
+	s.handle("POST", path, func(r *request) (any, error) {
+		id := r.get(c.idKey)
+		if id == "" && c.newID != nil {
+			id = c.newID()
+		}
+		if id == "" {
+			return nil, errorf(400, "missing %s", c.idKey)
+		}
+		if _, ok := c.items[id]; ok {
+			return nil, errorf(500, "%s '%s' already exists", c.kind, id)
+		}
+		item := make(map[string]string, len(c.defaults))
+		for k, v := range c.defaults {
+			item[k] = v
+		}
+		c.apply(item, r)
+		if err := c.check(id, item); err != nil {
+			return nil, err
+		}
+		c.items[id] = item
+		return nil, nil
+	})
+	s.handle("PUT", path+`/([^/]+)`, func(r *request) (any, error) {
+		item, err := c.get(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		for key := range c.fixed {
+			if r.has(key) {
+				return nil, errorf(400, "can't change value of fixed parameter '%s'", key)
+			}
+		}
+		updated := make(map[string]string, len(item))
+		for k, v := range item {
+			updated[k] = v
+		}
+		c.apply(updated, r)
+		if err := c.check(r.vars[0], updated); err != nil {
+			return nil, err
+		}
+		c.items[r.vars[0]] = updated
+		return nil, nil
+	})
+	s.handle("DELETE", path+`/([^/]+)`, func(r *request) (any, error) {
+		if _, err := c.get(r.vars[0]); err != nil {
+			return nil, err
+		}
+		if c.remove != nil {
+			if err := c.remove(r.vars[0]); err != nil {
+				return nil, err
+			}
+		}
+		delete(c.items, r.vars[0])
+		return nil, nil
+	})
+}
+
+// hasAny reports whether any of the keys is set to a non empty value.
+func hasAny(item map[string]string, keys ...string) bool {
+	for _, key := range keys {
+		if strings.TrimSpace(item[key]) != "" {
+			return true
+		}
+	}
+	return false
+}
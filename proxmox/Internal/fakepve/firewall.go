@@ -0,0 +1,562 @@
+package fakepve
+
+import (
+	"regexp"
+	"strconv"
+)
+
+var (
+	rxFirewallName  = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9\-_]+$`)
+	rxFirewallIface = regexp.MustCompile(`^net\d+$`)
+	firewallNumeric = map[string]struct{}{
+		"pos": {}, "enable": {}, "nomatch": {},
+		"dhcp": {}, "ipfilter": {}, "macfilter": {}, "ndp": {}, "radv": {}}
+	firewallActions   = []string{"ACCEPT", "DROP", "REJECT"}
+	firewallLogLevels = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug", "nolog"}
+)
+
+// firewall is the firewall config of a guest.
+type firewall struct {
+	options map[string]string
+	rules   []map[string]string
+	aliases map[string]map[string]string
+	ipsets  map[string]*ipset
+}
+
+type ipset struct {
+	comment string
+	// entries are kept in the order they were added, like PVE does.
+	entries []map[string]string
+}
+
+type firewallGroup struct {
+	comment string
+	rules   []map[string]string
+}
+
+// FirewallOptions returns the firewall options of a guest.
+func (s *Server) FirewallOptions(id int) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g, ok := s.guests[id]; ok && g.firewall != nil {
+		return copyConfig(g.firewall.options)
+	}
+	return map[string]string{}
+}
+
+// FirewallRules returns the firewall rules of a guest in their order.
+func (s *Server) FirewallRules(id int) []map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g, ok := s.guests[id]; ok && g.firewall != nil {
+		return copyRules(g.firewall.rules)
+	}
+	return nil
+}
+
+// FirewallAlias returns a firewall alias of a guest.
+func (s *Server) FirewallAlias(id int, name string) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g, ok := s.guests[id]; ok && g.firewall != nil {
+		alias, ok := g.firewall.aliases[name]
+		return copyConfig(alias), ok
+	}
+	return nil, false
+}
+
+// FirewallIPSet returns the comment and the entries of a firewall IP set of a guest.
+func (s *Server) FirewallIPSet(id int, name string) (string, []map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g, ok := s.guests[id]; ok && g.firewall != nil {
+		if set, ok := g.firewall.ipsets[name]; ok {
+			return set.comment, copyRules(set.entries), true
+		}
+	}
+	return "", nil, false
+}
+
+// FirewallGroup returns the comment and the rules of a security group.
+func (s *Server) FirewallGroup(name string) (string, []map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	group, ok := s.fwGroups[name]
+	if !ok {
+		return "", nil, false
+	}
+	return group.comment, copyRules(group.rules), true
+}
+
+func copyRules(rules []map[string]string) []map[string]string {
+	c := make([]map[string]string, len(rules))
+	for i, rule := range rules {
+		c[i] = copyConfig(rule)
+	}
+	return c
+}
+
+func (g *guest) fw() *firewall {
+	if g.firewall == nil {
+		g.firewall = &firewall{options: map[string]string{}, aliases: map[string]map[string]string{}, ipsets: map[string]*ipset{}}
+	}
+	return g.firewall
+}
+
+func firewallItem(config map[string]string) map[string]any {
+	item := map[string]any{"digest": "0"}
+	for k, v := range config {
+		item[k] = typed(k, v, firewallNumeric)
+	}
+	return item
+}
+
+func rulesAPI(rules []map[string]string) []any {
+	list := []any{}
+	for i, rule := range rules {
+		item := firewallItem(rule)
+		item["pos"] = i
+		list = append(list, item)
+	}
+	return list
+}
+
+// applyFirewallParams sets the parameters of a request on an object, skipping the keys that address the object.
+func applyFirewallParams(config map[string]string, r *request, skip ...string) {
+	for key := range r.params {
+		switch key {
+		case "delete", "digest", "pos", "moveto", "rename", "force":
+			continue
+		}
+		if contains(skip, key) {
+			continue
+		}
+		config[key] = r.get(key)
+	}
+	for _, key := range splitList(r.get("delete")) {
+		delete(config, key)
+	}
+}
+
+// checkRule validates a rule, inGroup is set for the rules of a security group, which cannot reference other groups.
+func (s *Server) checkRule(rule map[string]string, inGroup bool) error {
+	switch rule["type"] {
+	case "in", "out":
+		if !contains(firewallActions, rule["action"]) && rule["macro"] == "" {
+			return errorf(400, "invalid action '%s'", rule["action"])
+		}
+	case "group":
+		if inGroup {
+			return errorf(400, "security groups cannot contain group rules")
+		}
+		if _, ok := s.fwGroups[rule["action"]]; !ok {
+			return errorf(400, "security group '%s' does not exist", rule["action"])
+		}
+	default:
+		return errorf(400, "invalid rule type '%s'", rule["type"])
+	}
+	if rule["action"] == "" {
+		return errorf(400, "missing action")
+	}
+	if level, ok := rule["log"]; ok && !contains(firewallLogLevels, level) {
+		return errorf(400, "invalid log level '%s'", level)
+	}
+	if iface, ok := rule["iface"]; ok && !rxFirewallIface.MatchString(iface) {
+		return errorf(400, "invalid interface '%s'", iface)
+	}
+	return nil
+}
+
+func rulePos(rules []map[string]string, raw string) (int, error) {
+	pos, err := strconv.Atoi(raw)
+	if err != nil || pos >= len(rules) {
+		return 0, errorf(500, "no rule at position %s", raw)
+	}
+	return pos, nil
+}
+
+// handleRules registers the rule endpoints below base, rules returns the rules of the matched object.
+// Rules are added at the top unless a position is given, like PVE does.
+func (s *Server) handleRules(base, rulePath string, inGroup bool, rules func(r *request) (*[]map[string]string, error)) {
+	s.handle("GET", base, func(r *request) (any, error) {
+		list, err := rules(r)
+		if err != nil {
+			return nil, err
+		}
+		return rulesAPI(*list), nil
+	})
+	s.handle("POST", base, func(r *request) (any, error) {
+		list, err := rules(r)
+		if err != nil {
+			return nil, err
+		}
+		rule := map[string]string{}
+		applyFirewallParams(rule, r, "group")
+		if err = s.checkRule(rule, inGroup); err != nil {
+			return nil, err
+		}
+		pos := 0
+		if r.has("pos") {
+			pos = min(r.int("pos"), len(*list))
+		}
+		*list = append((*list)[:pos], append([]map[string]string{rule}, (*list)[pos:]...)...)
+		return nil, nil
+	})
+	s.handle("GET", base+rulePath, func(r *request) (any, error) {
+		list, err := rules(r)
+		if err != nil {
+			return nil, err
+		}
+		pos, err := rulePos(*list, r.vars[len(r.vars)-1])
+		if err != nil {
+			return nil, err
+		}
+		item := firewallItem((*list)[pos])
+		item["pos"] = pos
+		return item, nil
+	})
+	s.handle("PUT", base+rulePath, func(r *request) (any, error) {
+		list, err := rules(r)
+		if err != nil {
+			return nil, err
+		}
+		pos, err := rulePos(*list, r.vars[len(r.vars)-1])
+		if err != nil {
+			return nil, err
+		}
+		if r.has("moveto") {
+			rule := (*list)[pos]
+			*list = append((*list)[:pos], (*list)[pos+1:]...)
+			to := min(r.int("moveto"), len(*list))
+			*list = append((*list)[:to], append([]map[string]string{rule}, (*list)[to:]...)...)
+			return nil, nil
+		}
+		rule := copyConfig((*list)[pos])
+		applyFirewallParams(rule, r, "group")
+		if err = s.checkRule(rule, inGroup); err != nil {
+			return nil, err
+		}
+		(*list)[pos] = rule
+		return nil, nil
+	})
+	s.handle("DELETE", base+rulePath, func(r *request) (any, error) {
+		list, err := rules(r)
+		if err != nil {
+			return nil, err
+		}
+		pos, err := rulePos(*list, r.vars[len(r.vars)-1])
+		if err != nil {
+			return nil, err
+		}
+		*list = append((*list)[:pos], (*list)[pos+1:]...)
+		return nil, nil
+	})
+}
+
+func checkFirewallOptions(options map[string]string) error {
+	for _, key := range []string{"policy_in", "policy_out"} {
+		if v, ok := options[key]; ok && !contains(firewallActions, v) {
+			return errorf(400, "invalid %s '%s'", key, v)
+		}
+	}
+	for _, key := range []string{"log_level_in", "log_level_out"} {
+		if v, ok := options[key]; ok && !contains(firewallLogLevels, v) {
+			return errorf(400, "invalid %s '%s'", key, v)
+		}
+	}
+	return nil
+}
+
+func (s *Server) registerFirewall() {
+	s.fwGroups = map[string]*firewallGroup{}
+	const base = `/nodes/([^/]+)/(qemu|lxc)/(\d+)/firewall`
+	guestFirewall := func(r *request) (*firewall, error) {
+		g, err := s.guest(r.vars[0], r.vars[1], r.vars[2])
+		if err != nil {
+			return nil, err
+		}
+		return g.fw(), nil
+	}
+
+	s.handle("GET", base+`/options`, func(r *request) (any, error) {
+		fw, err := guestFirewall(r)
+		if err != nil {
+			return nil, err
+		}
+		return firewallItem(fw.options), nil
+	})
+	s.handle("PUT", base+`/options`, func(r *request) (any, error) {
+		fw, err := guestFirewall(r)
+		if err != nil {
+			return nil, err
+		}
+		options := copyConfig(fw.options)
+		applyFirewallParams(options, r)
+		if err = checkFirewallOptions(options); err != nil {
+			return nil, err
+		}
+		fw.options = options
+		return nil, nil
+	})
+
+	s.handleRules(base+`/rules`, `/(\d+)`, false, func(r *request) (*[]map[string]string, error) {
+		fw, err := guestFirewall(r)
+		if err != nil {
+			return nil, err
+		}
+		return &fw.rules, nil
+	})
+
+	s.handle("GET", base+`/aliases`, func(r *request) (any, error) {
+		fw, err := guestFirewall(r)
+		if err != nil {
+			return nil, err
+		}
+		list := []any{}
+		for _, name := range sortedKeys(fw.aliases) {
+			list = append(list, firewallItem(fw.aliases[name]))
+		}
+		return list, nil
+	})
+	s.handle("POST", base+`/aliases`, func(r *request) (any, error) {
+		fw, err := guestFirewall(r)
+		if err != nil {
+			return nil, err
+		}
+		name := r.get("name")
+		if !rxFirewallName.MatchString(name) {
+			return nil, errorf(400, "invalid alias name '%s'", name)
+		}
+		if _, ok := fw.aliases[name]; ok {
+			return nil, errorf(500, "alias '%s' already exists", name)
+		}
+		if r.get("cidr") == "" {
+			return nil, errorf(400, "missing cidr")
+		}
+		alias := map[string]string{}
+		applyFirewallParams(alias, r)
+		fw.aliases[name] = alias
+		return nil, nil
+	})
+	s.handle("GET", base+`/aliases/([^/]+)`, func(r *request) (any, error) {
+		fw, err := guestFirewall(r)
+		if err != nil {
+			return nil, err
+		}
+		alias, ok := fw.aliases[r.vars[3]]
+		if !ok {
+			return nil, errorf(500, "no such alias '%s'", r.vars[3])
+		}
+		return firewallItem(alias), nil
+	})
+	s.handle("PUT", base+`/aliases/([^/]+)`, func(r *request) (any, error) {
+		fw, err := guestFirewall(r)
+		if err != nil {
+			return nil, err
+		}
+		alias, ok := fw.aliases[r.vars[3]]
+		if !ok {
+			return nil, errorf(500, "no such alias '%s'", r.vars[3])
+		}
+		// The comment is cleared when it is not given, like PVE does.
+		delete(alias, "comment")
+		applyFirewallParams(alias, r)
+		return nil, nil
+	})
+	s.handle("DELETE", base+`/aliases/([^/]+)`, func(r *request) (any, error) {
+		fw, err := guestFirewall(r)
+		if err != nil {
+			return nil, err
+		}
+		delete(fw.aliases, r.vars[3])
+		return nil, nil
+	})
+
+	s.handle("GET", base+`/ipset`, func(r *request) (any, error) {
+		fw, err := guestFirewall(r)
+		if err != nil {
+			return nil, err
+		}
+		list := []any{}
+		for _, name := range sortedKeys(fw.ipsets) {
+			item := map[string]any{"name": name, "digest": "0"}
+			if comment := fw.ipsets[name].comment; comment != "" {
+				item["comment"] = comment
+			}
+			list = append(list, item)
+		}
+		return list, nil
+	})
+	s.handle("POST", base+`/ipset`, func(r *request) (any, error) {
+		fw, err := guestFirewall(r)
+		if err != nil {
+			return nil, err
+		}
+		name := r.get("name")
+		if rename := r.get("rename"); rename != "" {
+			set, ok := fw.ipsets[rename]
+			if !ok {
+				return nil, errorf(500, "IPSet '%s' does not exist", rename)
+			}
+			// PVE keeps the comment when the rename does not contain one.
+			if r.has("comment") {
+				set.comment = r.get("comment")
+			}
+			delete(fw.ipsets, rename)
+			fw.ipsets[name] = set
+			return nil, nil
+		}
+		if !rxFirewallName.MatchString(name) {
+			return nil, errorf(400, "invalid IPSet name '%s'", name)
+		}
+		if _, ok := fw.ipsets[name]; ok {
+			return nil, errorf(500, "IPSet '%s' already exists", name)
+		}
+		fw.ipsets[name] = &ipset{comment: r.get("comment")}
+		return nil, nil
+	})
+	ipsetOf := func(r *request) (*ipset, error) {
+		fw, err := guestFirewall(r)
+		if err != nil {
+			return nil, err
+		}
+		set, ok := fw.ipsets[r.vars[3]]
+		if !ok {
+			return nil, errorf(500, "no such IPSet '%s'", r.vars[3])
+		}
+		return set, nil
+	}
+	s.handle("GET", base+`/ipset/([^/]+)`, func(r *request) (any, error) {
+		set, err := ipsetOf(r)
+		if err != nil {
+			return nil, err
+		}
+		list := []any{}
+		for _, entry := range set.entries {
+			list = append(list, firewallItem(entry))
+		}
+		return list, nil
+	})
+	s.handle("POST", base+`/ipset/([^/]+)`, func(r *request) (any, error) {
+		set, err := ipsetOf(r)
+		if err != nil {
+			return nil, err
+		}
+		cidr := r.get("cidr")
+		if cidr == "" {
+			return nil, errorf(400, "missing cidr")
+		}
+		for _, entry := range set.entries {
+			if entry["cidr"] == cidr {
+				return nil, errorf(500, "entry '%s' already exists", cidr)
+			}
+		}
+		entry := map[string]string{}
+		applyFirewallParams(entry, r)
+		set.entries = append(set.entries, entry)
+		return nil, nil
+	})
+	s.handle("DELETE", base+`/ipset/([^/]+)`, func(r *request) (any, error) {
+		fw, err := guestFirewall(r)
+		if err != nil {
+			return nil, err
+		}
+		if set, ok := fw.ipsets[r.vars[3]]; ok && len(set.entries) > 0 && r.get("force") != "1" {
+			return nil, errorf(500, "IPSet '%s' is not empty", r.vars[3])
+		}
+		delete(fw.ipsets, r.vars[3])
+		return nil, nil
+	})
+	// The CIDR of an entry contains a slash, so it matches the rest of the path.
+	s.handle("PUT", base+`/ipset/([^/]+)/(.+)`, func(r *request) (any, error) {
+		set, err := ipsetOf(r)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range set.entries {
+			if entry["cidr"] == r.vars[4] {
+				delete(entry, "comment")
+				delete(entry, "nomatch")
+				applyFirewallParams(entry, r)
+				return nil, nil
+			}
+		}
+		return nil, errorf(500, "no such IPSet entry '%s'", r.vars[4])
+	})
+	s.handle("DELETE", base+`/ipset/([^/]+)/(.+)`, func(r *request) (any, error) {
+		set, err := ipsetOf(r)
+		if err != nil {
+			return nil, err
+		}
+		for i, entry := range set.entries {
+			if entry["cidr"] == r.vars[4] {
+				set.entries = append(set.entries[:i], set.entries[i+1:]...)
+				break
+			}
+		}
+		return nil, nil
+	})
+
+	s.handle("GET", `/cluster/firewall/groups`, func(r *request) (any, error) {
+		list := []any{}
+		for _, name := range sortedKeys(s.fwGroups) {
+			item := map[string]any{"group": name, "digest": "0"}
+			if comment := s.fwGroups[name].comment; comment != "" {
+				item["comment"] = comment
+			}
+			list = append(list, item)
+		}
+		return list, nil
+	})
+	s.handle("POST", `/cluster/firewall/groups`, func(r *request) (any, error) {
+		name := r.get("group")
+		if rename := r.get("rename"); rename != "" {
+			group, ok := s.fwGroups[rename]
+			if !ok {
+				return nil, errorf(500, "security group '%s' does not exist", rename)
+			}
+			if r.has("comment") {
+				group.comment = r.get("comment")
+			}
+			delete(s.fwGroups, rename)
+			s.fwGroups[name] = group
+			return nil, nil
+		}
+		if !rxFirewallName.MatchString(name) || len(name) > 18 {
+			return nil, errorf(400, "invalid security group name '%s'", name)
+		}
+		if _, ok := s.fwGroups[name]; ok {
+			return nil, errorf(500, "security group '%s' already exists", name)
+		}
+		s.fwGroups[name] = &firewallGroup{comment: r.get("comment")}
+		return nil, nil
+	})
+	s.handle("DELETE", `/cluster/firewall/groups/([^/]+)`, func(r *request) (any, error) {
+		group, ok := s.fwGroups[r.vars[0]]
+		if !ok {
+			return nil, nil
+		}
+		if len(group.rules) > 0 {
+			return nil, errorf(500, "security group '%s' is not empty", r.vars[0])
+		}
+		for _, g := range s.guests {
+			if g.firewall == nil {
+				continue
+			}
+			for _, rule := range g.firewall.rules {
+				if rule["type"] == "group" && rule["action"] == r.vars[0] {
+					return nil, errorf(500, "security group '%s' is used by guest %d", r.vars[0], g.id)
+				}
+			}
+		}
+		delete(s.fwGroups, r.vars[0])
+		return nil, nil
+	})
+	s.handleRules(`/cluster/firewall/groups/([^/]+)`, `/(\d+)`, true, func(r *request) (*[]map[string]string, error) {
+		group, ok := s.fwGroups[r.vars[0]]
+		if !ok {
+			return nil, errorf(500, "no such security group '%s'", r.vars[0])
+		}
+		return &group.rules, nil
+	})
+}
@@ -0,0 +1,687 @@
+package fakepve
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	guestQemu = "qemu"
+	guestLxc  = "lxc"
+)
+
+// numericQemu are the qemu config keys PVE returns as JSON numbers.
+var numericQemu = map[string]struct{}{
+	"acpi": {}, "balloon": {}, "ciupgrade": {}, "cores": {}, "cpuunits": {}, "freeze": {}, "kvm": {}, "localtime": {},
+	"memory": {}, "numa": {}, "onboot": {}, "protection": {}, "reboot": {}, "shares": {}, "sockets": {},
+	"tablet": {}, "template": {}, "vcpus": {}, "vmgenid": {}}
+
+// numericLxc are the lxc config keys PVE returns as JSON numbers.
+var numericLxc = map[string]struct{}{
+	"console": {}, "cores": {}, "cpuunits": {}, "memory": {}, "onboot": {}, "protection": {}, "swap": {},
+	"template": {}, "tty": {}, "unprivileged": {}}
+
+// newDisk matches a disk definition that asks PVE to allocate a new volume, e.g. "local-lvm:10,format=raw".
+var newDisk = regexp.MustCompile(`^([^:,]+):(\d+(?:\.\d+)?)(,.*)?$`)
+
+// macAddress matches the MAC address in a network device definition.
+var macAddress = regexp.MustCompile(`([0-9A-Fa-f]{2}:){5}[0-9A-Fa-f]{2}`)
+
+// diskKey matches the config keys that hold a volume.
+var diskKey = regexp.MustCompile(`^(ide|sata|scsi|virtio|efidisk|tpmstate|unused|rootfs|mp)\d*$`)
+
+type guest struct {
+	id        int
+	node      string
+	guestType string
+	config    map[string]string
+	status    string
+	haState   string
+	pool      string
+	diskSeq   int
+	started   time.Time
+	snapshots map[string]*snapshot
+	parent    string
+	rollbacks int
+	// migrations holds the parameters of every migrate request.
+	migrations []map[string]string
+	// resizes holds the disk and size of every resize request, like "scsi0=10G".
+	resizes  []string
+	firewall *firewall
+}
+
+// AddGuest adds a stopped guest with the given config to the fake cluster, disk definitions like "local-lvm:10" are allocated.
+// The guest type is either "qemu" or "lxc".
+func (s *Server) AddGuest(node, guestType string, id int, config map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g := &guest{id: id, node: node, guestType: guestType, config: map[string]string{}, status: "stopped"}
+	for k, v := range config {
+		g.config[k] = s.allocate(g, k, v)
+	}
+	s.guests[id] = g
+}
+
+// SetGuestStatus sets the status of a guest, e.g. "running" or "stopped", as if it was changed out of band.
+func (s *Server) SetGuestStatus(id int, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g, ok := s.guests[id]; ok {
+		if g.status != "running" && status == "running" {
+			g.started = time.Now()
+		}
+		g.status = status
+	}
+}
+
+func (g *guest) numeric() map[string]struct{} {
+	if g.guestType == guestLxc {
+		return numericLxc
+	}
+	return numericQemu
+}
+
+func (g *guest) name() string {
+	if g.guestType == guestLxc {
+		return g.config["hostname"]
+	}
+	return g.config["name"]
+}
+
+func (g *guest) template() bool { return g.config["template"] == "1" }
+
+func (g *guest) apiConfig() map[string]any {
+	config := make(map[string]any, len(g.config)+1)
+	for k, v := range g.config {
+		config[k] = typed(k, v, g.numeric())
+	}
+	config["digest"] = fmt.Sprintf("%040x", len(g.config))
+	return config
+}
+
+func (g *guest) resource() map[string]any {
+	res := map[string]any{
+		"id":       g.guestType + "/" + strconv.Itoa(g.id),
+		"vmid":     g.id,
+		"type":     g.guestType,
+		"node":     g.node,
+		"name":     g.name(),
+		"status":   g.status,
+		"template": 0,
+		"maxcpu":   1,
+		"maxmem":   512 * 1024 * 1024,
+		"maxdisk":  0,
+		"uptime":   g.uptime()}
+	if g.template() {
+		res["template"] = 1
+	}
+	if v, ok := g.config["tags"]; ok {
+		res["tags"] = v
+	}
+	if g.pool != "" {
+		res["pool"] = g.pool
+	}
+	if g.haState != "" {
+		res["hastate"] = g.haState
+	}
+	if v, ok := g.config["lock"]; ok {
+		res["lock"] = v
+	}
+	return res
+}
+
+func (g *guest) uptime() int64 {
+	if g.status != "running" {
+		return 0
+	}
+	return int64(time.Since(g.started).Seconds())
+}
+
+// allocate turns disk definitions like "local-lvm:10" into volumes, the way PVE does on create and update.
+func (s *Server) allocate(g *guest, key, value string) string {
+	if !diskKey.MatchString(key) {
+		return value
+	}
+	if strings.HasSuffix(value, ":cloudinit") || strings.Contains(value, ":cloudinit,") {
+		storage := value[:strings.Index(value, ":")]
+		return fmt.Sprintf("%s:vm-%d-cloudinit,media=cdrom", storage, g.id)
+	}
+	m := newDisk.FindStringSubmatch(value)
+	if m == nil {
+		return value
+	}
+	prefix := "vm"
+	if g.guestType == guestLxc {
+		prefix = "subvol"
+	}
+	volume := fmt.Sprintf("%s-%d-disk-%d", prefix, g.id, g.diskSeq)
+	g.diskSeq++
+	gib, _ := strconv.ParseFloat(m[2], 64)
+	size := formatSize(int64(gib * (1 << 30)))
+	if strings.HasPrefix(key, "efidisk") || strings.HasPrefix(key, "tpmstate") {
+		size = "4M"
+	}
+	options := m[3]
+	if strings.Contains(options, "import-from=") {
+		parts := []string{}
+		for _, o := range strings.Split(strings.TrimPrefix(options, ","), ",") {
+			if source, ok := strings.CutPrefix(o, "import-from="); ok {
+				// The size of an imported disk is the size of the image.
+				if v := s.findVolume(g.node, source); v != nil {
+					size = formatSize(v.size)
+				}
+				continue
+			}
+			parts = append(parts, o)
+		}
+		options = ""
+		if len(parts) > 0 {
+			options = "," + strings.Join(parts, ",")
+		}
+	}
+	if st, ok := s.storages[m[1]]; ok {
+		st.addVolume(g.node, volume, contentVolume(g.guestType), g.id, size)
+	}
+	return m[1] + ":" + volume + options + ",size=" + size
+}
+
+func contentVolume(guestType string) string {
+	if guestType == guestLxc {
+		return "rootdir"
+	}
+	return "images"
+}
+
+// applyConfig applies a create or update request to the guest config.
+func (s *Server) applyConfig(g *guest, r *request) {
+	ignore := map[string]struct{}{
+		"vmid": {}, "node": {}, "delete": {}, "digest": {}, "start": {}, "pool": {}, "ostemplate": {},
+		"password": {}, "ssh-public-keys": {}, "storage": {}, "unique": {}, "skiplock": {}, "revert": {},
+		"background_delay": {}, "restore": {}, "force": {}, "archive": {}, "bwlimit": {}, "live-restore": {}}
+	for key := range r.params {
+		if _, ok := ignore[key]; ok {
+			continue
+		}
+		g.config[key] = s.configValue(g, key, r.get(key))
+	}
+	for _, key := range strings.Split(r.get("delete"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			s.release(g, g.config[key])
+			delete(g.config, key)
+		}
+	}
+}
+
+// configValue returns the value PVE stores for a config key: new disks are allocated, the size of existing volumes is kept
+// and network devices without a MAC address get one generated.
+func (s *Server) configValue(g *guest, key, value string) string {
+	switch {
+	case diskKey.MatchString(key):
+		volume, _, _ := strings.Cut(value, ",")
+		if current, options, ok := strings.Cut(g.config[key], ","); ok && current == volume && !strings.Contains(value, "size=") {
+			if i := strings.Index(options, "size="); i >= 0 {
+				size, _, _ := strings.Cut(options[i:], ",")
+				return value + "," + size
+			}
+		}
+		return s.allocate(g, key, value)
+	case g.guestType == guestQemu && strings.HasPrefix(key, "net") && !macAddress.MatchString(value):
+		index, _ := strconv.Atoi(strings.TrimPrefix(key, "net"))
+		model, options, _ := strings.Cut(value, ",")
+		return model + "=" + newMAC(g.id, index) + "," + options
+	}
+	return value
+}
+
+// newMAC returns a MAC address in the Proxmox range that is unique for the network device of the guest.
+func newMAC(id, index int) string {
+	return fmt.Sprintf("BC:24:11:%02X:%02X:%02X", byte(id>>8), byte(id), byte(index))
+}
+
+// release removes the volume referenced by a disk definition from its storage.
+func (s *Server) release(g *guest, value string) {
+	storage, rest, ok := strings.Cut(value, ":")
+	if !ok {
+		return
+	}
+	volume, _, _ := strings.Cut(rest, ",")
+	if st, ok := s.storages[storage]; ok && strings.Contains(volume, "-"+strconv.Itoa(g.id)+"-") {
+		st.removeVolume(g.node, storage+":"+volume)
+	}
+}
+
+func (s *Server) guest(node, guestType, rawID string) (*guest, error) {
+	id, _ := strconv.Atoi(rawID)
+	g, ok := s.guests[id]
+	if !ok || g.guestType != guestType {
+		if guestType == guestLxc {
+			return nil, errorf(500, "Configuration file 'nodes/%s/lxc/%d.conf' does not exist", node, id)
+		}
+		return nil, errorf(500, "Configuration file 'nodes/%s/qemu-server/%d.conf' does not exist", node, id)
+	}
+	if g.node != node {
+		return nil, errorf(500, "VM %d is on node '%s', not on '%s'", id, g.node, node)
+	}
+	return g, nil
+}
+
+func (s *Server) createGuest(node, guestType string, r *request) (*guest, error) {
+	if _, err := s.node(node); err != nil {
+		return nil, err
+	}
+	id := r.int("vmid")
+	if _, ok := s.guests[id]; ok {
+		return nil, errorf(500, "unable to create VM %d - VM %d already exists", id, id)
+	}
+	g := &guest{id: id, node: node, guestType: guestType, config: map[string]string{}, status: "stopped"}
+	s.applyConfig(g, r)
+	s.guests[id] = g
+	if pool := r.get("pool"); pool != "" {
+		if p, ok := s.pools[pool]; ok {
+			p.add(g)
+		}
+	}
+	if r.get("start") == "1" {
+		g.status, g.started = "running", time.Now()
+	}
+	return g, nil
+}
+
+func (s *Server) deleteGuest(g *guest) {
+	for key, value := range g.config {
+		if diskKey.MatchString(key) {
+			s.release(g, value)
+		}
+	}
+	if p, ok := s.pools[g.pool]; ok {
+		p.remove(g.id)
+	}
+	delete(s.ha, g.id)
+	delete(s.guests, g.id)
+}
+
+func (s *Server) registerGuests() {
+	const guestPath = `/nodes/([^/]+)/(qemu|lxc)`
+	const idPath = guestPath + `/(\d+)`
+	s.handle("GET", guestPath, func(r *request) (any, error) {
+		list := []any{}
+		for _, id := range s.guestIDs() {
+			if g := s.guests[id]; g.node == r.vars[0] && g.guestType == r.vars[1] {
+				list = append(list, g.resource())
+			}
+		}
+		return list, nil
+	})
+	s.handle("POST", guestPath, func(r *request) (any, error) {
+		if r.has("archive") {
+			return s.restoreGuest(r.vars[0], r.vars[1], r)
+		}
+		g, err := s.createGuest(r.vars[0], r.vars[1], r)
+		if err != nil {
+			return nil, err
+		}
+		return s.newTask(g.node, map[string]string{guestQemu: "qmcreate", guestLxc: "vzcreate"}[g.guestType], strconv.Itoa(g.id)), nil
+	})
+	s.handle("GET", idPath+`/config`, func(r *request) (any, error) {
+		g, err := s.guest(r.vars[0], r.vars[1], r.vars[2])
+		if err != nil {
+			return nil, err
+		}
+		return g.apiConfig(), nil
+	})
+	s.handle("GET", idPath+`/pending`, func(r *request) (any, error) {
+		g, err := s.guest(r.vars[0], r.vars[1], r.vars[2])
+		if err != nil {
+			return nil, err
+		}
+		list := []any{}
+		for _, k := range sortedKeys(g.config) {
+			list = append(list, map[string]any{"key": k, "value": typed(k, g.config[k], g.numeric())})
+		}
+		return list, nil
+	})
+	update := func(r *request) (any, error) {
+		g, err := s.guest(r.vars[0], r.vars[1], r.vars[2])
+		if err != nil {
+			return nil, err
+		}
+		if g.template() && !r.has("template") {
+			for key := range r.params {
+				if diskKey.MatchString(key) {
+					return nil, errorf(500, "unable to modify disks of a template")
+				}
+			}
+		}
+		s.applyConfig(g, r)
+		if r.Method == "POST" {
+			return s.newTask(g.node, "qmconfig", strconv.Itoa(g.id)), nil
+		}
+		return nil, nil
+	}
+	s.handle("PUT", idPath+`/config`, update)
+	s.handle("POST", idPath+`/config`, update)
+	s.handle("GET", idPath+`/status/current`, func(r *request) (any, error) {
+		g, err := s.guest(r.vars[0], r.vars[1], r.vars[2])
+		if err != nil {
+			return nil, err
+		}
+		status := map[string]any{
+			"vmid":   g.id,
+			"name":   g.name(),
+			"status": g.status,
+			"uptime": g.uptime(),
+			"maxmem": 512 * 1024 * 1024,
+			"cpus":   1}
+		if g.haState != "" {
+			status["ha"] = map[string]any{"managed": 1, "state": g.haState}
+		} else {
+			status["ha"] = map[string]any{"managed": 0}
+		}
+		if g.guestType == guestQemu && g.config["agent"] != "" && !strings.HasPrefix(g.config["agent"], "0") {
+			status["agent"] = 1
+		}
+		return status, nil
+	})
+	s.handle("POST", idPath+`/status/(start|stop|shutdown|reboot|reset|suspend|resume)`, func(r *request) (any, error) {
+		g, err := s.guest(r.vars[0], r.vars[1], r.vars[2])
+		if err != nil {
+			return nil, err
+		}
+		if g.template() {
+			return nil, errorf(500, "you can't start a vm if it's a template")
+		}
+		upid := s.newTask(g.node, map[string]string{guestQemu: "qm", guestLxc: "vz"}[g.guestType]+r.vars[3], strconv.Itoa(g.id))
+		if s.taskFailed(upid) {
+			return upid, nil
+		}
+		switch r.vars[3] {
+		case "start", "reboot", "reset", "resume":
+			if g.status != "running" || r.vars[3] != "resume" {
+				g.started = time.Now()
+			}
+			g.status = "running"
+		case "stop", "shutdown":
+			g.status = "stopped"
+		case "suspend":
+			g.status = "paused"
+		}
+		return upid, nil
+	})
+	s.handle("DELETE", idPath, func(r *request) (any, error) {
+		g, err := s.guest(r.vars[0], r.vars[1], r.vars[2])
+		if err != nil {
+			return nil, err
+		}
+		if g.status == "running" {
+			return nil, errorf(500, "VM %d is running - destroy failed", g.id)
+		}
+		if g.config["protection"] == "1" {
+			return nil, errorf(500, "can't remove VM %d - protection mode enabled", g.id)
+		}
+		upid := s.newTask(g.node, map[string]string{guestQemu: "qmdestroy", guestLxc: "vzdestroy"}[g.guestType], strconv.Itoa(g.id))
+		if !s.taskFailed(upid) {
+			s.deleteGuest(g)
+		}
+		return upid, nil
+	})
+	s.handle("POST", idPath+`/clone`, func(r *request) (any, error) {
+		source, err := s.guest(r.vars[0], r.vars[1], r.vars[2])
+		if err != nil {
+			return nil, err
+		}
+		newID := r.int("newid")
+		if _, ok := s.guests[newID]; ok {
+			return nil, errorf(500, "unable to create VM %d - VM %d already exists", newID, newID)
+		}
+		target := source.node
+		if t := r.get("target"); t != "" {
+			if _, err := s.node(t); err != nil {
+				return nil, err
+			}
+			target = t
+		}
+		g := &guest{id: newID, node: target, guestType: source.guestType, config: map[string]string{}, status: "stopped"}
+		for k, v := range source.config {
+			switch {
+			case k == "template" || k == "lock":
+			case diskKey.MatchString(k) && !newDisk.MatchString(v) && strings.Contains(v, fmt.Sprintf("-%d-", source.id)):
+				storage, rest, _ := strings.Cut(v, ":")
+				if st := r.get("storage"); st != "" {
+					storage = st
+				}
+				_, options, _ := strings.Cut(rest, ",")
+				g.config[k] = s.cloneVolume(g, storage, k, options)
+			default:
+				g.config[k] = v
+			}
+		}
+		if name := r.get("name"); name != "" {
+			g.config["name"] = name
+		}
+		if hostname := r.get("hostname"); hostname != "" {
+			g.config["hostname"] = hostname
+		}
+		if description, ok := r.params["description"]; ok {
+			g.config["description"] = description[0]
+		}
+		s.guests[newID] = g
+		if p, ok := s.pools[r.get("pool")]; ok {
+			p.add(g)
+		}
+		return s.newTask(source.node, map[string]string{guestQemu: "qmclone", guestLxc: "vzclone"}[g.guestType], strconv.Itoa(source.id)), nil
+	})
+	s.handle("POST", idPath+`/template`, func(r *request) (any, error) {
+		g, err := s.guest(r.vars[0], r.vars[1], r.vars[2])
+		if err != nil {
+			return nil, err
+		}
+		if g.status == "running" {
+			return nil, errorf(500, "you can't convert a running VM to a template")
+		}
+		g.config["template"] = "1"
+		if g.guestType == guestLxc {
+			return nil, nil
+		}
+		return s.newTask(g.node, "qmtemplate", strconv.Itoa(g.id)), nil
+	})
+	s.handle("POST", idPath+`/migrate`, func(r *request) (any, error) {
+		g, err := s.guest(r.vars[0], r.vars[1], r.vars[2])
+		if err != nil {
+			return nil, err
+		}
+		target, err := s.node(r.get("target"))
+		if err != nil {
+			return nil, err
+		}
+		taskType := map[string]string{guestQemu: "qmigrate", guestLxc: "vzmigrate"}[g.guestType]
+		if g.status == "running" && g.guestType == guestQemu && r.get("online") != "1" {
+			return nil, errorf(500, "can't migrate running VM without --online")
+		}
+		if g.status == "running" && g.guestType == guestLxc && r.get("restart") != "1" {
+			return nil, errorf(500, "CT is running - use online migration")
+		}
+		params := map[string]string{}
+		for k := range r.params {
+			params[k] = r.get(k)
+		}
+		g.migrations = append(g.migrations, params)
+		targetStorage := r.get("targetstorage")
+		if g.guestType == guestLxc {
+			targetStorage = r.get("target-storage")
+		}
+		log := []string{fmt.Sprintf("starting migration of %s %d to node '%s'", strings.ToUpper(g.guestType[:1])+g.guestType[1:], g.id, target.name)}
+		if g.guestType == guestQemu && r.get("with-local-disks") != "1" {
+			if disk := s.localDisk(g); disk != "" {
+				msg := fmt.Sprintf("migration aborted (duration 00:00:01): can't migrate local disk '%s': can't live migrate attached local disks without with-local-disks option", disk)
+				s.failures[taskType] = msg
+				return s.newTask(g.node, taskType, strconv.Itoa(g.id), append(log, "ERROR: Problem found while scanning volumes - can't migrate local disk '"+disk+"'")...), nil
+			}
+		}
+		upid := s.newTask(g.node, taskType, strconv.Itoa(g.id), log...)
+		if !s.taskFailed(upid) {
+			s.moveGuest(g, target.name, targetStorage)
+		}
+		return upid, nil
+	})
+	s.handle("PUT", idPath+`/resize`, func(r *request) (any, error) {
+		g, err := s.guest(r.vars[0], r.vars[1], r.vars[2])
+		if err != nil {
+			return nil, err
+		}
+		disk := r.get("disk")
+		value, ok := g.config[disk]
+		if !ok {
+			return nil, errorf(500, "disk '%s' does not exist", disk)
+		}
+		if g.template() {
+			return nil, errorf(500, "you can't resize a disk of a template")
+		}
+		size := r.get("size")
+		g.resizes = append(g.resizes, disk+"="+size)
+		current := int64(0)
+		for _, o := range strings.Split(value, ",") {
+			if v, ok := strings.CutPrefix(o, "size="); ok {
+				current = parseSize(v)
+			}
+		}
+		if grow, ok := strings.CutPrefix(size, "+"); ok {
+			size = formatSize(current + parseSize(grow))
+		} else if parseSize(size) < current {
+			return nil, errorf(500, "shrinking disks is not supported")
+		}
+		parts := []string{}
+		for _, o := range strings.Split(value, ",") {
+			if !strings.HasPrefix(o, "size=") {
+				parts = append(parts, o)
+			}
+		}
+		g.config[disk] = strings.Join(append(parts, "size="+size), ",")
+		if g.guestType == guestLxc {
+			return s.newTask(g.node, "resize", strconv.Itoa(g.id)), nil
+		}
+		return nil, nil
+	})
+	s.handle("POST", idPath+`/(?:move_disk|move_volume)`, func(r *request) (any, error) {
+		g, err := s.guest(r.vars[0], r.vars[1], r.vars[2])
+		if err != nil {
+			return nil, err
+		}
+		disk := r.get("disk")
+		if disk == "" {
+			disk = r.get("volume")
+		}
+		value, ok := g.config[disk]
+		if !ok {
+			return nil, errorf(500, "disk '%s' does not exist", disk)
+		}
+		_, rest, _ := strings.Cut(value, ":")
+		_, options, _ := strings.Cut(rest, ",")
+		s.release(g, value)
+		g.config[disk] = s.cloneVolume(g, r.get("storage"), disk, options)
+		return s.newTask(g.node, "qmmove", strconv.Itoa(g.id)), nil
+	})
+	s.handle("GET", idPath+`/feature`, func(r *request) (any, error) {
+		if _, err := s.guest(r.vars[0], r.vars[1], r.vars[2]); err != nil {
+			return nil, err
+		}
+		return map[string]any{"hasFeature": 1, "nodes": sortedKeys(s.nodes)}, nil
+	})
+	s.handle("GET", `/nodes/([^/]+)/qemu/(\d+)/agent/([^/]+)`, func(r *request) (any, error) {
+		g, err := s.guest(r.vars[0], guestQemu, r.vars[1])
+		if err != nil {
+			return nil, err
+		}
+		if g.status != "running" {
+			return nil, errorf(500, "VM %d is not running", g.id)
+		}
+		return nil, errorf(500, "QEMU guest agent is not running")
+	})
+}
+
+// cloneVolume allocates a copy of a volume for the guest on the given storage.
+func (s *Server) cloneVolume(g *guest, storage, key, options string) string {
+	size := "0"
+	for _, o := range strings.Split(options, ",") {
+		if strings.HasPrefix(o, "size=") {
+			size = strings.TrimSuffix(strings.TrimPrefix(o, "size="), "G")
+		}
+	}
+	value := s.allocate(g, key, storage+":"+size)
+	for _, o := range strings.Split(options, ",") {
+		if o != "" && !strings.HasPrefix(o, "size=") {
+			value = strings.Replace(value, ",size=", ","+o+",size=", 1)
+		}
+	}
+	return value
+}
+
+// moveGuest moves a guest and its local volumes to another node.
+// localDisk returns the first disk of the guest that is on a storage that is not shared between the nodes.
+func (s *Server) localDisk(g *guest) string {
+	for _, key := range sortedKeys(g.config) {
+		if !diskKey.MatchString(key) {
+			continue
+		}
+		storage, rest, ok := strings.Cut(g.config[key], ":")
+		if st, exists := s.storages[storage]; ok && exists && !st.shared {
+			volume, _, _ := strings.Cut(rest, ",")
+			return storage + ":" + volume
+		}
+	}
+	return ""
+}
+
+// Migrations returns the parameters of the migrate requests of the guest.
+func (s *Server) Migrations(id int) []map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g, ok := s.guests[id]; ok {
+		return append([]map[string]string(nil), g.migrations...)
+	}
+	return nil
+}
+
+// Resizes returns the disk and size of the resize requests of the guest, like "scsi0=10G".
+func (s *Server) Resizes(id int) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g, ok := s.guests[id]; ok {
+		return append([]string(nil), g.resizes...)
+	}
+	return nil
+}
+
+func (s *Server) moveGuest(g *guest, target, targetStorage string) {
+	for key, value := range g.config {
+		if !diskKey.MatchString(key) {
+			continue
+		}
+		storage, rest, ok := strings.Cut(value, ":")
+		st, exists := s.storages[storage]
+		if !ok || !exists || st.shared {
+			continue
+		}
+		volume, options, _ := strings.Cut(rest, ",")
+		st.removeVolume(g.node, storage+":"+volume)
+		if targetStorage != "" {
+			storage = targetStorage
+		}
+		if dst, ok := s.storages[storage]; ok {
+			size := ""
+			for _, o := range strings.Split(options, ",") {
+				if strings.HasPrefix(o, "size=") {
+					size = strings.TrimPrefix(o, "size=")
+				}
+			}
+			dst.addVolume(target, volume, contentVolume(g.guestType), g.id, size)
+		}
+		g.config[key] = storage + ":" + volume
+		if options != "" {
+			g.config[key] += "," + options
+		}
+	}
+	g.node = target
+}
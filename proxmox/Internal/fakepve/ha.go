@@ -0,0 +1,91 @@
+package fakepve
+
+import (
+	"strconv"
+	"strings"
+)
+
+func (s *Server) registerHA() {
+	groups := newCollection("HA group", "group", "restricted", "nofailback")
+	groups.defaults = map[string]string{"type": "group"}
+	groups.validate = func(id string, group map[string]string) error {
+		if group["nodes"] == "" {
+			return errorf(400, "parameter verification failed: nodes: property is missing and it is not optional")
+		}
+		return s.checkHaNodes(group["nodes"])
+	}
+	s.haGroups = groups
+	s.registerCollection(`/cluster/ha/groups`, groups)
+
+	rules := newCollection("HA rule", "rule", "strict", "disable")
+	rules.validate = func(id string, rule map[string]string) error {
+		switch rule["type"] {
+		case "node-affinity":
+			if rule["nodes"] == "" {
+				return errorf(400, "parameter verification failed: nodes: property is missing and it is not optional")
+			}
+			if err := s.checkHaNodes(rule["nodes"]); err != nil {
+				return err
+			}
+		case "resource-affinity":
+			if rule["affinity"] != "positive" && rule["affinity"] != "negative" {
+				return errorf(400, "parameter verification failed: affinity: value '%s' does not have a value in the enumeration 'positive, negative'", rule["affinity"])
+			}
+			if len(splitList(rule["resources"])) < 2 {
+				return errorf(400, "resource affinity rule '%s' needs at least two resources", id)
+			}
+		default:
+			return errorf(400, "parameter verification failed: type: value '%s' does not have a value in the enumeration 'node-affinity, resource-affinity'", rule["type"])
+		}
+		if rule["resources"] == "" {
+			return errorf(400, "parameter verification failed: resources: property is missing and it is not optional")
+		}
+		for _, sid := range splitList(rule["resources"]) {
+			guestType, vmid := parseHaSid(sid)
+			if g, ok := s.guests[vmid]; !ok || (guestType == "ct") != (g.guestType == guestLxc) {
+				return errorf(500, "no such resource '%s'", sid)
+			}
+		}
+		return nil
+	}
+	s.haRules = rules
+	s.registerCollection(`/cluster/ha/rules`, rules)
+}
+
+// checkHaNodes validates a node list in the format `node[:priority],...`.
+func (s *Server) checkHaNodes(nodes string) error {
+	for _, e := range splitList(nodes) {
+		name, priority, ok := strings.Cut(e, ":")
+		if _, exists := s.nodes[name]; !exists {
+			return errorf(500, "no such node '%s'", name)
+		}
+		if _, err := strconv.Atoi(priority); ok && err != nil {
+			return errorf(400, "invalid priority '%s' of node '%s'", priority, name)
+		}
+	}
+	return nil
+}
+
+// HAGroup returns the stored config of an HA group.
+func (s *Server) HAGroup(name string) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	group, ok := s.haGroups.items[name]
+	return group, ok
+}
+
+// HARule returns the stored config of an HA rule.
+func (s *Server) HARule(id string) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rule, ok := s.haRules.items[id]
+	return rule, ok
+}
+
+// HAResource returns the HA config of a guest.
+func (s *Server) HAResource(id int) (map[string]any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res, ok := s.ha[id]
+	return res, ok
+}
@@ -0,0 +1,135 @@
+package fakepve
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultPCI returns the PCI devices every fake node has: an integrated GPU that supports mediated devices,
+// a GPU with its audio function in one IOMMU group and a network card.
+func defaultPCI() []map[string]any {
+	return []map[string]any{
+		{"id": "0000:00:02.0", "class": "0x030000", "vendor": "0x8086", "device": "0x3e92", "iommugroup": 0, "mdev": true,
+			"vendor_name": "Intel Corporation", "device_name": "CoffeeLake-S GT2 [UHD Graphics 630]"},
+		{"id": "0000:01:00.0", "class": "0x030000", "vendor": "0x10de", "device": "0x1eb8", "iommugroup": 1,
+			"subsystem_vendor": "0x10de", "subsystem_device": "0x12a2",
+			"vendor_name": "NVIDIA Corporation", "device_name": "TU104GL [Tesla T4]"},
+		{"id": "0000:01:00.1", "class": "0x040300", "vendor": "0x10de", "device": "0x10f8", "iommugroup": 1,
+			"vendor_name": "NVIDIA Corporation", "device_name": "TU104 HD Audio Controller"},
+		{"id": "0000:02:00.0", "class": "0x020000", "vendor": "0x8086", "device": "0x1533", "iommugroup": 2,
+			"vendor_name": "Intel Corporation", "device_name": "I210 Gigabit Network Connection"},
+	}
+}
+
+// defaultUSB returns the USB devices every fake node has.
+func defaultUSB() []map[string]any {
+	return []map[string]any{
+		{"busnum": 1, "devnum": 2, "port": 1, "level": 1, "usbpath": "1", "class": 0, "speed": "12",
+			"vendid": "046d", "prodid": "c52b", "manufacturer": "Logitech", "product": "USB Receiver"},
+		{"busnum": 2, "devnum": 3, "port": 2, "level": 1, "usbpath": "2", "class": 0, "speed": "5000",
+			"vendid": "0951", "prodid": "1666", "manufacturer": "Kingston", "product": "DataTraveler 3.0"},
+	}
+}
+
+// SetIOMMUGroup moves a PCI device of a node to another IOMMU group, like a firmware or kernel update can.
+func (s *Server) SetIOMMUGroup(node, pciID string, group int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n, ok := s.nodes[node]; ok {
+		if device := n.pciDevice(pciID); device != nil {
+			device["iommugroup"] = group
+		}
+	}
+}
+
+// pciDevice returns the PCI device with the ID, the domain may be left out. A path without a function matches function 0.
+func (n *node) pciDevice(pciID string) map[string]any {
+	if !strings.Contains(pciID, ".") {
+		pciID += ".0"
+	}
+	if strings.Count(pciID, ":") == 1 {
+		pciID = "0000:" + pciID
+	}
+	for _, device := range n.pci {
+		if device["id"] == pciID {
+			return device
+		}
+	}
+	return nil
+}
+
+// usbDevice returns the USB device on the port `<bus>-<path>`, the path of the API has no bus.
+func (n *node) usbDevice(port string) map[string]any {
+	for _, device := range n.usb {
+		if fmt.Sprint(device["busnum"])+"-"+device["usbpath"].(string) == port {
+			return device
+		}
+	}
+	return nil
+}
+
+// copyDevice copies a device, as the response is written after the lock of the server is released.
+func copyDevice(device map[string]any) map[string]any {
+	c := make(map[string]any, len(device))
+	for k, v := range device {
+		c[k] = v
+	}
+	return c
+}
+
+// pciVendorDevice returns the `<vendor>:<device>` ID of a PCI device, the format the mappings use.
+func pciVendorDevice(device map[string]any) string {
+	return strings.TrimPrefix(device["vendor"].(string), "0x") + ":" + strings.TrimPrefix(device["device"].(string), "0x")
+}
+
+func (s *Server) registerHardware() {
+	s.handle("GET", `/nodes/([^/]+)/hardware/pci`, func(r *request) (any, error) {
+		n, err := s.node(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		// PVE hides the memory controllers, bridges and processors unless the blacklist is overridden.
+		blacklist := []string{"05", "06", "0b"}
+		if r.has("pci-class-blacklist") {
+			blacklist = splitList(r.get("pci-class-blacklist"))
+		}
+		list := []any{}
+		for _, device := range n.pci {
+			class := strings.TrimPrefix(device["class"].(string), "0x")
+			if contains(blacklist, class[:2]) {
+				continue
+			}
+			list = append(list, copyDevice(device))
+		}
+		return list, nil
+	})
+	s.handle("GET", `/nodes/([^/]+)/hardware/pci/([^/]+)/mdev`, func(r *request) (any, error) {
+		n, err := s.node(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		device := n.pciDevice(r.vars[1])
+		if device == nil {
+			return nil, errorf(500, "can't find PCI device '%s'", r.vars[1])
+		}
+		if device["mdev"] != true {
+			return nil, errorf(500, "the device '%s' does not support mediated devices", r.vars[1])
+		}
+		// The types of an Intel GVT-g capable GPU, the number of available instances shrinks with their size.
+		return []any{
+			map[string]any{"type": "i915-GVTg_V5_4", "available": 1, "description": "low_gm_size: 128MB\nhigh_gm_size: 512MB\nresolution: 1920x1200"},
+			map[string]any{"type": "i915-GVTg_V5_8", "available": 2, "description": "low_gm_size: 64MB\nhigh_gm_size: 384MB\nresolution: 1024x768"},
+		}, nil
+	})
+	s.handle("GET", `/nodes/([^/]+)/hardware/usb`, func(r *request) (any, error) {
+		n, err := s.node(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		list := []any{}
+		for _, device := range n.usb {
+			list = append(list, copyDevice(device))
+		}
+		return list, nil
+	})
+}
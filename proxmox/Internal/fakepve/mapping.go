@@ -0,0 +1,206 @@
+package fakepve
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var rxMappingID = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_\-]{1,127}$`)
+
+// mapping is a cluster wide PCI or USB mapping, entries hold the devices of the nodes in the `key=value,...` format of PVE.
+type mapping struct {
+	config  map[string]string
+	entries []string
+}
+
+// Mapping returns the options and the device entries of a PCI or USB mapping, kind is "pci" or "usb".
+func (s *Server) Mapping(kind, id string) (map[string]string, []string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.mappings[kind][id]
+	if !ok {
+		return nil, nil, false
+	}
+	return copyConfig(m.config), append([]string(nil), m.entries...), true
+}
+
+func parseMappingEntry(entry string) map[string]string {
+	values := map[string]string{}
+	for _, kv := range strings.Split(entry, ",") {
+		k, v, _ := strings.Cut(kv, "=")
+		values[k] = v
+	}
+	return values
+}
+
+func (s *Server) checkMappingEntries(kind string, entries []string) error {
+	if len(entries) == 0 {
+		return errorf(400, "missing map")
+	}
+	nodes := map[string]struct{}{}
+	for _, entry := range entries {
+		values := parseMappingEntry(entry)
+		if _, err := s.node(values["node"]); err != nil {
+			return err
+		}
+		if _, ok := nodes[values["node"]]; ok && kind == "usb" {
+			return errorf(400, "node '%s' is mapped more than once", values["node"])
+		}
+		nodes[values["node"]] = struct{}{}
+		if values["id"] == "" {
+			return errorf(400, "map: missing id")
+		}
+		if kind == "pci" && values["path"] == "" {
+			return errorf(400, "map: missing path")
+		}
+	}
+	return nil
+}
+
+// mappingChecks returns the problems of the entries of a mapping for a node, like PVE does for the `check-node` parameter.
+func mappingChecks(kind string, m *mapping, n *node) []any {
+	checks := []any{}
+	add := func(severity, format string, a ...any) {
+		checks = append(checks, map[string]any{"severity": severity, "message": fmt.Sprintf(format, a...)})
+	}
+	found := false
+	for _, entry := range m.entries {
+		values := parseMappingEntry(entry)
+		if values["node"] != n.name {
+			continue
+		}
+		found = true
+		if kind == "pci" {
+			for _, path := range strings.Split(values["path"], ";") {
+				device := n.pciDevice(path)
+				if device == nil {
+					add("error", "PCI device '%s' not found", path)
+					continue
+				}
+				if pciVendorDevice(device) != values["id"] {
+					add("error", "PCI device '%s' has ID '%s' instead of '%s'", path, pciVendorDevice(device), values["id"])
+				}
+				if group, ok := values["iommugroup"]; ok && group != strconv.Itoa(device["iommugroup"].(int)) {
+					add("warning", "IOMMU group of PCI device '%s' changed from %s to %d", path, group, device["iommugroup"])
+				}
+				if m.config["mdev"] == "1" && device["mdev"] != true {
+					add("warning", "PCI device '%s' does not support mediated devices", path)
+				}
+			}
+			continue
+		}
+		if path := values["path"]; path != "" {
+			device := n.usbDevice(path)
+			if device == nil {
+				add("warning", "no USB device on port '%s'", path)
+			} else if device["vendid"].(string)+":"+device["prodid"].(string) != values["id"] {
+				add("error", "USB device on port '%s' does not match '%s'", path, values["id"])
+			}
+		}
+	}
+	if !found {
+		add("error", "mapping has no device on node '%s'", n.name)
+	}
+	return checks
+}
+
+func (m *mapping) api(id string) map[string]any {
+	item := map[string]any{"id": id, "map": append([]string(nil), m.entries...), "digest": "0"}
+	for k, v := range m.config {
+		item[k] = typed(k, v, map[string]struct{}{"mdev": {}})
+	}
+	return item
+}
+
+func (s *Server) registerMappings() {
+	s.mappings = map[string]map[string]*mapping{"pci": {}, "usb": {}}
+	const base = `/cluster/mapping/(pci|usb)`
+	get := func(r *request) (*mapping, error) {
+		m, ok := s.mappings[r.vars[0]][r.vars[1]]
+		if !ok {
+			return nil, errorf(500, "%s mapping '%s' does not exist", r.vars[0], r.vars[1])
+		}
+		return m, nil
+	}
+	apply := func(kind string, m *mapping, r *request) error {
+		entries := m.entries
+		if r.has("map") {
+			entries = r.params["map"]
+		}
+		if err := s.checkMappingEntries(kind, entries); err != nil {
+			return err
+		}
+		m.entries = entries
+		for key := range r.params {
+			switch key {
+			case "id", "map", "delete", "digest":
+				continue
+			}
+			m.config[key] = r.get(key)
+		}
+		for _, key := range splitList(r.get("delete")) {
+			delete(m.config, key)
+		}
+		return nil
+	}
+
+	s.handle("GET", base, func(r *request) (any, error) {
+		kind := r.vars[0]
+		list := []any{}
+		for _, id := range sortedKeys(s.mappings[kind]) {
+			item := s.mappings[kind][id].api(id)
+			if node := r.get("check-node"); node != "" {
+				n, err := s.onlineNode(node)
+				if err != nil {
+					return nil, err
+				}
+				item["checks"] = mappingChecks(kind, s.mappings[kind][id], n)
+			}
+			list = append(list, item)
+		}
+		return list, nil
+	})
+	s.handle("POST", base, func(r *request) (any, error) {
+		kind, id := r.vars[0], r.get("id")
+		if !rxMappingID.MatchString(id) {
+			return nil, errorf(400, "invalid mapping ID '%s'", id)
+		}
+		if _, ok := s.mappings[kind][id]; ok {
+			return nil, errorf(500, "%s mapping '%s' already exists", kind, id)
+		}
+		m := &mapping{config: map[string]string{}}
+		if err := apply(kind, m, r); err != nil {
+			return nil, err
+		}
+		s.mappings[kind][id] = m
+		return nil, nil
+	})
+	s.handle("GET", base+`/([^/]+)`, func(r *request) (any, error) {
+		m, err := get(r)
+		if err != nil {
+			return nil, err
+		}
+		return m.api(r.vars[1]), nil
+	})
+	s.handle("PUT", base+`/([^/]+)`, func(r *request) (any, error) {
+		m, err := get(r)
+		if err != nil {
+			return nil, err
+		}
+		updated := &mapping{config: copyConfig(m.config), entries: m.entries}
+		if err = apply(r.vars[0], updated, r); err != nil {
+			return nil, err
+		}
+		*m = *updated
+		return nil, nil
+	})
+	s.handle("DELETE", base+`/([^/]+)`, func(r *request) (any, error) {
+		if _, err := get(r); err != nil {
+			return nil, err
+		}
+		delete(s.mappings[r.vars[0]], r.vars[1])
+		return nil, nil
+	})
+}
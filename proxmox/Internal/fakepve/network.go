@@ -0,0 +1,236 @@
+package fakepve
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	rxNetworkName = map[string]*regexp.Regexp{
+		"bridge": regexp.MustCompile(`^vmbr\d{1,4}$`),
+		"bond":   regexp.MustCompile(`^bond\d{1,4}$`),
+		"vlan":   regexp.MustCompile(`^(?:vlan\d+|[a-zA-Z][a-zA-Z0-9_]*\.\d+)$`),
+	}
+	networkNumeric = map[string]struct{}{"active": {}, "autostart": {}, "bridge_vlan_aware": {}, "vlan-id": {}, "mtu": {}}
+	bondModes      = []string{"balance-rr", "active-backup", "balance-xor", "broadcast", "802.3ad", "balance-tlb", "balance-alb"}
+)
+
+// NetworkInterface returns the applied config of a network interface of a node.
+func (s *Server) NetworkInterface(node, iface string) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, ok := s.nodes[node]
+	if !ok {
+		return nil, false
+	}
+	config, ok := n.network[iface]
+	return config, ok
+}
+
+// NetworkPending reports whether the network of a node has changes that are not applied.
+func (s *Server) NetworkPending(node string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, ok := s.nodes[node]
+	return ok && n.pendingNetwork != nil
+}
+
+// editNetwork returns the interfaces with the pending changes, changes are made to a copy of the applied interfaces.
+func (n *node) editNetwork() map[string]map[string]string {
+	if n.pendingNetwork == nil {
+		n.pendingNetwork = make(map[string]map[string]string, len(n.network))
+		for iface, config := range n.network {
+			n.pendingNetwork[iface] = copyConfig(config)
+		}
+	}
+	return n.pendingNetwork
+}
+
+func (n *node) currentNetwork() map[string]map[string]string {
+	if n.pendingNetwork != nil {
+		return n.pendingNetwork
+	}
+	return n.network
+}
+
+func (n *node) networkAPI(iface string) map[string]any {
+	item := map[string]any{"iface": iface, "active": 0, "families": []any{"inet"}, "method": "manual"}
+	for k, v := range n.currentNetwork()[iface] {
+		item[k] = typed(k, v, networkNumeric)
+	}
+	if _, ok := n.network[iface]; ok {
+		item["active"] = 1
+	}
+	if _, ok := item["cidr"]; ok {
+		item["method"] = "static"
+	}
+	return item
+}
+
+func copyConfig(config map[string]string) map[string]string {
+	c := make(map[string]string, len(config))
+	for k, v := range config {
+		c[k] = v
+	}
+	return c
+}
+
+// checkNetwork validates an interface against the other interfaces of the node.
+func checkNetwork(network map[string]map[string]string, iface string, config map[string]string) error {
+	switch config["type"] {
+	case "bridge":
+		for _, port := range strings.Fields(config["bridge_ports"]) {
+			if _, ok := network[port]; !ok {
+				return errorf(400, "bridge port '%s' does not exist", port)
+			}
+		}
+	case "bond":
+		slaves := strings.Fields(config["slaves"])
+		if len(slaves) == 0 {
+			return errorf(400, "slaves: property is missing and it is not optional")
+		}
+		for _, slave := range slaves {
+			if other, ok := network[slave]; !ok || other["type"] != "eth" {
+				return errorf(400, "bond slave '%s' is not a physical interface", slave)
+			}
+		}
+		if mode := config["bond_mode"]; mode != "" && !contains(bondModes, mode) {
+			return errorf(400, "bond_mode: value '%s' does not have a value in the enumeration", mode)
+		}
+	case "vlan":
+		if device, id, ok := strings.Cut(iface, "."); ok && !strings.HasPrefix(iface, "vlan") {
+			config["vlan-raw-device"], config["vlan-id"] = device, id
+		}
+		if config["vlan-raw-device"] == "" || config["vlan-id"] == "" {
+			return errorf(400, "vlan-raw-device and vlan-id are required for interface '%s'", iface)
+		}
+		if id, err := strconv.Atoi(config["vlan-id"]); err != nil || id < 1 || id > 4094 {
+			return errorf(400, "vlan-id: value must be between 1 and 4094")
+		}
+		if _, ok := network[config["vlan-raw-device"]]; !ok {
+			return errorf(400, "vlan-raw-device '%s' does not exist", config["vlan-raw-device"])
+		}
+	default:
+		return errorf(400, "type: value '%s' does not have a value in the enumeration", config["type"])
+	}
+	if !rxNetworkName[config["type"]].MatchString(iface) {
+		return errorf(400, "iface: invalid %s name '%s'", config["type"], iface)
+	}
+	return nil
+}
+
+// applyNetworkParams sets the parameters of a create or update request on the config of an interface.
+func applyNetworkParams(config map[string]string, r *request) {
+	for key := range r.params {
+		switch key {
+		case "iface", "delete", "digest", "type", "node":
+			continue
+		}
+		config[key] = r.get(key)
+	}
+	for _, key := range splitList(r.get("delete")) {
+		delete(config, key)
+	}
+}
+
+func (s *Server) registerNetwork() {
+	s.handle("GET", `/nodes/([^/]+)/network`, func(r *request) (any, error) {
+		n, err := s.node(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		list := []any{}
+		for _, iface := range sortedKeys(n.currentNetwork()) {
+			t, ifaceType := r.get("type"), n.currentNetwork()[iface]["type"]
+			if t == "" || t == ifaceType || (t == "any_bridge" && (ifaceType == "bridge" || ifaceType == "OVSBridge")) {
+				list = append(list, n.networkAPI(iface))
+			}
+		}
+		return list, nil
+	})
+	s.handle("GET", `/nodes/([^/]+)/network/([^/]+)`, func(r *request) (any, error) {
+		n, err := s.node(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := n.currentNetwork()[r.vars[1]]; !ok {
+			return nil, errorf(500, "interface does not exist")
+		}
+		return n.networkAPI(r.vars[1]), nil
+	})
+	s.handle("POST", `/nodes/([^/]+)/network`, func(r *request) (any, error) {
+		n, err := s.node(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		iface := r.get("iface")
+		if _, ok := n.currentNetwork()[iface]; ok {
+			return nil, errorf(500, "interface already exists")
+		}
+		config := map[string]string{"type": r.get("type")}
+		applyNetworkParams(config, r)
+		if err := checkNetwork(n.currentNetwork(), iface, config); err != nil {
+			return nil, err
+		}
+		n.editNetwork()[iface] = config
+		return nil, nil
+	})
+	s.handle("PUT", `/nodes/([^/]+)/network/([^/]+)`, func(r *request) (any, error) {
+		n, err := s.node(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		iface := r.vars[1]
+		current, ok := n.currentNetwork()[iface]
+		if !ok {
+			return nil, errorf(500, "interface does not exist")
+		}
+		if r.get("type") != current["type"] {
+			return nil, errorf(400, "interface type mismatch: '%s' != '%s'", r.get("type"), current["type"])
+		}
+		config := copyConfig(current)
+		applyNetworkParams(config, r)
+		if err := checkNetwork(n.currentNetwork(), iface, config); err != nil {
+			return nil, err
+		}
+		n.editNetwork()[iface] = config
+		return nil, nil
+	})
+	s.handle("DELETE", `/nodes/([^/]+)/network/([^/]+)`, func(r *request) (any, error) {
+		n, err := s.node(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		iface := r.vars[1]
+		if _, ok := n.currentNetwork()[iface]; !ok {
+			return nil, errorf(500, "network interface '%s' does not exist", iface)
+		}
+		for other, config := range n.currentNetwork() {
+			if contains(strings.Fields(config["bridge_ports"]+" "+config["slaves"]), iface) || config["vlan-raw-device"] == iface {
+				return nil, errorf(500, "interface '%s' is used by '%s'", iface, other)
+			}
+		}
+		delete(n.editNetwork(), iface)
+		return nil, nil
+	})
+	s.handle("PUT", `/nodes/([^/]+)/network`, func(r *request) (any, error) {
+		n, err := s.node(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		upid := s.newTask(n.name, "srvreload", "networking")
+		if !s.taskFailed(upid) && n.pendingNetwork != nil {
+			n.network, n.pendingNetwork = n.pendingNetwork, nil
+		}
+		return upid, nil
+	})
+	s.handle("DELETE", `/nodes/([^/]+)/network`, func(r *request) (any, error) {
+		n, err := s.node(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		n.pendingNetwork = nil
+		return nil, nil
+	})
+}
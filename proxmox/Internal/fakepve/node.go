@@ -0,0 +1,148 @@
+package fakepve
+
+import "time"
+
+const (
+	nodeMemory = 64 * 1024 * 1024 * 1024
+	nodeCPUs   = 16
+)
+
+type node struct {
+	name   string
+	online bool
+	start  time.Time
+	// memory holds the used memory of the samples in the RRD data of the last hour, the last sample is the current usage.
+	memory []int64
+	// network holds the applied network interfaces, pendingNetwork the interfaces with the changes that are not applied yet.
+	network        map[string]map[string]string
+	pendingNetwork map[string]map[string]string
+	// pci and usb hold the devices of the node, in the format of the hardware endpoints.
+	pci []map[string]any
+	usb []map[string]any
+}
+
+func newNode(name string) *node {
+	return &node{name: name, online: true, start: time.Now(), network: map[string]map[string]string{
+		"eno1":  {"type": "eth", "autostart": "1"},
+		"eno2":  {"type": "eth", "autostart": "1"},
+		"vmbr0": {"type": "bridge", "autostart": "1", "bridge_ports": "eno1", "cidr": "192.168.1.10/24", "gateway": "192.168.1.1"},
+	}, pci: defaultPCI(), usb: defaultUSB()}
+}
+
+func (n *node) status() string {
+	if n.online {
+		return "online"
+	}
+	return "offline"
+}
+
+func (n *node) resource() map[string]any {
+	return map[string]any{
+		"id":      "node/" + n.name,
+		"type":    "node",
+		"node":    n.name,
+		"status":  n.status(),
+		"maxcpu":  nodeCPUs,
+		"maxmem":  nodeMemory,
+		"uptime":  int64(time.Since(n.start).Seconds()),
+		"level":   "",
+		"cpu":     0.01,
+		"mem":     n.memoryUsed(),
+		"disk":    0,
+		"maxdisk": 0}
+}
+
+func (n *node) memoryUsed() int64 {
+	if len(n.memory) == 0 {
+		return 0
+	}
+	return n.memory[len(n.memory)-1]
+}
+
+// rrd returns the samples of the last hour, one per minute.
+func (n *node) rrd() []any {
+	samples := n.memory
+	if len(samples) == 0 {
+		samples = []int64{0}
+	}
+	now := time.Now().Truncate(time.Minute).Unix()
+	list := make([]any, len(samples))
+	for i, used := range samples {
+		list[i] = map[string]any{
+			"time":     now - int64(len(samples)-1-i)*60,
+			"cpu":      0.01,
+			"maxcpu":   nodeCPUs,
+			"memused":  used,
+			"memtotal": nodeMemory}
+	}
+	return list
+}
+
+// SetNodeMemory sets the used memory in bytes of the samples in the RRD data of a node, the last sample is the current usage.
+func (s *Server) SetNodeMemory(name string, samples ...int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n, ok := s.nodes[name]; ok {
+		n.memory = samples
+	}
+}
+
+// SetNodeOnline marks a node as online or offline.
+func (s *Server) SetNodeOnline(name string, online bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n, ok := s.nodes[name]; ok {
+		n.online = online
+	}
+}
+
+func (s *Server) node(name string) (*node, error) {
+	if n, ok := s.nodes[name]; ok {
+		return n, nil
+	}
+	return nil, errorf(595, "no such node '%s'", name)
+}
+
+// onlineNode returns the node when the API of the node can be reached, like the proxy of PVE.
+func (s *Server) onlineNode(name string) (*node, error) {
+	n, err := s.node(name)
+	if err == nil && !n.online {
+		return nil, errorf(595, "no route to host '%s'", name)
+	}
+	return n, err
+}
+
+func (s *Server) registerNodes() {
+	s.handle("GET", `/nodes`, func(r *request) (any, error) {
+		list := []any{}
+		for _, name := range sortedKeys(s.nodes) {
+			list = append(list, s.nodes[name].resource())
+		}
+		return list, nil
+	})
+	s.handle("GET", `/nodes/([^/]+)/status`, func(r *request) (any, error) {
+		n, err := s.onlineNode(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{
+			"uptime":     int64(time.Since(n.start).Seconds()),
+			"cpu":        0.01,
+			"pveversion": "pve-manager/8.4.1/fake",
+			"kversion":   "Linux 6.8.12-fake-pve",
+			"cpuinfo":    map[string]any{"cpus": nodeCPUs, "cores": nodeCPUs / 2, "sockets": 1, "model": "Fake CPU"},
+			"memory":     map[string]any{"total": nodeMemory, "used": n.memoryUsed(), "free": nodeMemory - n.memoryUsed()},
+			"swap":       map[string]any{"total": 0, "used": 0, "free": 0},
+			"rootfs":     map[string]any{"total": 0, "used": 0, "free": 0, "avail": 0}}, nil
+	})
+	s.handle("GET", `/nodes/([^/]+)/rrddata`, func(r *request) (any, error) {
+		n, err := s.node(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		if r.get("timeframe") == "" {
+			return nil, errorf(400, "parameter verification failed: timeframe: property is missing and it is not optional")
+		}
+		return n.rrd(), nil
+	})
+}
@@ -0,0 +1,168 @@
+package fakepve
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type pool struct {
+	name     string
+	comment  string
+	guests   map[int]struct{}
+	storages map[string]struct{}
+}
+
+func (s *Server) pool(name string) (*pool, error) {
+	if p, ok := s.pools[name]; ok {
+		return p, nil
+	}
+	return nil, errorf(500, "pool '%s' does not exist", name)
+}
+
+func (p *pool) add(g *guest) {
+	p.guests[g.id] = struct{}{}
+	g.pool = p.name
+}
+
+func (p *pool) remove(id int) { delete(p.guests, id) }
+
+func (s *Server) poolMembers(p *pool) []any {
+	ids := make([]int, 0, len(p.guests))
+	for id := range p.guests {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	members := make([]any, 0, len(ids)+len(p.storages))
+	for _, id := range ids {
+		if g, ok := s.guests[id]; ok {
+			members = append(members, g.resource())
+		}
+	}
+	for _, name := range sortedKeys(p.storages) {
+		if st, ok := s.storages[name]; ok {
+			for _, n := range sortedKeys(s.nodes) {
+				if st.availableOn(n) {
+					members = append(members, st.resource(n))
+					break
+				}
+			}
+		}
+	}
+	return members
+}
+
+// PoolMembers returns the IDs of the guests and the names of the storages in a pool.
+func (s *Server) PoolMembers(name string) ([]int, []string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.pools[name]
+	if !ok {
+		return nil, nil, false
+	}
+	ids := make([]int, 0, len(p.guests))
+	for id := range p.guests {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids, sortedKeys(p.storages), true
+}
+
+func (s *Server) registerPools() {
+	s.handle("GET", `/pools`, func(r *request) (any, error) {
+		if name := r.get("poolid"); name != "" {
+			p, err := s.pool(name)
+			if err != nil {
+				return nil, err
+			}
+			return []any{map[string]any{"poolid": p.name, "comment": p.comment, "members": s.poolMembers(p)}}, nil
+		}
+		list := []any{}
+		for _, name := range sortedKeys(s.pools) {
+			list = append(list, map[string]any{"poolid": name, "comment": s.pools[name].comment})
+		}
+		return list, nil
+	})
+	s.handle("GET", `/pools/([^/]+)`, func(r *request) (any, error) {
+		p, err := s.pool(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"comment": p.comment, "members": s.poolMembers(p)}, nil
+	})
+	s.handle("POST", `/pools`, func(r *request) (any, error) {
+		name := r.get("poolid")
+		if _, ok := s.pools[name]; ok {
+			return nil, errorf(500, "create pool failed: pool '%s' already exists", name)
+		}
+		s.pools[name] = &pool{name: name, comment: r.get("comment"), guests: map[int]struct{}{}, storages: map[string]struct{}{}}
+		return nil, nil
+	})
+	s.handle("PUT", `/pools/([^/]+)`, func(r *request) (any, error) {
+		p, err := s.pool(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		return nil, s.updatePool(p, r)
+	})
+	s.handle("PUT", `/pools`, func(r *request) (any, error) {
+		p, err := s.pool(r.get("poolid"))
+		if err != nil {
+			return nil, err
+		}
+		return nil, s.updatePool(p, r)
+	})
+	s.handle("DELETE", `/pools/([^/]+)`, func(r *request) (any, error) {
+		p, err := s.pool(r.vars[0])
+		if err != nil {
+			return nil, errorf(500, "delete pool failed: %v", err)
+		}
+		if len(p.guests) > 0 || len(p.storages) > 0 {
+			return nil, errorf(500, "delete pool failed: pool '%s' is not empty", p.name)
+		}
+		delete(s.pools, p.name)
+		return nil, nil
+	})
+}
+
+func (s *Server) updatePool(p *pool, r *request) error {
+	if r.has("comment") {
+		p.comment = r.get("comment")
+	}
+	remove := r.get("delete") == "1"
+	for _, raw := range splitList(r.get("vms")) {
+		id, _ := strconv.Atoi(raw)
+		g, ok := s.guests[id]
+		if !ok {
+			return errorf(500, "no such VMID '%d'", id)
+		}
+		if remove {
+			p.remove(id)
+			g.pool = ""
+			continue
+		}
+		if g.pool != "" && g.pool != p.name {
+			if r.get("allow-move") != "1" {
+				return errorf(500, "VM %d belongs already to pool '%s'", id, g.pool)
+			}
+			s.pools[g.pool].remove(id)
+		}
+		p.add(g)
+	}
+	for _, name := range splitList(r.get("storage")) {
+		if _, ok := s.storages[name]; !ok {
+			return errorf(500, "no such storage '%s'", name)
+		}
+		if remove {
+			delete(p.storages, name)
+			continue
+		}
+		p.storages[name] = struct{}{}
+	}
+	return nil
+}
+
+// splitList splits the comma, semicolon or space separated lists PVE accepts.
+func splitList(raw string) []string {
+	return strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || r == ';' || r == ' ' })
+}
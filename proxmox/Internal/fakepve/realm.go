@@ -0,0 +1,79 @@
+package fakepve
+
+import (
+	"strings"
+)
+
+// realmRequired are the options every realm of a type needs.
+var realmRequired = map[string][]string{
+	"pam":    nil,
+	"pve":    nil,
+	"ldap":   {"server1", "base_dn", "user_attr"},
+	"ad":     {"server1", "domain"},
+	"openid": {"issuer-url", "client-id"},
+}
+
+func (s *Server) registerRealms() {
+	realms := newCollection("domain", "realm", "port", "verify", "default", "case-sensitive", "secure",
+		"autocreate", "groups-autocreate", "groups-overwrite", "query-userinfo")
+	realms.hidden["password"] = struct{}{}
+	realms.hidden["client-key"] = struct{}{}
+	realms.validate = func(id string, realm map[string]string) error {
+		required, ok := realmRequired[realm["type"]]
+		if !ok {
+			return errorf(400, "type: unknown realm type '%s'", realm["type"])
+		}
+		for _, key := range required {
+			if realm[key] == "" {
+				return errorf(400, "parameter verification failed: %s: property is missing and it is not optional", key)
+			}
+		}
+		if realm["default"] == "1" {
+			for other, item := range s.realms.items {
+				if other != id {
+					delete(item, "default")
+				}
+			}
+		}
+		return nil
+	}
+	realms.items["pam"] = map[string]string{"type": "pam", "comment": "Linux PAM standard authentication"}
+	realms.items["pve"] = map[string]string{"type": "pve", "comment": "Proxmox VE authentication server"}
+	s.realms = realms
+	s.handle("DELETE", `/access/domains/(pam|pve)`, func(r *request) (any, error) {
+		return nil, errorf(500, "can't delete realm '%s' - builtin realm", r.vars[0])
+	})
+	s.handle("POST", `/access/domains/([^/]+)/sync`, func(r *request) (any, error) {
+		realm, err := realms.get(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		if realm["type"] != "ldap" && realm["type"] != "ad" {
+			return nil, errorf(400, "realm '%s' of type '%s' does not support syncing", r.vars[0], realm["type"])
+		}
+		options := []string{"scope=" + r.get("scope")}
+		for _, key := range []string{"enable-new", "remove-vanished"} {
+			if r.has(key) {
+				options = append(options, key+"="+r.get(key))
+			}
+		}
+		s.realmSyncs = append(s.realmSyncs, r.vars[0]+":"+strings.Join(options, ","))
+		return s.newTask(sortedKeys(s.nodes)[0], "auth-realm-sync", r.vars[0]), nil
+	})
+	s.registerCollection(`/access/domains`, realms)
+}
+
+// Realm returns the stored config of an authentication realm, including the secrets PVE never returns.
+func (s *Server) Realm(id string) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	realm, ok := s.realms.items[id]
+	return realm, ok
+}
+
+// RealmSyncs returns the syncs that were started, in the format `realm:option=value,...`.
+func (s *Server) RealmSyncs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.realmSyncs...)
+}
@@ -0,0 +1,148 @@
+package fakepve
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var rxReplicationID = regexp.MustCompile(`^(\d+)-(\d+)$`)
+
+// ReplicationJob returns the stored config of a replication job, the way it would be written to replication.cfg.
+func (s *Server) ReplicationJob(id string) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.replJobs.items[id]
+	return copyConfig(job), ok
+}
+
+// RunReplication records a run of a replication job, an empty message is a successful sync.
+func (s *Server) RunReplication(id, errorMessage string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.replState[id]
+	if !ok {
+		state = map[string]any{"fail_count": 0}
+		s.replState[id] = state
+	}
+	now := time.Now().Unix()
+	state["last_try"] = now
+	state["next_sync"] = now + 900
+	if errorMessage == "" {
+		state["last_sync"], state["duration"], state["fail_count"] = now, 4.2, 0
+		delete(state, "error")
+		return
+	}
+	state["fail_count"] = state["fail_count"].(int) + 1
+	state["error"] = errorMessage
+}
+
+// replicationVolumes checks that PVE can replicate every disk of the guest, which only ZFS storages support.
+func (s *Server) replicationVolumes(g *guest) error {
+	for _, key := range sortedKeys(g.config) {
+		value := g.config[key]
+		if !diskKey.MatchString(key) || strings.HasPrefix(key, "unused") || strings.Contains(value, "media=cdrom") ||
+			strings.Contains(value, "replicate=0") {
+			continue
+		}
+		storageName, _, _ := strings.Cut(value, ":")
+		if st, ok := s.storages[storageName]; !ok || st.storageType != "zfspool" {
+			volume, _, _ := strings.Cut(value, ",")
+			return errorf(500, "missing replicate feature on volume '%s'", volume)
+		}
+	}
+	return nil
+}
+
+func (s *Server) registerReplication() {
+	jobs := newCollection("replication job", "id", "guest", "jobnum", "rate", "disable")
+	jobs.defaults = map[string]string{"type": "local"}
+	// The target cannot be changed, a job has to be recreated for another target.
+	jobs.fixed = map[string]struct{}{"target": {}, "type": {}}
+	jobs.validate = func(id string, job map[string]string) error {
+		m := rxReplicationID.FindStringSubmatch(id)
+		if m == nil {
+			return errorf(400, "invalid replication job ID '%s'", id)
+		}
+		guestID, _ := strconv.Atoi(m[1])
+		g, ok := s.guests[guestID]
+		if !ok {
+			return errorf(500, "guest '%d' does not exist", guestID)
+		}
+		if _, err := s.node(job["target"]); err != nil {
+			return err
+		}
+		if job["target"] == g.node {
+			return errorf(500, "source and target node are the same ('%s')", g.node)
+		}
+		for other, o := range jobs.items {
+			if other != id && o["guest"] == m[1] && o["target"] == job["target"] {
+				return errorf(500, "guest '%d' is already replicated to node '%s'", guestID, job["target"])
+			}
+		}
+		if rate := job["rate"]; rate != "" {
+			if v, err := strconv.ParseFloat(rate, 64); err != nil || v < 1 {
+				return errorf(400, "parameter verification failed: rate: value must have a minimum value of 1")
+			}
+		}
+		if job["schedule"] == "" {
+			job["schedule"] = "*/15"
+		}
+		job["guest"], job["jobnum"] = m[1], m[2]
+		return s.replicationVolumes(g)
+	}
+	jobs.remove = func(id string) error {
+		delete(s.replState, id)
+		return nil
+	}
+	s.replJobs = jobs
+	s.replState = map[string]map[string]any{}
+	s.registerCollection(`/cluster/replication`, jobs)
+
+	// status returns the state of the jobs of the guests on the node, like the replication runner of the node reports it.
+	status := func(node string) []map[string]any {
+		list := []map[string]any{}
+		for _, id := range sortedKeys(jobs.items) {
+			job := jobs.items[id]
+			guestID, _ := strconv.Atoi(job["guest"])
+			g, ok := s.guests[guestID]
+			if !ok || g.node != node {
+				continue
+			}
+			item := jobs.api(id)
+			item["vmtype"] = g.guestType
+			item["source"] = node
+			if state, ok := s.replState[id]; ok {
+				for k, v := range state {
+					item[k] = v
+				}
+			} else {
+				item["fail_count"] = 0
+			}
+			list = append(list, item)
+		}
+		return list
+	}
+	s.handle("GET", `/nodes/([^/]+)/replication`, func(r *request) (any, error) {
+		if _, err := s.node(r.vars[0]); err != nil {
+			return nil, err
+		}
+		list := []any{}
+		for _, item := range status(r.vars[0]) {
+			list = append(list, item)
+		}
+		return list, nil
+	})
+	s.handle("GET", `/nodes/([^/]+)/replication/([^/]+)/status`, func(r *request) (any, error) {
+		if _, err := s.node(r.vars[0]); err != nil {
+			return nil, err
+		}
+		for _, item := range status(r.vars[0]) {
+			if item["id"] == r.vars[1] {
+				return item, nil
+			}
+		}
+		return nil, errorf(500, "no such replication job '%s' on node '%s'", r.vars[1], r.vars[0])
+	})
+}
@@ -0,0 +1,408 @@
+package fakepve
+
+import (
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var rxSDNID = regexp.MustCompile(`^[a-z][a-z0-9]*[a-z0-9]$`)
+
+var sdnNumeric = []string{"mtu", "tag", "vrf-vxlan", "vlanaware", "isolate-ports", "snat", "asn", "ebgp", "ebgp-multihop",
+	"advertise-subnets", "exitnodes-local-routing", "disable-arp-nd-suppression", "bgp-multipath-as-path-relax"}
+
+// sdnZoneRequired are the options every zone of a type needs.
+var sdnZoneRequired = map[string][]string{
+	"simple": nil,
+	"vlan":   {"bridge"},
+	"qinq":   {"bridge", "tag"},
+	"vxlan":  {"peers"},
+	"evpn":   {"controller", "vrf-vxlan"},
+}
+
+// sdnControllerRequired are the options every controller of a type needs.
+var sdnControllerRequired = map[string][]string{
+	"evpn": {"asn", "peers"},
+	"bgp":  {"node", "asn", "peers"},
+	"isis": {"node", "isis-domain", "isis-ifaces", "isis-net"},
+}
+
+// sdn holds the SDN config of the cluster, running is the config that was applied last, per kind of object.
+type sdn struct {
+	zones       *collection
+	vnets       *collection
+	subnets     *collection
+	controllers *collection
+	running     map[*collection]map[string]map[string]string
+}
+
+func (n *sdn) kinds() []*collection {
+	return []*collection{n.zones, n.vnets, n.subnets, n.controllers}
+}
+
+// pending reports whether the config differs from the running config.
+func (n *sdn) pending() bool {
+	for _, c := range n.kinds() {
+		if len(c.items) != len(n.running[c]) {
+			return true
+		}
+		for id := range c.items {
+			if sdnState(c.items[id], n.running[c][id]) != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sdnState returns the pending state of an object like PVE does, empty when the object is applied.
+func sdnState(item, running map[string]string) string {
+	switch {
+	case item == nil:
+		return "deleted"
+	case running == nil:
+		return "new"
+	case len(item) != len(running):
+		return "changed"
+	}
+	for k, v := range item {
+		if running[k] != v {
+			return "changed"
+		}
+	}
+	return ""
+}
+
+// list returns the objects of a kind that match the filter.
+// With `running` the applied objects are returned, with `pending` every object gets its pending state and deleted objects are included.
+func (n *sdn) list(c *collection, r *request, filter func(item map[string]string) bool, api func(id string) map[string]any) []any {
+	list := []any{}
+	if r.get("running") == "1" {
+		for _, id := range sortedKeys(n.running[c]) {
+			if filter == nil || filter(n.running[c][id]) {
+				item := map[string]any{c.idKey: id}
+				for k, v := range n.running[c][id] {
+					item[k] = typed(k, v, c.numeric)
+				}
+				list = append(list, item)
+			}
+		}
+		return list
+	}
+	ids := sortedKeys(c.items)
+	if r.get("pending") == "1" {
+		for id := range n.running[c] {
+			if _, ok := c.items[id]; !ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	for _, id := range ids {
+		item, ok := c.items[id]
+		if !ok {
+			if filter == nil || filter(n.running[c][id]) {
+				list = append(list, map[string]any{c.idKey: id, "state": "deleted"})
+			}
+			continue
+		}
+		if filter != nil && !filter(item) {
+			continue
+		}
+		e := api(id)
+		if state := sdnState(item, n.running[c][id]); state != "" && r.get("pending") == "1" {
+			e["state"] = state
+		}
+		list = append(list, e)
+	}
+	return list
+}
+
+func sdnCheckID(kind, id string) error {
+	if !rxSDNID.MatchString(id) || len(id) > 8 {
+		return errorf(400, "%s: invalid format - %s ID '%s' must start with a letter, only contain lowercase letters and digits and be at most 8 characters", kind, kind, id)
+	}
+	return nil
+}
+
+func sdnCheckRequired(required []string, item map[string]string) error {
+	for _, key := range required {
+		if item[key] == "" {
+			return errorf(400, "parameter verification failed: %s: property is missing and it is not optional", key)
+		}
+	}
+	return nil
+}
+
+// subnetID returns the ID PVE gives a subnet, `<zone>-<ip>-<mask>`.
+func subnetID(zone string, cidr *net.IPNet) string {
+	ones, _ := cidr.Mask.Size()
+	return zone + "-" + cidr.IP.String() + "-" + strconv.Itoa(ones)
+}
+
+func (n *sdn) subnetAPI(id string) map[string]any {
+	item := n.subnets.api(id)
+	delete(item, "dhcp-range")
+	if ranges := strings.Fields(n.subnets.items[id]["dhcp-range"]); len(ranges) > 0 {
+		list := make([]any, len(ranges))
+		for i, e := range ranges {
+			r := map[string]any{}
+			for _, kv := range strings.Split(e, ",") {
+				k, v, _ := strings.Cut(kv, "=")
+				r[k] = v
+			}
+			list[i] = r
+		}
+		item["dhcp-range"] = list
+	}
+	return item
+}
+
+// applySubnet sets the parameters of a create or update request on a subnet, the DHCP ranges are a list in the API.
+func (n *sdn) applySubnet(item map[string]string, r *request) error {
+	for key := range r.params {
+		switch key {
+		case "subnet", "delete", "digest", "type", "vnet", "dhcp-range":
+			continue
+		}
+		item[key] = r.get(key)
+	}
+	for _, key := range splitList(r.get("delete")) {
+		delete(item, key)
+	}
+	if ranges, ok := r.params["dhcp-range"]; ok {
+		_, cidr, _ := net.ParseCIDR(item["cidr"])
+		for _, e := range ranges {
+			for _, kv := range strings.Split(e, ",") {
+				k, v, _ := strings.Cut(kv, "=")
+				if (k != "start-address" && k != "end-address") || !cidr.Contains(net.ParseIP(v)) {
+					return errorf(400, "dhcp-range: invalid range '%s' for subnet %s", e, item["cidr"])
+				}
+			}
+		}
+		item["dhcp-range"] = strings.Join(ranges, " ")
+	}
+	if gateway := item["gateway"]; gateway != "" {
+		if _, cidr, _ := net.ParseCIDR(item["cidr"]); !cidr.Contains(net.ParseIP(gateway)) {
+			return errorf(400, "gateway: %s is not in subnet %s", gateway, item["cidr"])
+		}
+	}
+	return nil
+}
+
+func (s *Server) registerSDN() {
+	n := &sdn{
+		zones:       newCollection("zone", "zone", sdnNumeric...),
+		vnets:       newCollection("vnet", "vnet", sdnNumeric...),
+		subnets:     newCollection("subnet", "subnet", sdnNumeric...),
+		controllers: newCollection("controller", "controller", sdnNumeric...),
+	}
+	n.running = map[*collection]map[string]map[string]string{}
+	for _, c := range n.kinds() {
+		n.running[c] = map[string]map[string]string{}
+	}
+	s.sdn = n
+
+	n.zones.defaults = map[string]string{"ipam": "pve"}
+	n.zones.validate = func(id string, zone map[string]string) error {
+		if err := sdnCheckID("zone", id); err != nil {
+			return err
+		}
+		required, ok := sdnZoneRequired[zone["type"]]
+		if !ok {
+			return errorf(400, "type: value '%s' does not have a value in the enumeration 'evpn, qinq, simple, vlan, vxlan'", zone["type"])
+		}
+		if err := sdnCheckRequired(required, zone); err != nil {
+			return err
+		}
+		if controller := zone["controller"]; controller != "" {
+			if c, ok := n.controllers.items[controller]; !ok || c["type"] != "evpn" {
+				return errorf(400, "controller: '%s' is not an evpn controller", controller)
+			}
+		}
+		for _, node := range splitList(zone["nodes"]) {
+			if _, ok := s.nodes[node]; !ok {
+				return errorf(400, "nodes: no such node '%s'", node)
+			}
+		}
+		return nil
+	}
+	n.zones.remove = func(id string) error {
+		for _, vnet := range sortedKeys(n.vnets.items) {
+			if n.vnets.items[vnet]["zone"] == id {
+				return errorf(500, "cannot delete zone %s, it is used by vnet %s", id, vnet)
+			}
+		}
+		return nil
+	}
+
+	n.vnets.validate = func(id string, vnet map[string]string) error {
+		if err := sdnCheckID("vnet", id); err != nil {
+			return err
+		}
+		zone, ok := n.zones.items[vnet["zone"]]
+		if !ok {
+			return errorf(400, "zone: zone '%s' does not exist", vnet["zone"])
+		}
+		tag, _ := strconv.Atoi(vnet["tag"])
+		switch zone["type"] {
+		case "simple":
+			if vnet["tag"] != "" {
+				return errorf(400, "tag: simple zones do not support a tag")
+			}
+		case "vlan", "qinq":
+			if tag < 1 || tag > 4094 {
+				return errorf(400, "tag: the vnet of a %s zone needs a tag between 1 and 4094", zone["type"])
+			}
+		default:
+			if tag < 1 || tag > 16777215 {
+				return errorf(400, "tag: the vnet of a %s zone needs a tag between 1 and 16777215", zone["type"])
+			}
+		}
+		return nil
+	}
+	n.vnets.remove = func(id string) error {
+		for _, subnet := range sortedKeys(n.subnets.items) {
+			if n.subnets.items[subnet]["vnet"] == id {
+				return errorf(500, "cannot delete vnet %s, it has subnet %s", id, subnet)
+			}
+		}
+		return nil
+	}
+
+	n.controllers.validate = func(id string, controller map[string]string) error {
+		required, ok := sdnControllerRequired[controller["type"]]
+		if !ok {
+			return errorf(400, "type: value '%s' does not have a value in the enumeration 'bgp, evpn, isis'", controller["type"])
+		}
+		if err := sdnCheckRequired(required, controller); err != nil {
+			return err
+		}
+		if node := controller["node"]; node != "" {
+			if _, ok := s.nodes[node]; !ok {
+				return errorf(400, "node: no such node '%s'", node)
+			}
+		}
+		return nil
+	}
+	n.controllers.remove = func(id string) error {
+		for _, zone := range sortedKeys(n.zones.items) {
+			if n.zones.items[zone]["controller"] == id {
+				return errorf(500, "cannot delete controller %s, it is used by zone %s", id, zone)
+			}
+		}
+		return nil
+	}
+
+	// The list endpoints are registered before the collections, so they take precedence and can show the pending state.
+	for path, c := range map[string]*collection{`/cluster/sdn/zones`: n.zones, `/cluster/sdn/vnets`: n.vnets, `/cluster/sdn/controllers`: n.controllers} {
+		s.handle("GET", path, func(r *request) (any, error) {
+			var filter func(map[string]string) bool
+			if t := r.get("type"); t != "" {
+				filter = func(item map[string]string) bool { return item["type"] == t }
+			}
+			return n.list(c, r, filter, c.api), nil
+		})
+		s.registerCollection(path, c)
+	}
+
+	vnetSubnet := func(r *request) (map[string]string, error) {
+		subnet, err := n.subnets.get(r.vars[1])
+		if err != nil || subnet["vnet"] != r.vars[0] {
+			return nil, errorf(500, "subnet '%s' does not exist in vnet '%s'", r.vars[1], r.vars[0])
+		}
+		return subnet, nil
+	}
+	s.handle("GET", `/cluster/sdn/vnets/([^/]+)/subnets`, func(r *request) (any, error) {
+		if _, err := n.vnets.get(r.vars[0]); err != nil {
+			return nil, err
+		}
+		return n.list(n.subnets, r, func(item map[string]string) bool { return item["vnet"] == r.vars[0] }, n.subnetAPI), nil
+	})
+	s.handle("GET", `/cluster/sdn/vnets/([^/]+)/subnets/([^/]+)`, func(r *request) (any, error) {
+		if _, err := vnetSubnet(r); err != nil {
+			return nil, err
+		}
+		return n.subnetAPI(r.vars[1]), nil
+	})
+	s.handle("POST", `/cluster/sdn/vnets/([^/]+)/subnets`, func(r *request) (any, error) {
+		vnet, err := n.vnets.get(r.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		if r.get("type") != "subnet" {
+			return nil, errorf(400, "parameter verification failed: type: value must be 'subnet'")
+		}
+		ip, cidr, err := net.ParseCIDR(r.get("subnet"))
+		if err != nil || !ip.Equal(cidr.IP) {
+			return nil, errorf(400, "subnet: invalid CIDR '%s'", r.get("subnet"))
+		}
+		id := subnetID(vnet["zone"], cidr)
+		if _, ok := n.subnets.items[id]; ok {
+			return nil, errorf(500, "subnet '%s' already exists", id)
+		}
+		ones, _ := cidr.Mask.Size()
+		subnet := map[string]string{"type": "subnet", "vnet": r.vars[0], "zone": vnet["zone"], "cidr": cidr.String(),
+			"network": cidr.IP.String(), "mask": strconv.Itoa(ones)}
+		if err := n.applySubnet(subnet, r); err != nil {
+			return nil, err
+		}
+		n.subnets.items[id] = subnet
+		return nil, nil
+	})
+	s.handle("PUT", `/cluster/sdn/vnets/([^/]+)/subnets/([^/]+)`, func(r *request) (any, error) {
+		subnet, err := vnetSubnet(r)
+		if err != nil {
+			return nil, err
+		}
+		updated := make(map[string]string, len(subnet))
+		for k, v := range subnet {
+			updated[k] = v
+		}
+		if err := n.applySubnet(updated, r); err != nil {
+			return nil, err
+		}
+		n.subnets.items[r.vars[1]] = updated
+		return nil, nil
+	})
+	s.handle("DELETE", `/cluster/sdn/vnets/([^/]+)/subnets/([^/]+)`, func(r *request) (any, error) {
+		if _, err := vnetSubnet(r); err != nil {
+			return nil, err
+		}
+		delete(n.subnets.items, r.vars[1])
+		return nil, nil
+	})
+
+	s.handle("PUT", `/cluster/sdn`, func(r *request) (any, error) {
+		node := sortedKeys(s.nodes)[0]
+		upid := s.newTask(node, "reloadnetworkall", "")
+		if !s.taskFailed(upid) {
+			for _, c := range n.kinds() {
+				n.running[c] = make(map[string]map[string]string, len(c.items))
+				for id, item := range c.items {
+					n.running[c][id] = copyConfig(item)
+				}
+			}
+		}
+		return upid, nil
+	})
+}
+
+// SDN returns the config of an SDN object, kind is "zones", "vnets", "subnets" or "controllers".
+func (s *Server) SDN(kind, id string) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := map[string]*collection{"zones": s.sdn.zones, "vnets": s.sdn.vnets, "subnets": s.sdn.subnets, "controllers": s.sdn.controllers}[kind]
+	if c == nil {
+		return nil, false
+	}
+	item, ok := c.items[id]
+	return item, ok
+}
+
+// SDNPending reports whether the SDN config has changes that are not applied.
+func (s *Server) SDNPending() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sdn.pending()
+}
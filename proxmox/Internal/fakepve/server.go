@@ -0,0 +1,318 @@
+// Package fakepve provides an in-process, stateful stand-in for the Proxmox VE REST API.
+// It is intended to be used by the acceptance tests, so the provider can be exercised end to end without a live cluster.
+package fakepve
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// User is the user that is allowed to log in to the fake API.
+	User = "root@pam"
+	// Password is the password of User.
+	Password = "fakepassword"
+
+	apiPath = "/api2/json"
+
+	defaultNextID = 100
+)
+
+// Server is a fake Proxmox VE API server.
+// All state is kept in memory and is guarded by a single mutex.
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	routes     []route
+	nodes      map[string]*node
+	guests     map[int]*guest
+	pools      map[string]*pool
+	storages   map[string]*storage
+	tasks      map[string]*task
+	ha         map[int]map[string]any
+	haGroups   *collection
+	haRules    *collection
+	backupJobs *collection
+	users      map[string]*user
+	groups     map[string]map[string]string
+	roles      map[string][]string
+	acl        []aclEntry
+	realms     *collection
+	sdn        *sdn
+	fwGroups   map[string]*firewallGroup
+	mappings   map[string]map[string]*mapping
+	replJobs   *collection
+	replState  map[string]map[string]any
+	realmSyncs []string
+	failures   map[string]string
+	forbidden  map[string]struct{}
+	taskSeq    int
+	unknown    []string
+}
+
+type route struct {
+	method  string
+	pattern *regexp.Regexp
+	handler handlerFunc
+}
+
+type handlerFunc func(r *request) (any, error)
+
+type request struct {
+	*http.Request
+	params url.Values
+	vars   []string
+	files  map[string][]byte
+}
+
+// apiError is returned by handlers and rendered the same way PVE renders errors.
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e apiError) Error() string { return e.message }
+
+func errorf(status int, format string, a ...any) error {
+	return apiError{status: status, message: fmt.Sprintf(format, a...)}
+}
+
+// New starts a fake API server with the given nodes. When no nodes are given a single node named "pve" is created.
+// The caller is responsible for calling Close.
+func New(nodes ...string) *Server {
+	if len(nodes) == 0 {
+		nodes = []string{"pve"}
+	}
+	s := &Server{
+		nodes:     make(map[string]*node, len(nodes)),
+		guests:    map[int]*guest{},
+		pools:     map[string]*pool{},
+		storages:  map[string]*storage{},
+		tasks:     map[string]*task{},
+		ha:        map[int]map[string]any{},
+		failures:  map[string]string{},
+		forbidden: map[string]struct{}{},
+	}
+	for _, n := range nodes {
+		s.nodes[n] = newNode(n)
+	}
+	s.AddStorage("local", "dir", true, "iso", "vztmpl", "backup", "snippets", "import")
+	s.AddStorage("local-lvm", "lvmthin", false, "images", "rootdir")
+	s.registerAccess()
+	s.registerRealms()
+	s.registerTasks()
+	s.registerCluster()
+	s.registerHA()
+	s.registerSDN()
+	s.registerFirewall()
+	s.registerMappings()
+	s.registerNodes()
+	s.registerHardware()
+	s.registerNetwork()
+	s.registerGuests()
+	s.registerSnapshots()
+	s.registerPools()
+	s.registerStorage()
+	s.registerBackup()
+	s.registerReplication()
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// ApiURL returns the URL that should be used as `pm_api_url`.
+func (s *Server) ApiURL() string { return s.URL + apiPath }
+
+// Unknown returns the API calls the fake did not know how to handle.
+func (s *Server) Unknown() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.unknown...)
+}
+
+// Forbid makes every request to the path (e.g. "/cluster/sdn/vnets") fail the way PVE fails requests the user lacks
+// the privileges for.
+func (s *Server) Forbid(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.forbidden[path] = struct{}{}
+}
+
+// FailTask makes the next task of the given type (e.g. "qmigrate") fail with the given exit status.
+// An exit status like "WARNINGS: 1" makes the task succeed with warnings instead.
+func (s *Server) FailTask(taskType, exitStatus string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[taskType] = exitStatus
+}
+
+func (s *Server) handle(method, pattern string, h handlerFunc) {
+	s.routes = append(s.routes, route{
+		method:  method,
+		pattern: regexp.MustCompile("^" + pattern + "$"),
+		handler: h})
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, apiPath)
+	req := &request{Request: r, params: url.Values{}}
+	if err := req.parse(); err != nil {
+		writeError(w, errorf(http.StatusBadRequest, "%v", err))
+		return
+	}
+	if path != "/access/ticket" && !authenticated(r) {
+		writeError(w, errorf(http.StatusUnauthorized, "authentication failure"))
+		return
+	}
+	s.mu.Lock()
+	_, forbidden := s.forbidden[path]
+	s.mu.Unlock()
+	if forbidden {
+		writeError(w, errorf(http.StatusForbidden, "Permission check failed (%s)", path))
+		return
+	}
+	for _, rt := range s.routes {
+		if rt.method != r.Method {
+			continue
+		}
+		if m := rt.pattern.FindStringSubmatch(path); m != nil {
+			req.vars = m[1:]
+			s.mu.Lock()
+			data, err := rt.handler(req)
+			s.mu.Unlock()
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			writeData(w, data)
+			return
+		}
+	}
+	s.mu.Lock()
+	s.unknown = append(s.unknown, r.Method+" "+path)
+	s.mu.Unlock()
+	writeError(w, errorf(http.StatusNotImplemented, "Method '%s %s' not implemented", r.Method, path))
+}
+
+// authenticated reports whether the request carries a ticket or an API token.
+func authenticated(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	return strings.HasPrefix(auth, "PVEAPIToken=") || strings.HasPrefix(auth, "PVEAuthCookie=")
+}
+
+func (r *request) parse() error {
+	for k, v := range r.URL.Query() {
+		r.params[k] = v
+	}
+	if r.Method == http.MethodGet || r.Body == nil {
+		return nil
+	}
+	mediaType, mediaParams, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		r.files = map[string][]byte{}
+		reader := multipart.NewReader(r.Body, mediaParams["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			content, err := io.ReadAll(part)
+			if err != nil {
+				return err
+			}
+			if part.FileName() != "" {
+				r.params.Set("filename", part.FileName())
+				r.files[part.FormName()] = content
+				continue
+			}
+			r.params.Add(part.FormName(), string(content))
+		}
+		return nil
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if mediaType == "application/json" {
+		var params map[string]any
+		if err := json.Unmarshal(body, &params); err != nil {
+			return err
+		}
+		for k, v := range params {
+			switch v := v.(type) {
+			case float64:
+				r.params.Set(k, strconv.FormatFloat(v, 'f', -1, 64))
+			case bool:
+				r.params.Set(k, map[bool]string{true: "1", false: "0"}[v])
+			default:
+				r.params.Set(k, fmt.Sprint(v))
+			}
+		}
+		return nil
+	}
+	// The SDK does not always set a content type, PVE treats those bodies as form data.
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range form {
+		r.params[k] = v
+	}
+	return nil
+}
+
+func (r *request) get(key string) string { return r.params.Get(key) }
+
+func (r *request) has(key string) bool { _, ok := r.params[key]; return ok }
+
+func (r *request) int(key string) int {
+	i, _ := strconv.Atoi(r.params.Get(key))
+	return i
+}
+
+func writeData(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	message := err.Error()
+	if e, ok := err.(apiError); ok {
+		status = e.status
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"data": nil, "message": message + "\n"})
+}
+
+// sortedKeys returns the keys of a map in a stable order, so list responses are deterministic.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// typed converts a stored string value to the JSON type PVE would return for the key.
+func typed(key, value string, numeric map[string]struct{}) any {
+	if _, ok := numeric[key]; ok {
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return value
+}
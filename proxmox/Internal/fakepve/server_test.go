@@ -0,0 +1,56 @@
+package fakepve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Server_allocate(t *testing.T) {
+	tests := []struct {
+		name   string
+		guest  string
+		key    string
+		input  string
+		output string
+	}{
+		{name: "new qemu disk", guest: guestQemu, key: "scsi0",
+			input:  "local-lvm:10,format=raw",
+			output: "local-lvm:vm-100-disk-0,format=raw,size=10G"},
+		{name: "new lxc rootfs", guest: guestLxc, key: "rootfs",
+			input:  "local-lvm:4",
+			output: "local-lvm:subvol-100-disk-0,size=4G"},
+		{name: "efi disk", guest: guestQemu, key: "efidisk0",
+			input:  "local-lvm:1,efitype=4m",
+			output: "local-lvm:vm-100-disk-0,efitype=4m,size=4M"},
+		{name: "cloudinit", guest: guestQemu, key: "ide2",
+			input:  "local-lvm:cloudinit",
+			output: "local-lvm:vm-100-cloudinit,media=cdrom"},
+		{name: "existing volume", guest: guestQemu, key: "scsi0",
+			input:  "local-lvm:vm-100-disk-0,size=10G",
+			output: "local-lvm:vm-100-disk-0,size=10G"},
+		{name: "cdrom", guest: guestQemu, key: "ide2",
+			input:  "local:iso/test.iso,media=cdrom",
+			output: "local:iso/test.iso,media=cdrom"},
+		{name: "not a disk", guest: guestQemu, key: "net0",
+			input:  "virtio,bridge=vmbr0",
+			output: "virtio,bridge=vmbr0"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := New()
+			defer s.Close()
+			g := &guest{id: 100, node: "pve", guestType: test.guest, config: map[string]string{}}
+			require.Equal(t, test.output, s.allocate(g, test.key, test.input))
+		})
+	}
+}
+
+func Test_Server_newTask(t *testing.T) {
+	s := New()
+	defer s.Close()
+	require.False(t, s.taskFailed(s.newTask("pve", "qmstart", "100")))
+	s.FailTask("qmstart", "start failed")
+	require.True(t, s.taskFailed(s.newTask("pve", "qmstart", "100")))
+	require.False(t, s.taskFailed(s.newTask("pve", "qmstart", "100")))
+}
@@ -0,0 +1,181 @@
+package fakepve
+
+import (
+	"maps"
+	"strconv"
+	"time"
+)
+
+type snapshot struct {
+	name        string
+	description string
+	parent      string
+	vmstate     bool
+	snaptime    int64
+	config      map[string]string
+}
+
+func (sn *snapshot) api(guestType string) map[string]any {
+	item := map[string]any{
+		"name":        sn.name,
+		"description": sn.description,
+		"snaptime":    sn.snaptime}
+	if sn.parent != "" {
+		item["parent"] = sn.parent
+	}
+	if guestType == guestQemu {
+		item["vmstate"] = 0
+		if sn.vmstate {
+			item["vmstate"] = 1
+		}
+	}
+	return item
+}
+
+func (g *guest) snapshot(name string) (*snapshot, error) {
+	if sn, ok := g.snapshots[name]; ok {
+		return sn, nil
+	}
+	return nil, errorf(500, "snapshot '%s' does not exist", name)
+}
+
+func (s *Server) registerSnapshots() {
+	const snapshotPath = `/nodes/([^/]+)/(qemu|lxc)/(\d+)/snapshot`
+	s.handle("GET", snapshotPath, func(r *request) (any, error) {
+		g, err := s.guest(r.vars[0], r.vars[1], r.vars[2])
+		if err != nil {
+			return nil, err
+		}
+		list := []any{}
+		for _, name := range sortedKeys(g.snapshots) {
+			list = append(list, g.snapshots[name].api(g.guestType))
+		}
+		current := map[string]any{"name": "current", "description": "You are here!"}
+		if g.parent != "" {
+			current["parent"] = g.parent
+		}
+		if g.status == "running" {
+			current["running"] = 1
+		}
+		return append(list, current), nil
+	})
+	s.handle("POST", snapshotPath, func(r *request) (any, error) {
+		g, err := s.guest(r.vars[0], r.vars[1], r.vars[2])
+		if err != nil {
+			return nil, err
+		}
+		name := r.get("snapname")
+		if _, ok := g.snapshots[name]; ok || name == "current" {
+			return nil, errorf(500, "snapshot name '%s' already used", name)
+		}
+		if g.template() {
+			return nil, errorf(500, "you can't take a snapshot if it's a template")
+		}
+		upid := s.newTask(g.node, map[string]string{guestQemu: "qmsnapshot", guestLxc: "vzsnapshot"}[g.guestType], strconv.Itoa(g.id))
+		if s.taskFailed(upid) {
+			return upid, nil
+		}
+		if g.snapshots == nil {
+			g.snapshots = map[string]*snapshot{}
+		}
+		g.snapshots[name] = &snapshot{
+			name:        name,
+			description: r.get("description"),
+			parent:      g.parent,
+			// PVE only saves the RAM of running guests.
+			vmstate:  g.guestType == guestQemu && r.get("vmstate") == "1" && g.status == "running",
+			snaptime: time.Now().Unix(),
+			config:   maps.Clone(g.config)}
+		g.parent = name
+		return upid, nil
+	})
+	s.handle("GET", snapshotPath+`/([^/]+)/config`, func(r *request) (any, error) {
+		g, err := s.guest(r.vars[0], r.vars[1], r.vars[2])
+		if err != nil {
+			return nil, err
+		}
+		sn, err := g.snapshot(r.vars[3])
+		if err != nil {
+			return nil, err
+		}
+		config := map[string]any{"description": sn.description, "snaptime": sn.snaptime}
+		for k, v := range sn.config {
+			if k != "description" {
+				config[k] = typed(k, v, g.numeric())
+			}
+		}
+		return config, nil
+	})
+	s.handle("PUT", snapshotPath+`/([^/]+)/config`, func(r *request) (any, error) {
+		g, err := s.guest(r.vars[0], r.vars[1], r.vars[2])
+		if err != nil {
+			return nil, err
+		}
+		sn, err := g.snapshot(r.vars[3])
+		if err != nil {
+			return nil, err
+		}
+		sn.description = r.get("description")
+		return nil, nil
+	})
+	s.handle("DELETE", snapshotPath+`/([^/]+)`, func(r *request) (any, error) {
+		g, err := s.guest(r.vars[0], r.vars[1], r.vars[2])
+		if err != nil {
+			return nil, err
+		}
+		taskType := map[string]string{guestQemu: "qmdelsnapshot", guestLxc: "vzdelsnapshot"}[g.guestType]
+		sn, err := g.snapshot(r.vars[3])
+		if err != nil {
+			return s.failedTask(g.node, taskType, strconv.Itoa(g.id), err.Error()), nil
+		}
+		upid := s.newTask(g.node, taskType, strconv.Itoa(g.id))
+		if s.taskFailed(upid) {
+			return upid, nil
+		}
+		// Children of the removed snapshot are attached to its parent.
+		for _, child := range g.snapshots {
+			if child.parent == sn.name {
+				child.parent = sn.parent
+			}
+		}
+		if g.parent == sn.name {
+			g.parent = sn.parent
+		}
+		delete(g.snapshots, sn.name)
+		return upid, nil
+	})
+	s.handle("POST", snapshotPath+`/([^/]+)/rollback`, func(r *request) (any, error) {
+		g, err := s.guest(r.vars[0], r.vars[1], r.vars[2])
+		if err != nil {
+			return nil, err
+		}
+		sn, err := g.snapshot(r.vars[3])
+		if err != nil {
+			return nil, err
+		}
+		upid := s.newTask(g.node, map[string]string{guestQemu: "qmrollback", guestLxc: "vzrollback"}[g.guestType], strconv.Itoa(g.id))
+		if s.taskFailed(upid) {
+			return upid, nil
+		}
+		g.config = maps.Clone(sn.config)
+		g.parent = sn.name
+		g.rollbacks++
+		switch {
+		case sn.vmstate || r.get("start") == "1":
+			g.status, g.started = "running", time.Now()
+		default:
+			g.status = "stopped"
+		}
+		return upid, nil
+	})
+}
+
+// Rollbacks returns how often the guest was rolled back to a snapshot.
+func (s *Server) Rollbacks(id int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g, ok := s.guests[id]; ok {
+		return g.rollbacks
+	}
+	return 0
+}
@@ -0,0 +1,625 @@
+package fakepve
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/tls"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const storageSize = 1024 * 1024 * 1024 * 1024
+
+type storage struct {
+	name        string
+	storageType string
+	shared      bool
+	content     []string
+	nodes       []string
+	// options are the other options of the storage config, like `path` or `server`.
+	options map[string]string
+	volumes map[string]*volume
+}
+
+// storageContent are the content types each storage type supports.
+var storageContent = map[string][]string{
+	"dir":     {"images", "rootdir", "vztmpl", "iso", "backup", "snippets", "import"},
+	"nfs":     {"images", "rootdir", "vztmpl", "iso", "backup", "snippets", "import"},
+	"cifs":    {"images", "rootdir", "vztmpl", "iso", "backup", "snippets", "import"},
+	"cephfs":  {"vztmpl", "iso", "backup", "snippets", "import"},
+	"lvm":     {"images", "rootdir"},
+	"lvmthin": {"images", "rootdir"},
+	"zfspool": {"images", "rootdir"},
+	"rbd":     {"images", "rootdir"},
+	"pbs":     {"backup"},
+}
+
+// transferContent are the content types PVE accepts uploads and downloads of, other content has to be copied onto the node.
+var transferContent = []string{"iso", "vztmpl", "import"}
+
+// storageRequired are the options each storage type requires.
+var storageRequired = map[string][]string{
+	"dir":     {"path"},
+	"nfs":     {"server", "export"},
+	"cifs":    {"server", "share"},
+	"lvm":     {"vgname"},
+	"lvmthin": {"vgname", "thinpool"},
+	"zfspool": {"pool"},
+	"pbs":     {"server", "datastore", "username"},
+}
+
+// storageFixed are the options that can not be changed after the storage is created.
+var storageFixed = []string{"path", "server", "export", "share", "vgname", "thinpool", "datastore", "fs-name"}
+
+// storageShared are the storage types that are always shared between the nodes.
+var storageShared = map[string]bool{"nfs": true, "cifs": true, "cephfs": true, "rbd": true, "pbs": true}
+
+var rxBackupName = regexp.MustCompile(`^vzdump-(?:qemu|lxc|openvz)-(\d+)-(\d{4}_\d{2}_\d{2}-\d{2}_\d{2}_\d{2})\.`)
+
+var (
+	storageNumeric = map[string]struct{}{"shared": {}, "disable": {}, "port": {}, "krbd": {}, "sparse": {}, "saferemove": {}}
+	storageHidden  = map[string]struct{}{"password": {}, "keyring": {}, "encryption-key": {}}
+)
+
+type volume struct {
+	volid   string
+	node    string
+	content string
+	format  string
+	vmid    int
+	size    int64
+	data    []byte
+	ctime   int64
+}
+
+// AddStorage adds a storage to the fake cluster. Shared storages expose the same volumes on every node.
+func (s *Server) AddStorage(name, storageType string, shared bool, content ...string) {
+	if s.Server != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+	s.storages[name] = &storage{
+		name:        name,
+		storageType: storageType,
+		shared:      shared,
+		content:     content,
+		options:     map[string]string{},
+		volumes:     map[string]*volume{}}
+}
+
+// StorageConfig returns the stored config of a storage, including the secrets PVE never returns.
+func (s *Server) StorageConfig(name string) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.storages[name]
+	if !ok {
+		return nil, false
+	}
+	config := map[string]string{"type": st.storageType, "content": strings.Join(st.content, ",")}
+	for k, v := range st.options {
+		config[k] = v
+	}
+	if st.shared {
+		config["shared"] = "1"
+	}
+	if len(st.nodes) > 0 {
+		config["nodes"] = strings.Join(st.nodes, ",")
+	}
+	return config, true
+}
+
+// PutFile stores a file on a storage, as if it had been uploaded out of band.
+func (s *Server) PutFile(node, storageName, content, filename string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if st, ok := s.storages[storageName]; ok {
+		st.putFile(node, content, filename, data)
+	}
+}
+
+func (st *storage) availableOn(node string) bool {
+	if len(st.nodes) == 0 {
+		return true
+	}
+	for _, n := range st.nodes {
+		if n == node {
+			return true
+		}
+	}
+	return false
+}
+
+func (st *storage) supports(content string) bool {
+	for _, c := range st.content {
+		if c == content {
+			return true
+		}
+	}
+	return false
+}
+
+// used returns the space taken by the volumes of the storage on the node.
+func (st *storage) used(node string) int64 {
+	var used int64
+	for _, v := range st.volumes {
+		if v.node == st.nodeKey(node) {
+			used += v.size
+		}
+	}
+	return used
+}
+
+func (st *storage) resource(node string) map[string]any {
+	shared := 0
+	if st.shared {
+		shared = 1
+	}
+	return map[string]any{
+		"id":         "storage/" + node + "/" + st.name,
+		"type":       "storage",
+		"storage":    st.name,
+		"node":       node,
+		"plugintype": st.storageType,
+		"content":    strings.Join(st.content, ","),
+		"shared":     shared,
+		"status":     "available",
+		"disk":       st.used(node),
+		"maxdisk":    storageSize}
+}
+
+func (st *storage) config() map[string]any {
+	config := map[string]any{
+		"storage": st.name,
+		"type":    st.storageType,
+		"content": strings.Join(st.content, ",")}
+	if st.shared {
+		config["shared"] = 1
+	}
+	if len(st.nodes) > 0 {
+		config["nodes"] = strings.Join(st.nodes, ",")
+	}
+	for k, v := range st.options {
+		if _, ok := storageHidden[k]; !ok {
+			config[k] = typed(k, v, storageNumeric)
+		}
+	}
+	return config
+}
+
+// apply sets the parameters of a create or update request on the storage.
+func (st *storage) apply(r *request, create bool) error {
+	for key := range r.params {
+		switch key {
+		case "storage", "type", "delete", "digest":
+			continue
+		case "content":
+			content := splitList(r.get(key))
+			for _, c := range content {
+				if !contains(storageContent[st.storageType], c) {
+					return errorf(400, "storage does not support content type '%s'", c)
+				}
+			}
+			st.content = content
+		case "nodes":
+			st.nodes = splitList(r.get(key))
+		case "shared":
+			if !storageShared[st.storageType] {
+				st.shared = r.get(key) == "1"
+			}
+		default:
+			if !create && contains(storageFixed, key) && r.get(key) != st.options[key] {
+				return errorf(400, "can't change value of fixed parameter '%s'", key)
+			}
+			st.options[key] = r.get(key)
+		}
+	}
+	for _, key := range splitList(r.get("delete")) {
+		switch key {
+		case "nodes":
+			st.nodes = nil
+		case "shared":
+			st.shared = storageShared[st.storageType]
+		default:
+			if contains(storageFixed, key) {
+				return errorf(400, "can't delete fixed parameter '%s'", key)
+			}
+			delete(st.options, key)
+		}
+	}
+	for _, key := range storageRequired[st.storageType] {
+		if st.options[key] == "" {
+			return errorf(400, "missing value for required option '%s'", key)
+		}
+	}
+	return nil
+}
+
+func contains(list []string, value string) bool {
+	for _, e := range list {
+		if e == value {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeKey returns the node volumes are tracked under; shared storages track all volumes under one key.
+func (st *storage) nodeKey(node string) string {
+	if st.shared {
+		return ""
+	}
+	return node
+}
+
+func (st *storage) addVolume(node, name, content string, vmid int, size string) {
+	v := &volume{
+		volid:   st.name + ":" + name,
+		node:    st.nodeKey(node),
+		content: content,
+		format:  "raw",
+		vmid:    vmid,
+		size:    parseSize(size),
+		ctime:   time.Now().Unix()}
+	if content == "rootdir" {
+		v.format = "subvol"
+	}
+	st.volumes[v.node+"/"+v.volid] = v
+}
+
+func (st *storage) putFile(node, content, filename string, data []byte) *volume {
+	v := &volume{
+		volid:   st.name + ":" + content + "/" + filename,
+		node:    st.nodeKey(node),
+		content: content,
+		format:  fileFormat(content, filename),
+		size:    int64(len(data)),
+		data:    data,
+		ctime:   time.Now().Unix()}
+	// Like PVE, the guest and creation time of a backup are taken from the name of the archive.
+	if m := rxBackupName.FindStringSubmatch(filename); content == "backup" && m != nil {
+		v.vmid, _ = strconv.Atoi(m[1])
+		if t, err := time.ParseInLocation("2006_01_02-15_04_05", m[2], time.Local); err == nil {
+			v.ctime = t.Unix()
+		}
+	}
+	st.volumes[v.node+"/"+v.volid] = v
+	return v
+}
+
+func (st *storage) volume(node, volid string) (*volume, bool) {
+	v, ok := st.volumes[st.nodeKey(node)+"/"+volid]
+	return v, ok
+}
+
+func (st *storage) removeVolume(node, volid string) {
+	delete(st.volumes, st.nodeKey(node)+"/"+volid)
+}
+
+func (st *storage) list(node, content string, vmid int) []any {
+	list := []any{}
+	for _, key := range sortedKeys(st.volumes) {
+		v := st.volumes[key]
+		if v.node != st.nodeKey(node) || (content != "" && v.content != content) || (vmid != 0 && v.vmid != vmid) {
+			continue
+		}
+		item := map[string]any{
+			"volid":   v.volid,
+			"content": v.content,
+			"format":  v.format,
+			"size":    v.size,
+			"ctime":   v.ctime}
+		if v.vmid != 0 {
+			item["vmid"] = v.vmid
+		}
+		list = append(list, item)
+	}
+	return list
+}
+
+func fileFormat(content, filename string) string {
+	switch content {
+	case "iso":
+		return "iso"
+	case "vztmpl":
+		return "tgz"
+	case "snippets":
+		return "snippet"
+	}
+	if i := strings.LastIndex(filename, "."); i >= 0 {
+		return filename[i+1:]
+	}
+	return "raw"
+}
+
+// parseSize parses sizes like "10G" or "4M" to bytes.
+func parseSize(raw string) int64 {
+	units := map[byte]int64{'K': 1 << 10, 'M': 1 << 20, 'G': 1 << 30, 'T': 1 << 40}
+	if raw == "" {
+		return 0
+	}
+	multiplier := int64(1)
+	if m, ok := units[raw[len(raw)-1]]; ok {
+		multiplier = m
+		raw = raw[:len(raw)-1]
+	}
+	f, _ := strconv.ParseFloat(raw, 64)
+	return int64(f * float64(multiplier))
+}
+
+// formatSize formats bytes the way PVE reports the size of volumes, e.g. "10G" or "512M".
+func formatSize(bytes int64) string {
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"T", 1 << 40}, {"G", 1 << 30}, {"M", 1 << 20}} {
+		if bytes >= unit.size && bytes%unit.size == 0 {
+			return strconv.FormatInt(bytes/unit.size, 10) + unit.suffix
+		}
+	}
+	return strconv.FormatInt((bytes+1023)/1024, 10) + "K"
+}
+
+// findVolume returns the volume with the given volume ID that is available on the node, or nil.
+func (s *Server) findVolume(node, volid string) *volume {
+	storageName, _, _ := strings.Cut(volid, ":")
+	if st, ok := s.storages[storageName]; ok {
+		if v, ok := st.volume(node, volid); ok {
+			return v
+		}
+	}
+	return nil
+}
+
+func (s *Server) storage(node, name string) (*storage, error) {
+	if _, err := s.node(node); err != nil {
+		return nil, err
+	}
+	st, ok := s.storages[name]
+	if !ok || !st.availableOn(node) {
+		return nil, errorf(500, "storage '%s' does not exist", name)
+	}
+	return st, nil
+}
+
+func newHash(algorithm string) hash.Hash {
+	switch algorithm {
+	case "md5":
+		return md5.New()
+	case "sha1":
+		return sha1.New()
+	case "sha224":
+		return sha256.New224()
+	case "sha256":
+		return sha256.New()
+	case "sha384":
+		return sha512.New384()
+	case "sha512":
+		return sha512.New()
+	}
+	return nil
+}
+
+func (s *Server) registerStorage() {
+	s.handle("GET", `/storage`, func(r *request) (any, error) {
+		list := []any{}
+		for _, name := range sortedKeys(s.storages) {
+			list = append(list, s.storages[name].config())
+		}
+		return list, nil
+	})
+	s.handle("GET", `/storage/([^/]+)`, func(r *request) (any, error) {
+		st, ok := s.storages[r.vars[0]]
+		if !ok {
+			return nil, errorf(500, "storage '%s' does not exist", r.vars[0])
+		}
+		return st.config(), nil
+	})
+	s.handle("POST", `/storage`, func(r *request) (any, error) {
+		name, storageType := r.get("storage"), r.get("type")
+		if _, ok := s.storages[name]; ok {
+			return nil, errorf(500, "create storage failed: storage ID '%s' already defined", name)
+		}
+		content, ok := storageContent[storageType]
+		if !ok {
+			return nil, errorf(400, "invalid storage type '%s'", storageType)
+		}
+		st := &storage{
+			name:        name,
+			storageType: storageType,
+			shared:      storageShared[storageType],
+			content:     []string{content[0]},
+			options:     map[string]string{},
+			volumes:     map[string]*volume{}}
+		if err := st.apply(r, true); err != nil {
+			return nil, err
+		}
+		switch storageType {
+		case "nfs", "cifs", "cephfs":
+			if st.options["path"] == "" {
+				st.options["path"] = "/mnt/pve/" + name
+			}
+		}
+		s.storages[name] = st
+		return map[string]any{"storage": name, "type": storageType}, nil
+	})
+	s.handle("PUT", `/storage/([^/]+)`, func(r *request) (any, error) {
+		st, ok := s.storages[r.vars[0]]
+		if !ok {
+			return nil, errorf(500, "update storage failed: storage '%s' does not exist", r.vars[0])
+		}
+		if r.has("type") {
+			return nil, errorf(400, "can't change the type of a storage")
+		}
+		updated := *st
+		updated.options = make(map[string]string, len(st.options))
+		for k, v := range st.options {
+			updated.options[k] = v
+		}
+		if err := updated.apply(r, false); err != nil {
+			return nil, err
+		}
+		*st = updated
+		return nil, nil
+	})
+	s.handle("DELETE", `/storage/([^/]+)`, func(r *request) (any, error) {
+		if _, ok := s.storages[r.vars[0]]; !ok {
+			return nil, errorf(500, "delete storage failed: storage '%s' does not exist", r.vars[0])
+		}
+		delete(s.storages, r.vars[0])
+		for _, p := range s.pools {
+			delete(p.storages, r.vars[0])
+		}
+		return nil, nil
+	})
+	s.handle("GET", `/nodes/([^/]+)/storage`, func(r *request) (any, error) {
+		if _, err := s.node(r.vars[0]); err != nil {
+			return nil, err
+		}
+		list := []any{}
+		for _, name := range sortedKeys(s.storages) {
+			if st := s.storages[name]; st.availableOn(r.vars[0]) && (r.get("content") == "" || st.supports(r.get("content"))) {
+				res := st.resource(r.vars[0])
+				res["type"], res["active"], res["enabled"] = st.storageType, 1, 1
+				res["total"], res["used"], res["avail"] = storageSize, st.used(r.vars[0]), storageSize-st.used(r.vars[0])
+				list = append(list, res)
+			}
+		}
+		return list, nil
+	})
+	s.handle("GET", `/nodes/([^/]+)/storage/([^/]+)/status`, func(r *request) (any, error) {
+		st, err := s.storage(r.vars[0], r.vars[1])
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{
+			"type":    st.storageType,
+			"content": strings.Join(st.content, ","),
+			"active":  1,
+			"enabled": 1,
+			"total":   storageSize,
+			"used":    st.used(r.vars[0]),
+			"avail":   storageSize - st.used(r.vars[0])}, nil
+	})
+	s.handle("GET", `/nodes/([^/]+)/storage/([^/]+)/content`, func(r *request) (any, error) {
+		st, err := s.storage(r.vars[0], r.vars[1])
+		if err != nil {
+			return nil, err
+		}
+		return st.list(r.vars[0], r.get("content"), r.int("vmid")), nil
+	})
+	s.handle("GET", `/nodes/([^/]+)/storage/([^/]+)/content/(.+)`, func(r *request) (any, error) {
+		st, err := s.storage(r.vars[0], r.vars[1])
+		if err != nil {
+			return nil, err
+		}
+		v, ok := st.volume(r.vars[0], r.vars[2])
+		if !ok {
+			return nil, errorf(500, "volume '%s' does not exist", r.vars[2])
+		}
+		return map[string]any{"path": "/fake/" + v.volid, "format": v.format, "size": v.size, "used": v.size}, nil
+	})
+	s.handle("DELETE", `/nodes/([^/]+)/storage/([^/]+)/content/(.+)`, func(r *request) (any, error) {
+		st, err := s.storage(r.vars[0], r.vars[1])
+		if err != nil {
+			return nil, err
+		}
+		volid := r.vars[2]
+		if !strings.Contains(volid, ":") {
+			volid = st.name + ":" + volid
+		}
+		if _, ok := st.volume(r.vars[0], volid); !ok {
+			return nil, errorf(500, "volume '%s' does not exist", volid)
+		}
+		st.removeVolume(r.vars[0], volid)
+		return s.newTask(r.vars[0], "imgdel", volid), nil
+	})
+	s.handle("POST", `/nodes/([^/]+)/storage/([^/]+)/upload`, func(r *request) (any, error) {
+		st, err := s.storage(r.vars[0], r.vars[1])
+		if err != nil {
+			return nil, err
+		}
+		content := r.get("content")
+		if !contains(transferContent, content) {
+			return nil, errorf(400, "upload content type '%s' not allowed", content)
+		}
+		if !st.supports(content) {
+			return nil, errorf(500, "storage '%s' does not support content type '%s'", st.name, content)
+		}
+		data, ok := r.files["filename"]
+		if !ok {
+			return nil, errorf(400, "missing file")
+		}
+		if algorithm := r.get("checksum-algorithm"); algorithm != "" {
+			if err := verifyChecksum(data, algorithm, r.get("checksum")); err != nil {
+				return s.failedTask(r.vars[0], "imgcopy", "", err.Error()), nil
+			}
+		}
+		upid := s.newTask(r.vars[0], "imgcopy", "")
+		if !s.taskFailed(upid) {
+			st.putFile(r.vars[0], content, r.get("filename"), data)
+		}
+		return upid, nil
+	})
+	s.handle("POST", `/nodes/([^/]+)/storage/([^/]+)/download-url`, func(r *request) (any, error) {
+		st, err := s.storage(r.vars[0], r.vars[1])
+		if err != nil {
+			return nil, err
+		}
+		content := r.get("content")
+		if !contains(transferContent, content) {
+			return nil, errorf(400, "parameter verification failed: content: value '%s' does not have a value in the enumeration '%s'", content, strings.Join(transferContent, ", "))
+		}
+		if !st.supports(content) {
+			return nil, errorf(500, "storage '%s' does not support content type '%s'", st.name, content)
+		}
+		data, err := download(r.get("url"), r.get("verify-certificates") == "0")
+		if err != nil {
+			return s.failedTask(r.vars[0], "download", st.name, err.Error()), nil
+		}
+		if algorithm := r.get("checksum-algorithm"); algorithm != "" {
+			if err := verifyChecksum(data, algorithm, r.get("checksum")); err != nil {
+				return s.failedTask(r.vars[0], "download", st.name, err.Error()), nil
+			}
+		}
+		upid := s.newTask(r.vars[0], "download", st.name, "downloading "+r.get("url"))
+		if !s.taskFailed(upid) {
+			st.putFile(r.vars[0], content, r.get("filename"), data)
+		}
+		return upid, nil
+	})
+}
+
+func verifyChecksum(data []byte, algorithm, expected string) error {
+	h := newHash(algorithm)
+	if h == nil {
+		return errorf(400, "unknown checksum algorithm '%s'", algorithm)
+	}
+	h.Write(data)
+	if got := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(got, expected) {
+		return errorf(500, "checksum mismatch: got '%s' != expected '%s'", got, expected)
+	}
+	return nil
+}
+
+func download(url string, insecure bool) ([]byte, error) {
+	client := &http.Client{
+		Timeout:   time.Minute,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure}}}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errorf(500, "download failed: %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
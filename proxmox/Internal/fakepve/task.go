@@ -0,0 +1,123 @@
+package fakepve
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type task struct {
+	upid       string
+	node       string
+	taskType   string
+	id         string
+	exitStatus string
+	log        []string
+	start      int64
+	seq        int
+}
+
+// newTask registers a finished task and returns its UPID.
+// Tasks complete instantly, unless a failure was registered with FailTask for the task type.
+func (s *Server) newTask(node, taskType, id string, log ...string) string {
+	s.taskSeq++
+	upid := fmt.Sprintf("UPID:%s:%08X:%08X:%08X:%s:%s:%s:", node, s.taskSeq, s.taskSeq, time.Now().Unix(), taskType, id, User)
+	t := &task{
+		upid:       upid,
+		node:       node,
+		taskType:   taskType,
+		id:         id,
+		exitStatus: "OK",
+		log:        append(log, "TASK OK"),
+		start:      time.Now().Unix(),
+		seq:        s.taskSeq}
+	if msg, ok := s.failures[taskType]; ok {
+		delete(s.failures, taskType)
+		t.exitStatus = msg
+		t.log = append(log[:len(log):len(log)], "TASK ERROR: "+msg)
+		if strings.HasPrefix(msg, "WARNINGS") {
+			t.log[len(t.log)-1] = "TASK " + msg
+		}
+	}
+	s.tasks[upid] = t
+	return upid
+}
+
+// failedTask registers a task that failed with the given exit status and returns its UPID.
+func (s *Server) failedTask(node, taskType, id, exitStatus string) string {
+	s.failures[taskType] = exitStatus
+	return s.newTask(node, taskType, id)
+}
+
+// taskFailed reports whether the last task registered for the UPID failed, a task that finished with warnings succeeded.
+func (s *Server) taskFailed(upid string) bool {
+	if t, ok := s.tasks[upid]; ok {
+		return t.exitStatus != "OK" && !strings.HasPrefix(t.exitStatus, "WARNINGS")
+	}
+	return false
+}
+
+func (s *Server) registerTasks() {
+	s.handle("GET", `/nodes/([^/]+)/tasks`, func(r *request) (any, error) {
+		if _, err := s.node(r.vars[0]); err != nil {
+			return nil, err
+		}
+		limit, _ := strconv.Atoi(r.get("limit"))
+		if limit == 0 {
+			limit = 50
+		}
+		tasks := []*task{}
+		for _, t := range s.tasks {
+			if t.node != r.vars[0] ||
+				(r.get("vmid") != "" && t.id != r.get("vmid")) ||
+				(r.get("typefilter") != "" && t.taskType != r.get("typefilter")) ||
+				(r.get("errors") == "1" && t.exitStatus == "OK") {
+				continue
+			}
+			tasks = append(tasks, t)
+		}
+		// Newest first, like PVE.
+		sort.Slice(tasks, func(i, j int) bool { return tasks[i].seq > tasks[j].seq })
+		list := []any{}
+		for i := 0; i < len(tasks) && i < limit; i++ {
+			list = append(list, map[string]any{
+				"upid":      tasks[i].upid,
+				"node":      tasks[i].node,
+				"type":      tasks[i].taskType,
+				"id":        tasks[i].id,
+				"user":      User,
+				"status":    tasks[i].exitStatus,
+				"starttime": tasks[i].start,
+				"endtime":   tasks[i].start})
+		}
+		return list, nil
+	})
+	s.handle("GET", `/nodes/([^/]+)/tasks/(.+)/status`, func(r *request) (any, error) {
+		t, ok := s.tasks[r.vars[1]]
+		if !ok {
+			return nil, errorf(500, "no such task")
+		}
+		return map[string]any{
+			"upid":       t.upid,
+			"node":       t.node,
+			"type":       t.taskType,
+			"id":         t.id,
+			"user":       User,
+			"status":     "stopped",
+			"exitstatus": t.exitStatus,
+			"starttime":  t.start}, nil
+	})
+	s.handle("GET", `/nodes/([^/]+)/tasks/(.+)/log`, func(r *request) (any, error) {
+		t, ok := s.tasks[r.vars[1]]
+		if !ok {
+			return nil, errorf(500, "no such task")
+		}
+		lines := make([]map[string]any, len(t.log))
+		for i := range t.log {
+			lines[i] = map[string]any{"n": i + 1, "t": t.log[i]}
+		}
+		return lines, nil
+	})
+}
@@ -0,0 +1,60 @@
+package vnet
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	pveAPI "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// rxBridge matches the names PVE allows for the Linux bridges of a node.
+var rxBridge = regexp.MustCompile(`^vmbr\d{1,4}$`)
+
+// Lookup returns the names of the SDN VNets of the cluster and of the bridges of the node of the guest.
+type Lookup func() (map[string]struct{}, error)
+
+// Check returns an error for every bridge that is neither an SDN VNet nor a bridge of the node.
+// Bridges named vmbr<N> are not looked up, so lookup is only called when a guest uses another bridge, like a VNet.
+// When the user may not list the VNets or bridges, the bridges are not checked and a warning is returned instead.
+// key is the name of the attribute that holds the bridges, it is used in the errors.
+func Check(lookup Lookup, key string, bridges []string) diag.Diagnostics {
+	if lookup == nil {
+		return nil
+	}
+	var known map[string]struct{}
+	var diags diag.Diagnostics
+	for _, bridge := range bridges {
+		if bridge == "" || rxBridge.MatchString(bridge) {
+			continue
+		}
+		if known == nil {
+			var err error
+			if known, err = lookup(); err != nil {
+				if permissionDenied(err) {
+					return diag.Diagnostics{{
+						Summary:  "the bridges of " + key + " are not checked, they can't be looked up",
+						Detail:   err.Error(),
+						Severity: diag.Warning}}
+				}
+				return diag.FromErr(err)
+			}
+		}
+		if _, ok := known[bridge]; !ok {
+			diags = append(diags, diag.Diagnostic{
+				Summary:  key + " '" + bridge + "' is neither an SDN VNet nor a bridge of the node",
+				Severity: diag.Error})
+		}
+	}
+	return diags
+}
+
+// permissionDenied reports whether the API refused the request because the user lacks a privilege, e.g. SDN.Audit.
+func permissionDenied(err error) bool {
+	var apiErr *pveAPI.ApiError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == "403"
+	}
+	return strings.HasPrefix(err.Error(), "403 ")
+}
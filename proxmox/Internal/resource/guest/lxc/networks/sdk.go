@@ -1,11 +1,15 @@
 package networks
 
 import (
+	"sort"
+
 	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/_sub/vnet"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// SDK converts the network or networks of the Terraform configuration to the SDK configuration.
 func SDK(version pveSDK.EncodedVersion, d *schema.ResourceData) (pveSDK.LxcNetworks, diag.Diagnostics) {
 	if v, ok := d.GetOk(RootNetwork); ok { // network
 		return sdkNetwork(version, v.([]any))
@@ -21,3 +25,29 @@ func SDK(version pveSDK.EncodedVersion, d *schema.ResourceData) (pveSDK.LxcNetwo
 	}
 	return config, nil
 }
+
+// CheckBridges checks that the bridges of the networks that are not vmbr<N> exist, e.g. an SDN VNet.
+// bridges looks up the bridges of the node the guest is placed on, so it is called once the node is known.
+func CheckBridges(d *schema.ResourceData, config pveSDK.LxcNetworks, bridges vnet.Lookup) diag.Diagnostics {
+	key := RootNetworks + "." + schemaBridge
+	if _, ok := d.GetOk(RootNetwork); ok {
+		key = RootNetwork + "." + schemaBridge
+	}
+	return vnet.Check(bridges, key, sdkBridges(config))
+}
+
+// sdkBridges returns the bridges of the networks that are not deleted, ordered by the ID of the network.
+func sdkBridges(config pveSDK.LxcNetworks) []string {
+	ids := make([]int, 0, len(config))
+	for id, e := range config {
+		if !e.Delete && e.Bridge != nil {
+			ids = append(ids, int(id))
+		}
+	}
+	sort.Ints(ids)
+	bridges := make([]string, len(ids))
+	for i, id := range ids {
+		bridges[i] = *config[pveSDK.LxcNetworkID(id)].Bridge
+	}
+	return bridges
+}
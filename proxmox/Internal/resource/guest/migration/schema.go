@@ -0,0 +1,102 @@
+// Package migration provides the settings used to migrate a guest when its node changes.
+package migration
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	Root              = "migration"
+	onlineKey         = "online"
+	restartKey        = "restart"
+	restartTimeoutKey = "restart_timeout"
+	withLocalDisksKey = "with_local_disks"
+	targetStorageKey  = "target_storage"
+	bandwidthLimitKey = "bandwidth_limit"
+	networkKey        = "network"
+	typeKey           = "type"
+
+	defaultOnline         = true
+	defaultRestart        = true
+	defaultRestartTimeout = 180
+	defaultWithLocalDisks = true
+	defaultBandwidthLimit = 0
+)
+
+// SchemaQemu returns the migration block of a QEMU guest.
+func SchemaQemu() *schema.Schema {
+	settings := commonSchema()
+	settings[onlineKey] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     defaultOnline,
+		Description: "Live migrate the guest when it is running. When false, a running guest can not be migrated.",
+	}
+	settings[withLocalDisksKey] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     defaultWithLocalDisks,
+		Description: "Copy disks on storages that are not shared to the target node.",
+	}
+	settings[networkKey] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validation.IsCIDR,
+		Description:  "The CIDR of the network used for the migration traffic, e.g. '10.0.0.0/24'. Defaults to the migration network of the datacenter.",
+	}
+	settings[typeKey] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validation.StringInSlice([]string{"secure", "insecure"}, false),
+		Description:  "Send the migration traffic over an encrypted tunnel ('secure') or unencrypted ('insecure'). Defaults to the migration type of the datacenter.",
+	}
+	return rootSchema(settings)
+}
+
+// SchemaLxc returns the migration block of an LXC guest.
+func SchemaLxc() *schema.Schema {
+	settings := commonSchema()
+	settings[restartKey] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     defaultRestart,
+		Description: "Containers can not be live migrated, a running container is shut down and started again on the target node. When false, a running container can not be migrated.",
+	}
+	settings[restartTimeoutKey] = &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		Default:      defaultRestartTimeout,
+		ValidateFunc: validation.IntAtLeast(0),
+		Description:  "The number of seconds to wait for the container to shut down before it is stopped.",
+	}
+	return rootSchema(settings)
+}
+
+func commonSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		targetStorageKey: {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringIsNotWhiteSpace,
+			Description:  "The storage on the target node that local disks are migrated to, instead of a storage with the same name.",
+		},
+		bandwidthLimitKey: {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      defaultBandwidthLimit,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "Limit the bandwidth of the migration in KiB/s, 0 uses the default of the datacenter.",
+		},
+	}
+}
+
+func rootSchema(settings map[string]*schema.Schema) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "How the guest is migrated when the target node changes.",
+		Elem:        &schema.Resource{Schema: settings},
+	}
+}
@@ -0,0 +1,88 @@
+package migration
+
+import (
+	pveAPI "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Config holds the settings to migrate a guest to another node.
+type Config struct {
+	Online         bool // QEMU only
+	WithLocalDisks bool // QEMU only
+	Network        string
+	Type           string
+	Restart        bool // LXC only
+	RestartTimeout int  // LXC only
+	TargetStorage  string
+	BandwidthLimit int
+}
+
+// Params returns the parameters of the migrate request that moves a guest of the given type to the target node.
+func (c Config) Params(guestType pveAPI.GuestType, target pveAPI.NodeName) map[string]any {
+	params := map[string]any{"target": target.String()}
+	if c.BandwidthLimit > 0 {
+		params["bwlimit"] = c.BandwidthLimit
+	}
+	if guestType == pveAPI.GuestLxc {
+		if c.Restart {
+			params["restart"] = 1
+			params["timeout"] = c.RestartTimeout
+		}
+		if c.TargetStorage != "" {
+			params["target-storage"] = c.TargetStorage
+		}
+		return params
+	}
+	if c.Online {
+		params["online"] = 1
+	}
+	if c.WithLocalDisks {
+		params["with-local-disks"] = 1
+	}
+	if c.TargetStorage != "" {
+		params["targetstorage"] = c.TargetStorage
+	}
+	if c.Network != "" {
+		params["migration_network"] = c.Network
+	}
+	if c.Type != "" {
+		params["migration_type"] = c.Type
+	}
+	return params
+}
+
+// SDK returns the defaults when the migration block is not set.
+func SDK(d *schema.ResourceData) Config {
+	config := Config{
+		Online:         defaultOnline,
+		WithLocalDisks: defaultWithLocalDisks,
+		Restart:        defaultRestart,
+		RestartTimeout: defaultRestartTimeout,
+		BandwidthLimit: defaultBandwidthLimit}
+	tmpList, ok := d.Get(Root).([]any)
+	if !ok || len(tmpList) == 0 || tmpList[0] == nil {
+		return config
+	}
+	settings := tmpList[0].(map[string]any)
+	if v, ok := settings[onlineKey].(bool); ok {
+		config.Online = v
+	}
+	if v, ok := settings[withLocalDisksKey].(bool); ok {
+		config.WithLocalDisks = v
+	}
+	if v, ok := settings[networkKey].(string); ok {
+		config.Network = v
+	}
+	if v, ok := settings[typeKey].(string); ok {
+		config.Type = v
+	}
+	if v, ok := settings[restartKey].(bool); ok {
+		config.Restart = v
+	}
+	if v, ok := settings[restartTimeoutKey].(int); ok {
+		config.RestartTimeout = v
+	}
+	config.TargetStorage = settings[targetStorageKey].(string)
+	config.BandwidthLimit = settings[bandwidthLimitKey].(int)
+	return config
+}
@@ -10,61 +10,77 @@ import (
 )
 
 const (
-	RootNode  string = "target_node"
-	RootNodes string = "target_nodes"
-	Computed  string = "current_node"
+	RootNode     string = "target_node"
+	RootNodes    string = "target_nodes"
+	RootStrategy string = "placement_strategy"
+	Computed     string = "current_node"
 )
 
 func SchemaNode(s schema.Schema, guestType string) *schema.Schema {
 	s.Type = schema.TypeString
 	s.Optional = true
 	s.Description = "The node the " + guestType + " guest goes to."
-	s.ValidateDiagFunc = func(i interface{}, path cty.Path) diag.Diagnostics {
+	s.ValidateDiagFunc = ValidateName(RootNode)
+	return &s
+}
+
+func SchemaNodes(guestType string) *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeSet,
+		Optional:      true,
+		Description:   "A list of nodes the " + guestType + " guest may be placed on.",
+		MinItems:      1,
+		ConflictsWith: []string{RootNode},
+		Elem: &schema.Schema{
+			Type:             schema.TypeString,
+			ValidateDiagFunc: ValidateName(RootNodes)}}
+}
+
+// ValidateName returns the validation of a node name for the argument key.
+func ValidateName(key string) schema.SchemaValidateDiagFunc {
+	return func(i interface{}, path cty.Path) diag.Diagnostics {
 		v, ok := i.(string)
 		if !ok {
 			return diag.Diagnostics{diag.Diagnostic{
 				Severity:      diag.Error,
-				Summary:       "Invalid " + RootNode,
-				Detail:        RootNode + " must be a string",
+				Summary:       "Invalid " + key,
+				Detail:        key + " must be a string",
 				AttributePath: path}}
 		}
 		if err := pveAPI.NodeName(v).Validate(); err != nil {
 			return diag.Diagnostics{diag.Diagnostic{
 				Severity:      diag.Error,
-				Summary:       "Invalid " + RootNode,
+				Summary:       "Invalid " + key,
 				AttributePath: path}}
 		}
 		return nil
 	}
-	return &s
 }
 
-func SchemaNodes(guestType string) *schema.Schema {
+func SchemaStrategy(guestType string) *schema.Schema {
 	return &schema.Schema{
-		Type:          schema.TypeSet,
-		Optional:      true,
-		Description:   "A list of nodes the " + guestType + " guest may be placed on.",
-		MinItems:      1,
-		ConflictsWith: []string{RootNode},
-		Elem: &schema.Schema{
-			Type: schema.TypeString,
-			ValidateDiagFunc: func(i interface{}, path cty.Path) diag.Diagnostics {
-				v, ok := i.(string)
-				if !ok {
-					return diag.Diagnostics{diag.Diagnostic{
-						Severity:      diag.Error,
-						Summary:       "Invalid " + RootNodes,
-						Detail:        RootNodes + " must be a string",
-						AttributePath: path}}
-				}
-				if err := pveAPI.NodeName(v).Validate(); err != nil {
-					return diag.Diagnostics{diag.Diagnostic{
-						Severity:      diag.Error,
-						Summary:       "Invalid " + RootNodes,
-						AttributePath: path}}
-				}
-				return nil
-			}}}
+		Type:        schema.TypeString,
+		Optional:    true,
+		Default:     string(StrategyRandom),
+		Description: "How the node of the " + guestType + " guest is picked from " + RootNodes + ".",
+		ValidateDiagFunc: func(i interface{}, path cty.Path) diag.Diagnostics {
+			v, ok := i.(string)
+			if !ok {
+				return diag.Diagnostics{diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       "Invalid " + RootStrategy,
+					Detail:        RootStrategy + " must be a string",
+					AttributePath: path}}
+			}
+			if err := Strategy(v).Validate(); err != nil {
+				return diag.Diagnostics{diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       "Invalid " + RootStrategy,
+					Detail:        err.Error(),
+					AttributePath: path}}
+			}
+			return nil
+		}}
 }
 
 func SchemaComputed(guestType string) *schema.Schema {
@@ -11,42 +11,67 @@ import (
 
 const errorNoNodeConfigured = "no target node specified"
 
-func SdkUpdate(d *schema.ResourceData, current pveAPI.NodeName) (pveAPI.NodeName, error) {
-	if node, ok := d.GetOk(RootNode); ok {
-		return pveAPI.NodeName(node.(string)), nil
-	}
-	nodes := d.Get(RootNodes).(*schema.Set).List()
-	currentNode := string(current)
-	switch len(nodes) {
-	case 0:
-		return "", errors.New(errorNoNodeConfigured)
-	case 1:
-		if currentNode != nodes[0].(string) {
-			return pveAPI.NodeName(nodes[0].(string)), nil
-		}
-		return current, nil
+// SdkUpdate keeps the guest on its current node when that node is still in target_nodes and allowed by the filter,
+// otherwise a new node is picked with the placement strategy.
+func SdkUpdate(d *schema.ResourceData, current pveAPI.NodeName, placement *Placement, filter Filter) (pveAPI.NodeName, error) {
+	nodes, err := sdkNodes(d, filter)
+	if err != nil {
+		return "", err
 	}
-	if inArray(nodes, currentNode) {
-		return current, nil
+	for i := range nodes {
+		if nodes[i] == current {
+			return current, nil
+		}
 	}
-	randomIndex := rand.New(rand.NewSource(time.Now().UnixNano())).Intn(len(nodes))
-	return pveAPI.NodeName(nodes[randomIndex].(string)), nil
+	return selectNode(d, nodes, placement)
 }
 
 // SdkCreate selects a node for resource creation.
-func SdkCreate(d *schema.ResourceData) (pveAPI.NodeName, error) {
+func SdkCreate(d *schema.ResourceData, placement *Placement, filter Filter) (pveAPI.NodeName, error) {
+	nodes, err := sdkNodes(d, filter)
+	if err != nil {
+		return "", err
+	}
+	return selectNode(d, nodes, placement)
+}
+
+// sdkNodes returns the configured nodes that are allowed by the filter.
+func sdkNodes(d *schema.ResourceData, filter Filter) ([]pveAPI.NodeName, error) {
+	var nodes []pveAPI.NodeName
 	if node, ok := d.GetOk(RootNode); ok {
-		return pveAPI.NodeName(node.(string)), nil
+		nodes = []pveAPI.NodeName{pveAPI.NodeName(node.(string))}
+	} else {
+		list := d.Get(RootNodes).(*schema.Set).List()
+		nodes = make([]pveAPI.NodeName, len(list))
+		for i := range list {
+			nodes[i] = pveAPI.NodeName(list[i].(string))
+		}
+	}
+	if len(nodes) == 0 {
+		return nil, errors.New(errorNoNodeConfigured)
+	}
+	if filter == nil {
+		return nodes, nil
+	}
+	return filter(nodes)
+}
+
+func selectNode(d *schema.ResourceData, nodes []pveAPI.NodeName, placement *Placement) (pveAPI.NodeName, error) {
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	strategy := StrategyRandom
+	if v, ok := d.Get(RootStrategy).(string); ok && v != "" {
+		strategy = Strategy(v)
+	}
+	if strategy == StrategyRandom || placement == nil {
+		return nodes[rand.New(rand.NewSource(time.Now().UnixNano())).Intn(len(nodes))], nil
 	}
-	nodes := d.Get(RootNodes).(*schema.Set).List()
-	switch len(nodes) {
-	case 0:
-		return "", errors.New(errorNoNodeConfigured)
-	case 1:
-		return pveAPI.NodeName(nodes[0].(string)), nil
+	candidates, err := placement.Candidates(strategy, nodes)
+	if err != nil {
+		return "", err
 	}
-	randomIndex := rand.New(rand.NewSource(time.Now().UnixNano())).Intn(len(nodes))
-	return pveAPI.NodeName(nodes[randomIndex].(string)), nil
+	return strategy.Select(candidates, placement.Rotation)
 }
 
 func inArray(nodes []any, current string) bool {
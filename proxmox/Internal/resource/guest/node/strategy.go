@@ -0,0 +1,144 @@
+package node
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	pveAPI "github.com/Telmate/proxmox-api-go/proxmox"
+)
+
+// Strategy decides on which of the target_nodes a guest is placed.
+type Strategy string
+
+const (
+	// StrategyRandom picks a random node, without looking at the state of the nodes.
+	StrategyRandom Strategy = "random"
+	// StrategyMemory picks the node with the lowest average memory usage over the last hour.
+	StrategyMemory Strategy = "memory"
+	// StrategyGuests picks the node with the fewest guests.
+	StrategyGuests Strategy = "guests"
+	// StrategyStorage picks the node with the most free space on the storage of the guest's largest disk.
+	StrategyStorage Strategy = "storage"
+	// StrategyRoundRobin places the guests on the nodes in turn, in alphabetical order.
+	StrategyRoundRobin Strategy = "round_robin"
+)
+
+var strategies = []Strategy{StrategyRandom, StrategyMemory, StrategyGuests, StrategyStorage, StrategyRoundRobin}
+
+func (s Strategy) Validate() error {
+	for _, e := range strategies {
+		if s == e {
+			return nil
+		}
+	}
+	names := make([]string, len(strategies))
+	for i := range strategies {
+		names[i] = string(strategies[i])
+	}
+	return errors.New("must be one of '" + strings.Join(names, "', '") + "'")
+}
+
+// Candidate holds the live data of a node that the strategies rank the node by.
+type Candidate struct {
+	Name   pveAPI.NodeName
+	Online bool
+	// MemoryPressure is the average fraction of the memory that was in use over the last hour.
+	MemoryPressure float64
+	// Guests is the number of guests on the node.
+	Guests int
+	// Newest is the highest ID of the guests on the node, round robin continues after the node with the newest guest
+	// when the provider did not place a guest on the nodes yet.
+	Newest pveAPI.GuestID
+	// StorageAvailable is the free space in bytes on the storage of the guest, negative when the storage is not available on the node.
+	StorageAvailable int64
+}
+
+// Placement provides what the strategies need to pick a node.
+type Placement struct {
+	// Candidates returns the live data of the nodes, only the data the strategy needs has to be filled in.
+	// It is not called for StrategyRandom.
+	Candidates func(strategy Strategy, nodes []pveAPI.NodeName) ([]Candidate, error)
+	// Rotation remembers the nodes picked by StrategyRoundRobin, it may be nil.
+	Rotation *Rotation
+}
+
+// Rotation remembers the node StrategyRoundRobin picked last for each set of nodes. Guests that are placed by the same
+// run of the provider continue from that node, even when the guests placed before them do not exist yet.
+type Rotation struct {
+	mutex sync.Mutex
+	last  map[string]pveAPI.NodeName
+}
+
+// next returns the node after the previous pick for the nodes, which are sorted by name, and remembers it.
+// Without a previous pick, the node after the one with the newest guest is returned.
+func (r *Rotation) next(usable []Candidate) pveAPI.NodeName {
+	names := make([]string, len(usable))
+	var previous pveAPI.NodeName
+	var newest pveAPI.GuestID
+	for i, e := range usable {
+		names[i] = e.Name.String()
+		if e.Newest > newest {
+			previous, newest = e.Name, e.Newest
+		}
+	}
+	key := strings.Join(names, ",")
+	if r != nil {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		if last, ok := r.last[key]; ok {
+			previous = last
+		}
+	}
+	pick := usable[0].Name
+	if previous != "" {
+		for i, e := range usable {
+			if e.Name == previous {
+				pick = usable[(i+1)%len(usable)].Name
+				break
+			}
+		}
+	}
+	if r != nil {
+		if r.last == nil {
+			r.last = map[string]pveAPI.NodeName{}
+		}
+		r.last[key] = pick
+	}
+	return pick
+}
+
+// Filter returns the nodes a guest may be placed on, it is used to honour the placement group of the guest.
+type Filter func(nodes []pveAPI.NodeName) ([]pveAPI.NodeName, error)
+
+// Select picks one of the candidates, nodes that are offline are skipped.
+// Ties are broken by the name of the node, so the same data always results in the same node.
+// The rotation is only used by StrategyRoundRobin, it may be nil.
+func (s Strategy) Select(candidates []Candidate, rotation *Rotation) (pveAPI.NodeName, error) {
+	usable := make([]Candidate, 0, len(candidates))
+	for _, e := range candidates {
+		if !e.Online || (s == StrategyStorage && e.StorageAvailable < 0) {
+			continue
+		}
+		usable = append(usable, e)
+	}
+	if len(usable) == 0 {
+		if s == StrategyStorage {
+			return "", errors.New("none of the nodes in " + RootNodes + " is online and has the storage of the guest")
+		}
+		return "", errors.New("none of the nodes in " + RootNodes + " is online")
+	}
+	sort.Slice(usable, func(i, j int) bool { return usable[i].Name < usable[j].Name })
+	switch s {
+	case StrategyRoundRobin:
+		return rotation.next(usable), nil
+	case StrategyMemory:
+		sort.SliceStable(usable, func(i, j int) bool { return usable[i].MemoryPressure < usable[j].MemoryPressure })
+	case StrategyGuests:
+		sort.SliceStable(usable, func(i, j int) bool { return usable[i].Guests < usable[j].Guests })
+	case StrategyStorage:
+		sort.SliceStable(usable, func(i, j int) bool { return usable[i].StorageAvailable > usable[j].StorageAvailable })
+	}
+	return usable[0].Name, nil
+}
@@ -0,0 +1,40 @@
+package node
+
+import (
+	"testing"
+
+	pveAPI "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Strategy_Select_RoundRobin(t *testing.T) {
+	candidates := []Candidate{
+		{Name: "pve3", Online: true, Guests: 5, Newest: 104},
+		{Name: "pve1", Online: true, Guests: 1, Newest: 107},
+		{Name: "pve2", Online: false},
+		{Name: "pve4", Online: true},
+	}
+	tests := []struct {
+		name     string
+		rotation *Rotation
+		output   []pveAPI.NodeName
+	}{
+		// Without a rotation every pick continues after the node with the newest guest.
+		{name: `nil rotation`, output: []pveAPI.NodeName{"pve3", "pve3", "pve3"}},
+		// Guests placed before the earlier ones exist still go to the next node, offline nodes are skipped.
+		{name: `rotation`, rotation: &Rotation{}, output: []pveAPI.NodeName{"pve3", "pve4", "pve1", "pve3"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, expected := range test.output {
+				name, err := StrategyRoundRobin.Select(candidates, test.rotation)
+				require.NoError(t, err)
+				require.Equal(t, expected, name)
+			}
+		})
+	}
+	// Without guests the first node is picked.
+	name, err := StrategyRoundRobin.Select([]Candidate{{Name: "pve2", Online: true}, {Name: "pve1", Online: true}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, pveAPI.NodeName("pve1"), name)
+}
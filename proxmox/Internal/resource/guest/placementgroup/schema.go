@@ -0,0 +1,43 @@
+// Package placementgroup provides the placement group that keeps guests on the same node or spreads them over different nodes.
+package placementgroup
+
+import (
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	Root      = "placement_group"
+	nameKey   = "name"
+	policyKey = "policy"
+
+	defaultPolicy = PolicyAntiAffinity
+)
+
+var regexName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+func Schema(guestType string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "The placement group of the " + guestType + " guest, the group is stored as a tag on the guest.",
+		Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+			nameKey: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringMatch(regexName, "must start with a lowercase letter or digit and only contain lowercase letters, digits, '-' and '_'"),
+				Description:  "The name of the group, guests with the same name are in the same group.",
+			},
+			policyKey: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      string(defaultPolicy),
+				ValidateFunc: validation.StringInSlice([]string{string(PolicyAffinity), string(PolicyAntiAffinity)}, false),
+				Description:  "'affinity' keeps the guests of the group on the same node, 'anti-affinity' places each guest of the group on a different node.",
+			},
+		}},
+	}
+}
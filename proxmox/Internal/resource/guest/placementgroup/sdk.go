@@ -0,0 +1,154 @@
+package placementgroup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	pveAPI "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+type Policy string
+
+const (
+	PolicyAffinity     Policy = "affinity"
+	PolicyAntiAffinity Policy = "anti-affinity"
+)
+
+// tagPrefix is the prefix of the tag that holds the group, the tag has the format `pg.<policy>.<name>`.
+const tagPrefix = "pg."
+
+// Config is the placement group of a guest.
+type Config struct {
+	Name   string
+	Policy Policy
+}
+
+// SDK returns nil when the guest is not in a placement group.
+func SDK(d *schema.ResourceData) *Config {
+	return sdk(d.Get(Root))
+}
+
+// SDKDiff is the same as SDK, for use during planning.
+func SDKDiff(d *schema.ResourceDiff) *Config {
+	return sdk(d.Get(Root))
+}
+
+func sdk(v any) *Config {
+	tmpList, ok := v.([]any)
+	if !ok || len(tmpList) == 0 || tmpList[0] == nil {
+		return nil
+	}
+	settings := tmpList[0].(map[string]any)
+	name, _ := settings[nameKey].(string)
+	if name == "" {
+		return nil
+	}
+	policy, _ := settings[policyKey].(string)
+	if policy == "" {
+		policy = string(defaultPolicy)
+	}
+	return &Config{Name: name, Policy: Policy(policy)}
+}
+
+func (c Config) Tag() pveAPI.Tag {
+	return pveAPI.Tag(tagPrefix + string(c.Policy) + "." + c.Name)
+}
+
+// AddTag returns the tags of the guest with the tag of the placement group.
+func AddTag(tags *pveAPI.Tags, c *Config) *pveAPI.Tags {
+	if c == nil {
+		return tags
+	}
+	newTags := pveAPI.Tags{c.Tag()}
+	if tags != nil {
+		newTags = append(newTags, *tags...)
+	}
+	return &newTags
+}
+
+// parseTag returns nil when the tag is not the tag of a placement group.
+func parseTag(tag pveAPI.Tag) *Config {
+	rest, ok := strings.CutPrefix(string(tag), tagPrefix)
+	if !ok {
+		return nil
+	}
+	policy, name, ok := strings.Cut(rest, ".")
+	if !ok || (Policy(policy) != PolicyAffinity && Policy(policy) != PolicyAntiAffinity) || !regexName.MatchString(name) {
+		return nil
+	}
+	return &Config{Name: name, Policy: Policy(policy)}
+}
+
+// Members returns the number of guests of the group per node, the guest with the ID self is left out.
+// guests is the list of guests from the cluster resources.
+func (c Config) Members(guests []any, self pveAPI.GuestID) (map[pveAPI.NodeName]int, error) {
+	members := map[pveAPI.NodeName]int{}
+	for _, e := range guests {
+		guest, ok := e.(map[string]any)
+		if !ok {
+			continue
+		}
+		if id, _ := guest["vmid"].(float64); pveAPI.GuestID(id) == self {
+			continue
+		}
+		rawTags, _ := guest["tags"].(string)
+		for _, tag := range strings.FieldsFunc(rawTags, func(r rune) bool { return r == ';' || r == ',' || r == ' ' }) {
+			group := parseTag(pveAPI.Tag(tag))
+			if group == nil || group.Name != c.Name {
+				continue
+			}
+			if group.Policy != c.Policy {
+				return nil, fmt.Errorf("%s '%s' has policy '%s', but guest %v is in the group with policy '%s'", Root, c.Name, c.Policy, guest["vmid"], group.Policy)
+			}
+			node, _ := guest["node"].(string)
+			members[pveAPI.NodeName(node)]++
+		}
+	}
+	return members, nil
+}
+
+// Filter returns the nodes that satisfy the policy of the group, given the nodes the other guests of the group are on.
+func (c Config) Filter(members map[pveAPI.NodeName]int, nodes []pveAPI.NodeName) ([]pveAPI.NodeName, error) {
+	allowed := make([]pveAPI.NodeName, 0, len(nodes))
+	switch c.Policy {
+	case PolicyAffinity:
+		if len(members) == 0 {
+			return nodes, nil
+		}
+		// When the group is already spread over several nodes, the nodes with the most guests of the group are preferred.
+		var most int
+		for _, n := range nodes {
+			if members[n] > most {
+				most = members[n]
+				allowed = allowed[:0]
+			}
+			if most > 0 && members[n] == most {
+				allowed = append(allowed, n)
+			}
+		}
+		if len(allowed) == 0 {
+			return nil, fmt.Errorf("%s '%s' with policy '%s' can not be satisfied, the other guests of the group are on node(s) '%s', which are not allowed for this guest", Root, c.Name, c.Policy, joinNodes(members))
+		}
+	default:
+		for _, n := range nodes {
+			if members[n] == 0 {
+				allowed = append(allowed, n)
+			}
+		}
+		if len(allowed) == 0 {
+			return nil, fmt.Errorf("%s '%s' with policy '%s' can not be satisfied, every allowed node already has a guest of the group: '%s'", Root, c.Name, c.Policy, joinNodes(members))
+		}
+	}
+	return allowed, nil
+}
+
+func joinNodes(members map[pveAPI.NodeName]int) string {
+	names := make([]string, 0, len(members))
+	for n := range members {
+		names = append(names, n.String())
+	}
+	sort.Strings(names)
+	return strings.Join(names, "', '")
+}
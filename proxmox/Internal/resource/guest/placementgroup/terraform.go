@@ -0,0 +1,30 @@
+package placementgroup
+
+import (
+	pveAPI "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Terraform sets the placement group from the tags of the guest and returns the remaining tags.
+func Terraform(tags *pveAPI.Tags, d *schema.ResourceData) *pveAPI.Tags {
+	var group *Config
+	var remaining pveAPI.Tags
+	if tags != nil {
+		remaining = make(pveAPI.Tags, 0, len(*tags))
+		for _, tag := range *tags {
+			if v := parseTag(tag); v != nil && group == nil {
+				group = v
+				continue
+			}
+			remaining = append(remaining, tag)
+		}
+	}
+	if group == nil {
+		d.Set(Root, nil)
+	} else {
+		d.Set(Root, []any{map[string]any{
+			nameKey:   group.Name,
+			policyKey: string(group.Policy)}})
+	}
+	return &remaining
+}
@@ -1,6 +1,10 @@
 package disk
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
 	pveAPI "github.com/Telmate/proxmox-api-go/proxmox"
 	errorMSG "github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/errormsg"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/helper/size"
@@ -28,6 +32,7 @@ const (
 	schemaFile              string = "file"
 	schemaFormat            string = "format"
 	schemaID                string = "id"
+	schemaImportFrom        string = "import_from"
 	schemaIDE               string = "ide"
 	schemaIOPSrBurst        string = "iops_r_burst"
 	schemaIOPSrBurstLength  string = "iops_r_burst_length"
@@ -632,6 +637,7 @@ func subSchemaIde(slot string) *schema.Schema {
 							schemaEmulateSSD:    {Type: schema.TypeBool, Optional: true},
 							schemaFormat:        subSchemaDiskFormat(schema.Schema{Default: "raw"}),
 							schemaID:            subSchemaDiskId(),
+							schemaImportFrom:    subSchemaDiskImportFrom(),
 							schemaLinkedDiskId:  subSchemaLinkedDiskId(),
 							schemaReplicate:     {Type: schema.TypeBool, Optional: true},
 							schemaSerial:        subSchemaDiskSerial(),
@@ -658,6 +664,35 @@ func subSchemaIde(slot string) *schema.Schema {
 							schemaWorldWideName: subSchemaDiskWWN()})}}}}}
 }
 
+// CustomizeDiff rejects changes to the image a disk is imported from once the disk exists.
+// PVE only imports the image when the disk is created, so the change would otherwise be accepted without effect.
+func CustomizeDiff() schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+		if d.Id() == "" {
+			return nil
+		}
+		for _, key := range d.GetChangedKeysPrefix(RootDisks) {
+			if !strings.HasSuffix(key, "."+schemaImportFrom) || !d.HasChange(key) {
+				continue
+			}
+			if _, importFrom := d.GetChange(key); importFrom.(string) == "" {
+				continue
+			}
+			// The storage is required, so a disk that existed before has one in the state.
+			if storage, _ := d.GetChange(strings.TrimSuffix(key, schemaImportFrom) + schemaStorage); storage.(string) != "" {
+				return fmt.Errorf("%s: %s can't be changed once the disk exists, remove the disk first to import a different image", key, schemaImportFrom)
+			}
+		}
+		return nil
+	}
+}
+
+func subSchemaDiskImportFrom() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true}
+}
+
 func subSchemaIgnore(path string, ci bool) *schema.Schema {
 	var conflicts []string
 	if ci {
@@ -718,6 +753,7 @@ func subSchemaSata(slot string) *schema.Schema {
 							schemaEmulateSSD:    {Type: schema.TypeBool, Optional: true},
 							schemaFormat:        subSchemaDiskFormat(schema.Schema{Default: "raw"}),
 							schemaID:            subSchemaDiskId(),
+							schemaImportFrom:    subSchemaDiskImportFrom(),
 							schemaLinkedDiskId:  subSchemaLinkedDiskId(),
 							schemaReplicate:     {Type: schema.TypeBool, Optional: true},
 							schemaSerial:        subSchemaDiskSerial(),
@@ -768,6 +804,7 @@ func subSchemaScsi(slot string) *schema.Schema {
 							schemaEmulateSSD:    {Type: schema.TypeBool, Optional: true},
 							schemaFormat:        subSchemaDiskFormat(schema.Schema{Default: "raw"}),
 							schemaID:            subSchemaDiskId(),
+							schemaImportFrom:    subSchemaDiskImportFrom(),
 							schemaIOthread:      {Type: schema.TypeBool, Optional: true},
 							schemaLinkedDiskId:  subSchemaLinkedDiskId(),
 							schemaReadOnly:      {Type: schema.TypeBool, Optional: true},
@@ -820,6 +857,7 @@ func subSchemaVirtio(setting string) *schema.Schema {
 							schemaDiscard:       {Type: schema.TypeBool, Optional: true},
 							schemaFormat:        subSchemaDiskFormat(schema.Schema{Default: "raw"}),
 							schemaID:            subSchemaDiskId(),
+							schemaImportFrom:    subSchemaDiskImportFrom(),
 							schemaIOthread:      {Type: schema.TypeBool, Optional: true},
 							schemaLinkedDiskId:  subSchemaLinkedDiskId(),
 							schemaReadOnly:      {Type: schema.TypeBool, Optional: true},
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	pveAPI "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/helper/size"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -55,6 +56,88 @@ func SDK(d *schema.ResourceData) (*pveAPI.QemuStorages, diag.Diagnostics) {
 		VirtIO: sdk_Disks_QemuVirtIODisksDefault()}, nil
 }
 
+// ImportedSizes returns the configured size of every disk that is imported from an image in this apply, keyed by slot.
+// PVE creates these disks with the size of the image, so they still have to be resized afterwards. Disks that were
+// imported by an earlier apply are left alone, their size is managed like the size of any other disk.
+func ImportedSizes(d *schema.ResourceData) map[string]string {
+	oldDisks, newDisks := d.GetChange(RootDisks)
+	existing := importedDisks(oldDisks)
+	sizes := map[string]string{}
+	for slot, disk := range importedDisks(newDisks) {
+		if _, ok := existing[slot]; !ok {
+			sizes[slot] = disk[schemaSize].(string)
+		}
+	}
+	return sizes
+}
+
+// importedDisks returns the disks of the disks argument that have an import_from, keyed by slot.
+func importedDisks(disks any) map[string]map[string]any {
+	imported := map[string]map[string]any{}
+	v, ok := disks.([]any)
+	if !ok || len(v) != 1 || v[0] == nil {
+		return imported
+	}
+	for _, bus := range v[0].(map[string]any) {
+		slots, ok := bus.([]any)
+		if !ok || len(slots) != 1 || slots[0] == nil {
+			continue
+		}
+		for slot, storage := range slots[0].(map[string]any) {
+			storageSchema, ok := storage.([]any)
+			if !ok || terraformImportFrom(storageSchema) == "" {
+				continue
+			}
+			imported[slot] = storageSchema[0].(map[string]any)[schemaDisk].([]any)[0].(map[string]any)
+		}
+	}
+	return imported
+}
+
+// Storage returns the storage of the largest disk, or an empty string when the guest has no disks.
+// Disks of the same size are ordered by the name of their storage, so the result does not depend on map order.
+func Storage(d *schema.ResourceData) string {
+	var storage string
+	largest := -1
+	check := func(disk map[string]any) {
+		s, _ := disk[schemaStorage].(string)
+		v, _ := disk[schemaSize].(string)
+		if s == "" {
+			return
+		}
+		if diskSize := size.Parse_Unsafe(v); diskSize > largest || (diskSize == largest && s < storage) {
+			storage, largest = s, diskSize
+		}
+	}
+	if v, ok := d.Get(RootDisk).([]any); ok {
+		for _, disk := range v {
+			if disk, ok := disk.(map[string]any); ok && disk[schemaType] == enumDisk {
+				check(disk)
+			}
+		}
+	}
+	v, ok := d.Get(RootDisks).([]any)
+	if !ok || len(v) != 1 || v[0] == nil {
+		return storage
+	}
+	for _, bus := range v[0].(map[string]any) {
+		slots, ok := bus.([]any)
+		if !ok || len(slots) != 1 || slots[0] == nil {
+			continue
+		}
+		for _, slot := range slots[0].(map[string]any) {
+			storageSchema, ok := slot.([]any)
+			if !ok || len(storageSchema) != 1 || storageSchema[0] == nil {
+				continue
+			}
+			if disk, ok := storageSchema[0].(map[string]any)[schemaDisk].([]any); ok && len(disk) == 1 && disk[0] != nil {
+				check(disk[0].(map[string]any))
+			}
+		}
+	}
+	return storage
+}
+
 func sdkIsoFile(iso string) *pveAPI.IsoFile {
 	if iso == "" {
 		return nil
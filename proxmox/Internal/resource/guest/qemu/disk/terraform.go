@@ -21,6 +21,19 @@ func terraformLinkedCloneId(id *pveAPI.GuestID) int {
 	return -1
 }
 
+// PVE does not remember which image a disk was imported from, so the value is taken from the prior schema.
+func terraformImportFrom(schema []any) string {
+	if len(schema) == 0 || schema[0] == nil {
+		return ""
+	}
+	disk, ok := schema[0].(map[string]any)[schemaDisk].([]any)
+	if !ok || len(disk) == 0 || disk[0] == nil {
+		return ""
+	}
+	importFrom, _ := disk[0].(map[string]any)[schemaImportFrom].(string)
+	return importFrom
+}
+
 func terraformIsoFile(config *pveAPI.IsoFile) string {
 	if config == nil {
 		return ""
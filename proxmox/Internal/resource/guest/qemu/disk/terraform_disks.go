@@ -85,6 +85,7 @@ func terraform_Disks_QemuIdeStorage(config *pveAPI.QemuIdeStorage, ciDisk *bool,
 			schemaEmulateSSD:   config.Disk.EmulateSSD,
 			schemaFormat:       string(config.Disk.Format),
 			schemaID:           int(config.Disk.Id),
+			schemaImportFrom:   terraformImportFrom(schema),
 			schemaLinkedDiskId: terraformLinkedCloneId(config.Disk.LinkedDiskId),
 			schemaReplicate:    config.Disk.Replicate,
 			schemaSerial:       string(config.Disk.Serial),
@@ -155,6 +156,7 @@ func terraform_Disks_QemuSataStorage(config *pveAPI.QemuSataStorage, ciDisk *boo
 			schemaEmulateSSD:   config.Disk.EmulateSSD,
 			schemaFormat:       string(config.Disk.Format),
 			schemaID:           int(config.Disk.Id),
+			schemaImportFrom:   terraformImportFrom(schema),
 			schemaLinkedDiskId: terraformLinkedCloneId(config.Disk.LinkedDiskId),
 			schemaReplicate:    config.Disk.Replicate,
 			schemaSerial:       string(config.Disk.Serial),
@@ -250,6 +252,7 @@ func terraform_Disks_QemuScsiStorage(config *pveAPI.QemuScsiStorage, ciDisk *boo
 			schemaEmulateSSD:   config.Disk.EmulateSSD,
 			schemaFormat:       string(config.Disk.Format),
 			schemaID:           int(config.Disk.Id),
+			schemaImportFrom:   terraformImportFrom(schema),
 			schemaIOthread:     config.Disk.IOThread,
 			schemaLinkedDiskId: terraformLinkedCloneId(config.Disk.LinkedDiskId),
 			schemaReadOnly:     config.Disk.ReadOnly,
@@ -332,6 +335,7 @@ func terraform_Disks_QemuVirtIOStorage(config *pveAPI.QemuVirtIOStorage, schema
 			schemaDiscard:      config.Disk.Discard,
 			schemaFormat:       string(config.Disk.Format),
 			schemaID:           int(config.Disk.Id),
+			schemaImportFrom:   terraformImportFrom(schema),
 			schemaIOthread:     config.Disk.IOThread,
 			schemaLinkedDiskId: terraformLinkedCloneId(config.Disk.LinkedDiskId),
 			schemaReadOnly:     config.Disk.ReadOnly,
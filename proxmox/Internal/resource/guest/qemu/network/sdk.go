@@ -3,10 +3,12 @@ package network
 import (
 	"fmt"
 	"net"
+	"sort"
 	"strconv"
 	"strings"
 
 	pveAPI "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/_sub/vnet"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/util"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -60,3 +62,20 @@ func SDK(d *schema.ResourceData) (pveAPI.QemuNetworkInterfaces, diag.Diagnostics
 	}
 	return networks, diags
 }
+
+// CheckBridges checks that the bridges of the network devices that are not vmbr<N> exist, e.g. an SDN VNet.
+// bridges looks up the bridges of the node the guest is placed on, so it is called once the node is known.
+func CheckBridges(networks pveAPI.QemuNetworkInterfaces, bridges vnet.Lookup) diag.Diagnostics {
+	ids := make([]int, 0, len(networks))
+	for id, e := range networks {
+		if !e.Delete && e.Bridge != nil {
+			ids = append(ids, int(id))
+		}
+	}
+	sort.Ints(ids)
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = *networks[pveAPI.QemuNetworkInterfaceID(id)].Bridge
+	}
+	return vnet.Check(bridges, Root+"."+schemaBridge, names)
+}
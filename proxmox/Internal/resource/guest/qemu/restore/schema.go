@@ -0,0 +1,58 @@
+// Package restore provides the backup archive a QEMU guest is created from.
+package restore
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	Root           = "restore"
+	archiveKey     = "archive"
+	storageKey     = "storage"
+	uniqueKey      = "unique"
+	bwlimitKey     = "bwlimit"
+	defaultUnique  = false
+	defaultBwlimit = 0
+)
+
+func Schema() *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		ForceNew:      true,
+		MaxItems:      1,
+		ConflictsWith: []string{"clone", "clone_id", "pxe"},
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				archiveKey: {
+					Type:         schema.TypeString,
+					Required:     true,
+					ForceNew:     true,
+					ValidateFunc: validation.StringIsNotWhiteSpace,
+					Description:  "The volume ID of the vzdump or PBS backup, e.g. 'local:backup/vzdump-qemu-100-2024_01_01-00_00_00.vma.zst'.",
+				},
+				storageKey: {
+					Type:        schema.TypeString,
+					Optional:    true,
+					ForceNew:    true,
+					Description: "The storage the disks are restored to, instead of the storages in the backup.",
+				},
+				uniqueKey: {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					ForceNew:    true,
+					Default:     defaultUnique,
+					Description: "Assign new random MAC addresses to the network interfaces.",
+				},
+				bwlimitKey: {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ForceNew:     true,
+					Default:      defaultBwlimit,
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "Limit the I/O bandwidth of the restore in KiB/s, 0 uses the default of the storage.",
+				},
+			},
+		}}
+}
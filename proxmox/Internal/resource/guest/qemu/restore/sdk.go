@@ -0,0 +1,46 @@
+package restore
+
+import (
+	pveAPI "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Config holds the settings to restore a QEMU guest from a backup.
+type Config struct {
+	Archive        string
+	Storage        string
+	Unique         bool
+	BandwidthLimit int
+}
+
+// Params returns the parameters of the create request that restores the backup as guest id.
+func (c Config) Params(id pveAPI.GuestID) map[string]any {
+	params := map[string]any{
+		"vmid":    int(id),
+		"archive": c.Archive,
+	}
+	if c.Storage != "" {
+		params[storageKey] = c.Storage
+	}
+	if c.Unique {
+		params[uniqueKey] = true
+	}
+	if c.BandwidthLimit > 0 {
+		params[bwlimitKey] = c.BandwidthLimit
+	}
+	return params
+}
+
+// SDK returns nil when the guest is not restored from a backup.
+func SDK(d *schema.ResourceData) *Config {
+	tmpList := d.Get(Root).([]any)
+	if len(tmpList) == 0 || tmpList[0] == nil {
+		return nil
+	}
+	settings := tmpList[0].(map[string]any)
+	return &Config{
+		Archive:        settings[archiveKey].(string),
+		Storage:        settings[storageKey].(string),
+		Unique:         settings[uniqueKey].(bool),
+		BandwidthLimit: settings[bwlimitKey].(int)}
+}
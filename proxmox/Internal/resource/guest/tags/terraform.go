@@ -8,3 +8,8 @@ import (
 func Terraform(tags *pveSDK.Tags, d *schema.ResourceData) {
 	d.Set(Root, toString(tags))
 }
+
+// String returns the tags sorted and without duplicates, in the format of the tags argument.
+func String(tags pveSDK.Tags) string {
+	return toString(sortArray(removeDuplicates(&tags)))
+}
@@ -0,0 +1,45 @@
+package id
+
+import (
+	"errors"
+	"strings"
+)
+
+const firewallType = "firewall"
+
+type GuestFirewall struct {
+	Guest Guest
+	// Kind is the part of the firewall config, e.g. "rules" or "ipset".
+	Kind string
+	// Name is the name of the alias or IP set, it is empty for the options and the rules.
+	Name string
+}
+
+func (f *GuestFirewall) Parse(resourceID string) error {
+	idParts := strings.Split(resourceID, "/")
+	if (len(idParts) != 5 && len(idParts) != 6) || idParts[3] != firewallType {
+		return errors.New("failed to get resource format: '" + resourceID + "'. Must be <node>/<type>/<vmid>/" + firewallType + "/<kind>[/<name>]")
+	}
+	if err := f.Guest.Parse(strings.Join(idParts[:3], "/")); err != nil {
+		return err
+	}
+	if idParts[4] == "" {
+		return errors.New("failed to get firewall kind: '" + resourceID + "'")
+	}
+	f.Kind = idParts[4]
+	f.Name = ""
+	if len(idParts) == 6 {
+		if idParts[5] == "" {
+			return errors.New("failed to get firewall name: '" + resourceID + "'")
+		}
+		f.Name = idParts[5]
+	}
+	return nil
+}
+
+func (f GuestFirewall) String() string {
+	if f.Name == "" {
+		return f.Guest.String() + "/" + firewallType + "/" + f.Kind
+	}
+	return f.Guest.String() + "/" + firewallType + "/" + f.Kind + "/" + f.Name
+}
@@ -0,0 +1,35 @@
+package id
+
+import (
+	"errors"
+	"strings"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+)
+
+const networkType = "network"
+
+type NetworkInterface struct {
+	Node pveSDK.NodeName
+	Name string
+}
+
+func (n *NetworkInterface) Parse(resourceID string) error {
+	idParts := strings.Split(resourceID, "/")
+	if len(idParts) != 3 || idParts[1] != networkType {
+		return errors.New("failed to get resource format: '" + resourceID + "'. Must be <node>/" + networkType + "/<iface>")
+	}
+	if idParts[0] == "" {
+		return errors.New("failed to get node name: '" + idParts[0] + "'")
+	}
+	if idParts[2] == "" {
+		return errors.New("failed to get interface name: '" + resourceID + "'")
+	}
+	n.Node = pveSDK.NodeName(idParts[0])
+	n.Name = idParts[2]
+	return nil
+}
+
+func (n NetworkInterface) String() string {
+	return n.Node.String() + "/" + networkType + "/" + n.Name
+}
@@ -0,0 +1,31 @@
+package id
+
+import (
+	"errors"
+	"strings"
+)
+
+const sdnSubnetType = "sdn_subnet"
+
+type SDNSubnet struct {
+	VNet string
+	// Subnet is the ID PVE gives the subnet, `<zone>-<ip>-<mask>`.
+	Subnet string
+}
+
+func (s *SDNSubnet) Parse(resourceID string) error {
+	idParts := strings.Split(resourceID, "/")
+	if len(idParts) != 3 || idParts[0] != sdnSubnetType {
+		return errors.New("failed to get resource format: '" + resourceID + "'. Must be " + sdnSubnetType + "/<vnet>/<subnet>")
+	}
+	if idParts[1] == "" || idParts[2] == "" {
+		return errors.New("failed to get vnet and subnet: '" + resourceID + "'")
+	}
+	s.VNet = idParts[1]
+	s.Subnet = idParts[2]
+	return nil
+}
+
+func (s SDNSubnet) String() string {
+	return sdnSubnetType + "/" + s.VNet + "/" + s.Subnet
+}
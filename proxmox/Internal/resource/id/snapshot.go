@@ -0,0 +1,32 @@
+package id
+
+import (
+	"errors"
+	"strings"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+)
+
+type Snapshot struct {
+	Guest Guest
+	Name  pveSDK.SnapshotName
+}
+
+func (s *Snapshot) Parse(resourceID string) error {
+	index := strings.LastIndex(resourceID, "/")
+	if index < 0 {
+		return errors.New("failed to get resource format: '" + resourceID + "'. Must be <node>/<type>/<vmid>/<snapshot>")
+	}
+	if err := s.Guest.Parse(resourceID[:index]); err != nil {
+		return err
+	}
+	s.Name = pveSDK.SnapshotName(resourceID[index+1:])
+	if s.Name == "" {
+		return errors.New("failed to get snapshot name: '" + resourceID + "'")
+	}
+	return nil
+}
+
+func (s Snapshot) String() string {
+	return s.Guest.String() + "/" + s.Name.String()
+}
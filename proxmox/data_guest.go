@@ -0,0 +1,132 @@
+package proxmox
+
+import (
+	"context"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/tags"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// dataGuestAttributes returns the schema of the attributes every guest data source returns.
+func dataGuestAttributes() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"vmid": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"node": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"type": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"tags": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"pool": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"template": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+	}
+}
+
+// dataGuest returns the attributes of a guest from the guest list of the cluster.
+func dataGuest(raw pveSDK.RawGuestResource) map[string]interface{} {
+	return map[string]interface{}{
+		"id":       dataGuestID(raw).String(),
+		"vmid":     int(raw.GetID()),
+		"node":     raw.GetNode().String(),
+		"type":     raw.GetType().String(),
+		"name":     raw.GetName().String(),
+		"tags":     tags.String(raw.GetTags()),
+		"status":   raw.GetStatus().String(),
+		"pool":     string(raw.GetPool()),
+		"template": raw.GetTemplate(),
+	}
+}
+
+// dataGuestID returns the ID of the guest in the format of the guest resources, <node>/<type>/<vmid>.
+func dataGuestID(raw pveSDK.RawGuestResource) id.Guest {
+	return id.Guest{ID: raw.GetID(), Node: raw.GetNode(), Type: raw.GetType().String()}
+}
+
+// dataSingleGuest returns a data source that looks up one guest of the type by its name or VMID.
+func dataSingleGuest(guestType pveSDK.GuestType) *schema.Resource {
+	s := dataGuestAttributes()
+	s["vmid"] = &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		Computed:     true,
+		ExactlyOneOf: []string{"vmid", "name"},
+		ValidateFunc: validation.IntBetween(100, 999999999),
+		Description:  "The VMID of the guest.",
+	}
+	s["name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Computed:    true,
+		Description: "The name of the guest.",
+	}
+	s["node"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Computed:    true,
+		Description: "The node to prefer when several guests have the name.",
+	}
+	return &schema.Resource{
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return dataReadSingleGuest(ctx, d, meta, guestType)
+		},
+		Schema: s,
+	}
+}
+
+func DataVmQemu() *schema.Resource { return dataSingleGuest(pveSDK.GuestQemu) }
+
+func DataLxcGuest() *schema.Resource { return dataSingleGuest(pveSDK.GuestLxc) }
+
+func dataReadSingleGuest(ctx context.Context, d *schema.ResourceData, meta interface{}, guestType pveSDK.GuestType) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	rawGuests, err := pconf.NewClient.Guest.List(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var raw pveSDK.RawGuestResource
+	if name := d.Get("name").(string); name != "" {
+		raw, err = guestGetSourceByName(rawGuests, pveSDK.GuestName(name), pveSDK.NodeName(d.Get("node").(string)), guestType)
+	} else {
+		raw, err = guestGetSourceByID(rawGuests, pveSDK.GuestID(d.Get("vmid").(int)), guestType)
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	guest := dataGuest(raw)
+	d.SetId(guest["id"].(string))
+	delete(guest, "id")
+	for key, value := range guest {
+		d.Set(key, value)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package proxmox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func testFakeGuests(t *testing.T) *providerConfiguration {
+	fake, _ := testAccFakeProvider(t, "pve1", "pve2")
+	fake.AddGuest("pve1", "qemu", 100, map[string]string{"name": "web", "tags": "prod;web"})
+	fake.AddGuest("pve2", "qemu", 101, map[string]string{"name": "web", "tags": "staging;web"})
+	fake.AddGuest("pve2", "qemu", 102, map[string]string{"name": "db", "tags": "prod"})
+	fake.AddGuest("pve1", "lxc", 200, map[string]string{"hostname": "proxy", "tags": "prod"})
+	fake.AddGuest("pve1", "qemu", 9000, map[string]string{"name": "debian-template", "template": "1"})
+	fake.SetGuestStatus(100, "running")
+	fake.SetGuestStatus(200, "running")
+	meta := testFakeMeta(t, fake)
+	require.NoError(t, meta.Client.Post(context.Background(), map[string]any{"poolid": "production"}, "/pools"))
+	require.NoError(t, meta.Client.Put(context.Background(), map[string]any{"vms": "100,102"}, "/pools/production"))
+	return meta
+}
+
+func Test_DataGuest_Fake(t *testing.T) {
+	meta := testFakeGuests(t)
+
+	r := DataVmQemu()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]any{"vmid": 102})
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "pve2/qemu/102", d.Id())
+	require.Equal(t, "db", d.Get("name"))
+	require.Equal(t, "pve2", d.Get("node"))
+	require.Equal(t, "production", d.Get("pool"))
+	require.Equal(t, "stopped", d.Get("status"))
+
+	// Guests with the same name are looked up on the preferred node first.
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]any{"name": "web", "node": "pve2"})
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, 101, d.Get("vmid"))
+	require.Equal(t, "staging;web", d.Get("tags"))
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]any{"name": "web"})
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "pve1/qemu/100", d.Id())
+	require.Equal(t, "running", d.Get("status"))
+
+	lxc := DataLxcGuest()
+	d = schema.TestResourceDataRaw(t, lxc.Schema, map[string]any{"name": "proxy"})
+	testFakeRead(t, lxc, meta, d)
+	require.Equal(t, "pve1/lxc/200", d.Id())
+	require.Equal(t, "lxc", d.Get("type"))
+
+	// Guests of the other type or that do not exist are not found.
+	for _, test := range []struct {
+		r      *schema.Resource
+		config map[string]any
+	}{
+		{r: lxc, config: map[string]any{"vmid": 100}},
+		{r: lxc, config: map[string]any{"name": "web"}},
+		{r: r, config: map[string]any{"vmid": 300}},
+	} {
+		d := schema.TestResourceDataRaw(t, test.r.Schema, test.config)
+		require.True(t, test.r.ReadContext(context.Background(), d, meta).HasError(), test.config)
+	}
+}
+
+func Test_DataGuests_Fake(t *testing.T) {
+	meta := testFakeGuests(t)
+	r := DataGuests()
+	vmids := func(config map[string]any) []int {
+		d := schema.TestResourceDataRaw(t, r.Schema, config)
+		testFakeRead(t, r, meta, d)
+		ids := []int{}
+		for _, guest := range d.Get("guests").([]any) {
+			ids = append(ids, guest.(map[string]any)["vmid"].(int))
+		}
+		return ids
+	}
+
+	require.Equal(t, []int{100, 101, 102, 200, 9000}, vmids(map[string]any{}))
+	require.Equal(t, []int{100, 102, 200}, vmids(map[string]any{"tags": []any{"prod"}}))
+	require.Equal(t, []int{100}, vmids(map[string]any{"tags": []any{"prod", "web"}}))
+	require.Equal(t, []int{100, 102}, vmids(map[string]any{"pool": "production"}))
+	require.Equal(t, []int{101, 102}, vmids(map[string]any{"node": "pve2"}))
+	require.Equal(t, []int{200}, vmids(map[string]any{"type": "lxc"}))
+	require.Equal(t, []int{100, 200}, vmids(map[string]any{"status": "running"}))
+	require.Equal(t, []int{100, 101, 9000}, vmids(map[string]any{"name_regex": "^(web|debian-)"}))
+	require.Equal(t, []int{}, vmids(map[string]any{"type": "lxc", "node": "pve2"}))
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]any{"tags": []any{"prod"}, "type": "lxc"})
+	testFakeRead(t, r, meta, d)
+	guest := d.Get("guests").([]any)[0].(map[string]any)
+	require.Equal(t, "pve1/lxc/200", guest["id"])
+	require.Equal(t, "proxy", guest["name"])
+	require.Equal(t, false, guest["template"])
+}
+
+func Test_DataGuest_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource *schema.Resource
+		config   map[string]any
+	}{
+		{name: "name and vmid", resource: DataVmQemu(), config: map[string]any{"name": "web", "vmid": 100}},
+		{name: "neither name nor vmid", resource: DataLxcGuest(), config: map[string]any{}},
+		{name: "guests type", resource: DataGuests(), config: map[string]any{"type": "vm"}},
+		{name: "guests regex", resource: DataGuests(), config: map[string]any{"name_regex": "web("}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.True(t, test.resource.Validate(terraform.NewResourceConfigRaw(test.config)).HasError())
+		})
+	}
+}
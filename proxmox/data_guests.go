@@ -0,0 +1,127 @@
+package proxmox
+
+import (
+	"context"
+	"regexp"
+	"sort"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func DataGuests() *schema.Resource {
+	attributes := dataGuestAttributes()
+	attributes["id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The ID of the guest in the format <node>/<type>/<vmid>.",
+	}
+	return &schema.Resource{
+		ReadContext: dataReadGuests,
+		Schema: map[string]*schema.Schema{
+			"tags": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Only return the guests that have all of the tags.",
+			},
+			"pool": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the guests in the pool.",
+			},
+			"node": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the guests on the node.",
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"qemu", "lxc"}, false),
+				Description:  "Only return the guests of the type.",
+			},
+			"status": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"running", "stopped"}, false),
+				Description:  "Only return the guests with the status.",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Only return the guests whose name matches the regular expression.",
+			},
+			"guests": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: attributes,
+				},
+				Description: "The guests that match all filters, ordered by VMID.",
+			},
+		},
+	}
+}
+
+// dataGuestsMatch reports whether the guest matches all filters of the data source.
+func dataGuestsMatch(d *schema.ResourceData, raw pveSDK.RawGuestResource, nameRegex *regexp.Regexp) bool {
+	for key, value := range map[string]string{
+		"pool":   string(raw.GetPool()),
+		"node":   raw.GetNode().String(),
+		"type":   raw.GetType().String(),
+		"status": raw.GetStatus().String(),
+	} {
+		if filter := d.Get(key).(string); filter != "" && filter != value {
+			return false
+		}
+	}
+	if nameRegex != nil && !nameRegex.MatchString(raw.GetName().String()) {
+		return false
+	}
+	guestTags := map[pveSDK.Tag]struct{}{}
+	for _, tag := range raw.GetTags() {
+		guestTags[tag] = struct{}{}
+	}
+	for _, tag := range d.Get("tags").([]interface{}) {
+		if _, ok := guestTags[pveSDK.Tag(tag.(string))]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func dataReadGuests(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	var nameRegex *regexp.Regexp
+	if expr := d.Get("name_regex").(string); expr != "" {
+		var err error
+		if nameRegex, err = regexp.Compile(expr); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	rawGuests, err := pconf.NewClient.Guest.List(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	matches := make([]pveSDK.RawGuestResource, 0)
+	for raw := range rawGuests.Iter() {
+		if dataGuestsMatch(d, raw, nameRegex) {
+			matches = append(matches, raw)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].GetID() < matches[j].GetID() })
+	guests := make([]interface{}, len(matches))
+	for i, raw := range matches {
+		guests[i] = dataGuest(raw)
+	}
+	d.SetId("guests")
+	d.Set("guests", guests)
+	return nil
+}
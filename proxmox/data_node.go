@@ -0,0 +1,249 @@
+package proxmox
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/node"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dataNodeAttributes returns the schema of the attributes every node data source returns.
+func dataNodeAttributes() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"online": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"cpu_count": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"cpu_usage": {
+			Type:        schema.TypeFloat,
+			Computed:    true,
+			Description: "The fraction of the CPUs in use.",
+		},
+		"memory_total": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The memory of the node in bytes.",
+		},
+		"memory_used": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The memory in use in bytes.",
+		},
+		"uptime": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The uptime of the node in seconds.",
+		},
+		"pve_version": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"kernel_version": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"storages": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name":      {Type: schema.TypeString, Computed: true},
+					"type":      {Type: schema.TypeString, Computed: true},
+					"content":   {Type: schema.TypeList, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+					"shared":    {Type: schema.TypeBool, Computed: true},
+					"active":    {Type: schema.TypeBool, Computed: true},
+					"total":     {Type: schema.TypeInt, Computed: true},
+					"used":      {Type: schema.TypeInt, Computed: true},
+					"available": {Type: schema.TypeInt, Computed: true},
+				},
+			},
+			Description: "The storages that are available on the node.",
+		},
+		"bridges": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "The active Linux and OVS bridges of the node, which guests can connect their network devices to.",
+		},
+		"network_interfaces": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name":    {Type: schema.TypeString, Computed: true},
+					"type":    {Type: schema.TypeString, Computed: true},
+					"active":  {Type: schema.TypeBool, Computed: true},
+					"cidr":    {Type: schema.TypeString, Computed: true},
+					"gateway": {Type: schema.TypeString, Computed: true},
+					"comment": {Type: schema.TypeString, Computed: true},
+				},
+			},
+			Description: "The network interfaces of the node, interfaces that are not applied yet are not active.",
+		},
+	}
+}
+
+// dataNodeList returns the nodes of the cluster from the node list, by name.
+func dataNodeList(ctx context.Context, client *pveSDK.Client) (map[string]map[string]interface{}, error) {
+	list, err := client.GetNodeList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nodes := map[string]map[string]interface{}{}
+	data, _ := list["data"].([]interface{})
+	for _, e := range data {
+		if info, ok := e.(map[string]interface{}); ok {
+			nodes[itemValue(info, "node")] = info
+		}
+	}
+	return nodes, nil
+}
+
+// dataNode returns the attributes of a node, the version, storages and network interfaces are only returned for online nodes.
+func dataNode(ctx context.Context, client *pveSDK.Client, info map[string]interface{}) (map[string]interface{}, error) {
+	name := itemValue(info, "node")
+	cpus, _ := info["maxcpu"].(float64)
+	cpu, _ := info["cpu"].(float64)
+	memTotal, _ := info["maxmem"].(float64)
+	memUsed, _ := info["mem"].(float64)
+	uptime, _ := info["uptime"].(float64)
+	n := map[string]interface{}{
+		"name":               name,
+		"online":             itemValue(info, "status") == "online",
+		"cpu_count":          int(cpus),
+		"cpu_usage":          cpu,
+		"memory_total":       int64(memTotal),
+		"memory_used":        int64(memUsed),
+		"uptime":             int64(uptime),
+		"pve_version":        "",
+		"kernel_version":     "",
+		"storages":           []interface{}{},
+		"bridges":            []string{},
+		"network_interfaces": []interface{}{},
+	}
+	if !n["online"].(bool) {
+		return n, nil
+	}
+
+	status, err := client.GetItemConfigMapStringInterface(ctx, "/nodes/"+name+"/status", "node", "status")
+	if err != nil {
+		return nil, err
+	}
+	n["pve_version"] = itemValue(status, "pveversion")
+	n["kernel_version"] = itemValue(status, "kversion")
+
+	storages, err := client.GetItemListInterfaceArray(ctx, "/nodes/"+name+"/storage")
+	if err != nil {
+		return nil, err
+	}
+	storageList := make([]interface{}, 0, len(storages))
+	for _, e := range storages {
+		item, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		total, _ := item["total"].(float64)
+		used, _ := item["used"].(float64)
+		avail, _ := item["avail"].(float64)
+		content := []interface{}{}
+		for _, c := range strings.Split(itemValue(item, "content"), ",") {
+			if c != "" {
+				content = append(content, c)
+			}
+		}
+		storageList = append(storageList, map[string]interface{}{
+			"name":      itemValue(item, "storage"),
+			"type":      itemValue(item, "type"),
+			"content":   content,
+			"shared":    itemValue(item, "shared") == "1",
+			"active":    itemValue(item, "active") == "1",
+			"total":     int64(total),
+			"used":      int64(used),
+			"available": int64(avail),
+		})
+	}
+	n["storages"] = storageList
+
+	interfaces, err := client.GetItemListInterfaceArray(ctx, "/nodes/"+name+"/network")
+	if err != nil {
+		return nil, err
+	}
+	interfaceList := make([]interface{}, 0, len(interfaces))
+	bridges := []string{}
+	for _, e := range interfaces {
+		item, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ifaceType, active := itemValue(item, "type"), itemValue(item, "active") == "1"
+		if active && (ifaceType == "bridge" || ifaceType == "OVSBridge") {
+			bridges = append(bridges, itemValue(item, "iface"))
+		}
+		interfaceList = append(interfaceList, map[string]interface{}{
+			"name":    itemValue(item, "iface"),
+			"type":    ifaceType,
+			"active":  active,
+			"cidr":    itemValue(item, "cidr"),
+			"gateway": itemValue(item, "gateway"),
+			"comment": itemValue(item, "comments"),
+		})
+	}
+	sort.Slice(interfaceList, func(i, j int) bool {
+		return interfaceList[i].(map[string]interface{})["name"].(string) < interfaceList[j].(map[string]interface{})["name"].(string)
+	})
+	sort.Strings(bridges)
+	n["network_interfaces"] = interfaceList
+	n["bridges"] = bridges
+	return n, nil
+}
+
+func DataNode() *schema.Resource {
+	s := dataNodeAttributes()
+	s["name"] = &schema.Schema{
+		Type:             schema.TypeString,
+		Required:         true,
+		ValidateDiagFunc: node.ValidateName("name"),
+		Description:      "The name of the node.",
+	}
+	return &schema.Resource{
+		ReadContext: dataReadNode,
+		Schema:      s,
+	}
+}
+
+func dataReadNode(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	name := d.Get("name").(string)
+	nodes, err := dataNodeList(ctx, pconf.Client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	info, ok := nodes[name]
+	if !ok {
+		return diag.Errorf("node '%s' does not exist", name)
+	}
+	n, err := dataNode(ctx, pconf.Client, info)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(name)
+	for key, value := range n {
+		d.Set(key, value)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package proxmox
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DataNodePciDevices_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t, "pve")
+	meta := testFakeMeta(t, fake)
+	r := DataNodePciDevices()
+	ids := func(config map[string]any) []string {
+		config["node"] = "pve"
+		d := schema.TestResourceDataRaw(t, r.Schema, config)
+		testFakeRead(t, r, meta, d)
+		list := []string{}
+		for _, device := range d.Get("devices").([]any) {
+			list = append(list, device.(map[string]any)["id"].(string))
+		}
+		return list
+	}
+
+	require.Equal(t, []string{"0000:00:02.0", "0000:01:00.0", "0000:01:00.1", "0000:02:00.0"}, ids(map[string]any{}))
+	require.Equal(t, []string{"0000:00:02.0", "0000:01:00.0"}, ids(map[string]any{"class": "0x03"}))
+	require.Equal(t, []string{"0000:01:00.0", "0000:01:00.1"}, ids(map[string]any{"vendor_id": "10DE"}))
+	require.Equal(t, []string{"0000:01:00.0"}, ids(map[string]any{"class": "0300", "vendor_id": "10de"}))
+	require.Equal(t, []string{"0000:02:00.0"}, ids(map[string]any{"name_regex": "^Intel .*Gigabit"}))
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]any{"node": "pve", "name_regex": "Tesla T4"})
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "10de", d.Get("devices.0.vendor_id"))
+	require.Equal(t, "1eb8", d.Get("devices.0.device_id"))
+	require.Equal(t, "10de", d.Get("devices.0.sub_vendor_id"))
+	require.Equal(t, "12a2", d.Get("devices.0.sub_device_id"))
+	require.Equal(t, 1, d.Get("devices.0.iommu_group"))
+	require.False(t, d.Get("devices.0.mdev").(bool))
+	require.Empty(t, d.Get("devices.0.mdev_types"))
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]any{"node": "pve", "vendor_id": "8086", "class": "03"})
+	testFakeRead(t, r, meta, d)
+	require.True(t, d.Get("devices.0.mdev").(bool))
+	require.Equal(t, "i915-GVTg_V5_4", d.Get("devices.0.mdev_types.0.type"))
+	require.Equal(t, 2, d.Get("devices.0.mdev_types.1.available"))
+}
+
+func Test_DataNodeUsbDevices_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t, "pve")
+	meta := testFakeMeta(t, fake)
+	r := DataNodeUsbDevices()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]any{"node": "pve"})
+	testFakeRead(t, r, meta, d)
+	require.Len(t, d.Get("devices"), 2)
+	require.Equal(t, "046d:c52b", d.Get("devices.0.device_id"))
+	require.Equal(t, "1-1", d.Get("devices.0.port_id"))
+	require.Equal(t, "Logitech", d.Get("devices.0.vendor_name"))
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]any{"node": "pve", "vendor_id": "0951"})
+	testFakeRead(t, r, meta, d)
+	require.Len(t, d.Get("devices"), 1)
+	require.Equal(t, "2-2", d.Get("devices.0.port_id"))
+	require.Equal(t, "DataTraveler 3.0", d.Get("devices.0.product_name"))
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]any{"node": "pve", "class": "09"})
+	testFakeRead(t, r, meta, d)
+	require.Empty(t, d.Get("devices"))
+}
+
+func Test_DataNodeDevices_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource *schema.Resource
+		config   map[string]any
+	}{
+		{name: "pci node", resource: DataNodePciDevices(), config: map[string]any{"node": "pve_1"}},
+		{name: "pci class", resource: DataNodePciDevices(), config: map[string]any{"node": "pve", "class": "display"}},
+		{name: "pci vendor", resource: DataNodePciDevices(), config: map[string]any{"node": "pve", "vendor_id": "0x10de"}},
+		{name: "usb regex", resource: DataNodeUsbDevices(), config: map[string]any{"node": "pve", "name_regex": "Logitech("}},
+		{name: "usb class", resource: DataNodeUsbDevices(), config: map[string]any{"node": "pve", "class": "9"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.True(t, test.resource.Validate(terraform.NewResourceConfigRaw(test.config)).HasError())
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package proxmox
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/node"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var rxNodeDeviceClass = regexp.MustCompile(`^(?:0x)?[0-9a-fA-F]{2,6}$`)
+
+// dataNodeDeviceFilters returns the arguments both device data sources filter the devices by.
+func dataNodeDeviceFilters(classDescription string) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"node": {
+			Type:             schema.TypeString,
+			Required:         true,
+			ValidateDiagFunc: node.ValidateName("node"),
+			Description:      "The node to list the devices of.",
+		},
+		"class": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringMatch(rxNodeDeviceClass, "must be a hexadecimal class code"),
+			Description:  classDescription,
+		},
+		"vendor_id": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[0-9a-fA-F]{4}$`), "must be a 4 digit hexadecimal vendor ID"),
+			Description:  "Only list the devices of the vendor, e.g. 10de.",
+		},
+		"name_regex": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringIsValidRegExp,
+			Description:  "Only list the devices whose vendor and device name, separated by a space, match the regular expression.",
+		},
+	}
+}
+
+// dataNodeDeviceFilter returns whether a device matches the filters of the data source.
+func dataNodeDeviceFilter(d *schema.ResourceData) (func(class, vendorID, name string) bool, error) {
+	class := strings.ToLower(strings.TrimPrefix(d.Get("class").(string), "0x"))
+	vendorID := strings.ToLower(d.Get("vendor_id").(string))
+	var rx *regexp.Regexp
+	if expr := d.Get("name_regex").(string); expr != "" {
+		var err error
+		if rx, err = regexp.Compile(expr); err != nil {
+			return nil, err
+		}
+	}
+	return func(deviceClass, deviceVendorID, name string) bool {
+		return strings.HasPrefix(deviceClass, class) && (vendorID == "" || deviceVendorID == vendorID) &&
+			(rx == nil || rx.MatchString(name))
+	}, nil
+}
+
+func DataNodePciDevices() *schema.Resource {
+	s := dataNodeDeviceFilters("Only list the devices whose class code starts with the hexadecimal code, e.g. 03 for display controllers or 0300 for VGA controllers.")
+	s["devices"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"id":            {Type: schema.TypeString, Computed: true},
+				"class":         {Type: schema.TypeString, Computed: true},
+				"vendor_id":     {Type: schema.TypeString, Computed: true},
+				"device_id":     {Type: schema.TypeString, Computed: true},
+				"sub_vendor_id": {Type: schema.TypeString, Computed: true},
+				"sub_device_id": {Type: schema.TypeString, Computed: true},
+				"vendor_name":   {Type: schema.TypeString, Computed: true},
+				"device_name":   {Type: schema.TypeString, Computed: true},
+				"iommu_group":   {Type: schema.TypeInt, Computed: true},
+				"mdev":          {Type: schema.TypeBool, Computed: true},
+				"mdev_types": {
+					Type:     schema.TypeList,
+					Computed: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"type":        {Type: schema.TypeString, Computed: true},
+							"available":   {Type: schema.TypeInt, Computed: true},
+							"description": {Type: schema.TypeString, Computed: true},
+						},
+					},
+				},
+			},
+		},
+		Description: "The PCI devices that match all filters, ordered by their ID.",
+	}
+	return &schema.Resource{
+		ReadContext: dataReadNodePciDevices,
+		Schema:      s,
+	}
+}
+
+// dataNodePciHex returns a `0x` prefixed ID of the hardware API without the prefix.
+func dataNodePciHex(device map[string]interface{}, key string) string {
+	return strings.ToLower(strings.TrimPrefix(itemValue(device, key), "0x"))
+}
+
+// dataNodeMdevTypes returns the mediated device types a PCI device supports.
+func dataNodeMdevTypes(ctx context.Context, client *pveSDK.Client, nodeName, id string) ([]interface{}, error) {
+	list, err := client.GetItemListInterfaceArray(ctx, "/nodes/"+nodeName+"/hardware/pci/"+id+"/mdev")
+	if err != nil {
+		return nil, err
+	}
+	types := make([]interface{}, 0, len(list))
+	for _, e := range list {
+		item, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		available, _ := item["available"].(float64)
+		types = append(types, map[string]interface{}{
+			"type":        itemValue(item, "type"),
+			"available":   int(available),
+			"description": itemValue(item, "description"),
+		})
+	}
+	return types, nil
+}
+
+func dataReadNodePciDevices(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	nodeName := d.Get("node").(string)
+	match, err := dataNodeDeviceFilter(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	hardware, err := mappingHardware(ctx, pconf.Client, nodeName, "pci")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	sort.Slice(hardware, func(i, j int) bool { return itemValue(hardware[i], "id") < itemValue(hardware[j], "id") })
+
+	devices := make([]interface{}, 0, len(hardware))
+	for _, device := range hardware {
+		id := itemValue(device, "id")
+		vendorName, deviceName := itemValue(device, "vendor_name"), itemValue(device, "device_name")
+		if !match(dataNodePciHex(device, "class"), dataNodePciHex(device, "vendor"), vendorName+" "+deviceName) {
+			continue
+		}
+		mdev := itemValue(device, "mdev") == "1" || itemValue(device, "mdev") == "true"
+		mdevTypes := []interface{}{}
+		if mdev {
+			if mdevTypes, err = dataNodeMdevTypes(ctx, pconf.Client, nodeName, id); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+		group, _ := device["iommugroup"].(float64)
+		devices = append(devices, map[string]interface{}{
+			"id":            id,
+			"class":         dataNodePciHex(device, "class"),
+			"vendor_id":     dataNodePciHex(device, "vendor"),
+			"device_id":     dataNodePciHex(device, "device"),
+			"sub_vendor_id": dataNodePciHex(device, "subsystem_vendor"),
+			"sub_device_id": dataNodePciHex(device, "subsystem_device"),
+			"vendor_name":   vendorName,
+			"device_name":   deviceName,
+			"iommu_group":   int(group),
+			"mdev":          mdev,
+			"mdev_types":    mdevTypes,
+		})
+	}
+	d.SetId(nodeName + "/pci")
+	d.Set("devices", devices)
+	return nil
+}
@@ -0,0 +1,79 @@
+package proxmox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DataNode_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t, "pve1", "pve2")
+	fake.AddStorage("tank", "zfspool", false, "images", "rootdir")
+	meta := testFakeMeta(t, fake)
+
+	r := DataNode()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]any{"name": "pve1"})
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "pve1", d.Id())
+	require.True(t, d.Get("online").(bool))
+	require.Positive(t, d.Get("cpu_count"))
+	require.Positive(t, d.Get("memory_total"))
+	require.Equal(t, "pve-manager/8.4.1/fake", d.Get("pve_version"))
+	require.Equal(t, []any{"vmbr0"}, d.Get("bridges"))
+	require.Equal(t, "eno1", d.Get("network_interfaces.0.name"))
+	storages := map[string]map[string]any{}
+	for _, e := range d.Get("storages").([]any) {
+		storages[e.(map[string]any)["name"].(string)] = e.(map[string]any)
+	}
+	require.Contains(t, storages, "tank")
+	require.Equal(t, "zfspool", storages["tank"]["type"])
+	require.Equal(t, []any{"images", "rootdir"}, storages["tank"]["content"])
+
+	// Offline nodes only report the attributes of the node list.
+	fake.SetNodeOnline("pve2", false)
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]any{"name": "pve2"})
+	testFakeRead(t, r, meta, d)
+	require.False(t, d.Get("online").(bool))
+	require.Empty(t, d.Get("pve_version"))
+	require.Empty(t, d.Get("storages"))
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]any{"name": "pve3"})
+	require.True(t, r.ReadContext(context.Background(), d, meta).HasError())
+}
+
+func Test_DataNodes_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t, "pve1", "pve2", "pve3")
+	meta := testFakeMeta(t, fake)
+	fake.SetNodeOnline("pve3", false)
+
+	r := DataNodes()
+	names := func(config map[string]any) []any {
+		d := schema.TestResourceDataRaw(t, r.Schema, config)
+		testFakeRead(t, r, meta, d)
+		require.Len(t, d.Get("nodes"), len(d.Get("names").([]any)))
+		return d.Get("names").([]any)
+	}
+	require.Equal(t, []any{"pve1", "pve2", "pve3"}, names(map[string]any{}))
+	require.Equal(t, []any{"pve1", "pve2"}, names(map[string]any{"online": true}))
+	require.Equal(t, []any{}, names(map[string]any{"bridge": "vmbr1"}))
+
+	// A bridge is only used once the network config of the node is applied.
+	ctx := context.Background()
+	require.NoError(t, meta.Client.Post(ctx, map[string]any{"iface": "vmbr1", "type": "bridge", "bridge_ports": "eno2"}, "/nodes/pve2/network"))
+	require.Equal(t, []any{}, names(map[string]any{"bridge": "vmbr1"}))
+	_, err := meta.Client.PutWithTask(ctx, map[string]any{}, "/nodes/pve2/network")
+	require.NoError(t, err)
+	require.Equal(t, []any{"pve2"}, names(map[string]any{"bridge": "vmbr1"}))
+	require.Equal(t, []any{"pve1", "pve2"}, names(map[string]any{"bridge": "vmbr0"}))
+}
+
+func Test_DataNode_Validation(t *testing.T) {
+	for _, name := range []string{"", "-pve1", "pve_1", "pve1.example.com"} {
+		t.Run(name, func(t *testing.T) {
+			require.True(t, DataNode().Validate(terraform.NewResourceConfigRaw(map[string]any{"name": name})).HasError())
+		})
+	}
+}
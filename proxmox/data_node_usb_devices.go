@@ -0,0 +1,79 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func DataNodeUsbDevices() *schema.Resource {
+	s := dataNodeDeviceFilters("Only list the devices whose class code starts with the hexadecimal code, e.g. 09 for hubs.")
+	s["devices"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"device_id":    {Type: schema.TypeString, Computed: true},
+				"port_id":      {Type: schema.TypeString, Computed: true},
+				"class":        {Type: schema.TypeString, Computed: true},
+				"vendor_id":    {Type: schema.TypeString, Computed: true},
+				"product_id":   {Type: schema.TypeString, Computed: true},
+				"vendor_name":  {Type: schema.TypeString, Computed: true},
+				"product_name": {Type: schema.TypeString, Computed: true},
+				"serial":       {Type: schema.TypeString, Computed: true},
+				"speed":        {Type: schema.TypeString, Computed: true},
+			},
+		},
+		Description: "The USB devices that match all filters, ordered by their port.",
+	}
+	return &schema.Resource{
+		ReadContext: dataReadNodeUsbDevices,
+		Schema:      s,
+	}
+}
+
+func dataReadNodeUsbDevices(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	nodeName := d.Get("node").(string)
+	match, err := dataNodeDeviceFilter(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	hardware, err := mappingHardware(ctx, pconf.Client, nodeName, "usb")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	sort.Slice(hardware, func(i, j int) bool { return mappingUSBPort(hardware[i]) < mappingUSBPort(hardware[j]) })
+
+	devices := make([]interface{}, 0, len(hardware))
+	for _, device := range hardware {
+		class, _ := strconv.Atoi(itemValue(device, "class"))
+		vendorID, productID := strings.ToLower(itemValue(device, "vendid")), strings.ToLower(itemValue(device, "prodid"))
+		vendorName, productName := itemValue(device, "manufacturer"), itemValue(device, "product")
+		if !match(fmt.Sprintf("%02x", class), vendorID, vendorName+" "+productName) {
+			continue
+		}
+		devices = append(devices, map[string]interface{}{
+			"device_id":    vendorID + ":" + productID,
+			"port_id":      mappingUSBPort(device),
+			"class":        fmt.Sprintf("%02x", class),
+			"vendor_id":    vendorID,
+			"product_id":   productID,
+			"vendor_name":  vendorName,
+			"product_name": productName,
+			"serial":       itemValue(device, "serial"),
+			"speed":        itemValue(device, "speed"),
+		})
+	}
+	d.SetId(nodeName + "/usb")
+	d.Set("devices", devices)
+	return nil
+}
@@ -0,0 +1,88 @@
+package proxmox
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func DataNodes() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataReadNodes,
+		Schema: map[string]*schema.Schema{
+			"online": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Only return the nodes that are online.",
+			},
+			"bridge": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the nodes that have the bridge, implies online.",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The names of the nodes, the same format as target_nodes.",
+			},
+			"nodes": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: dataNodeAttributes(),
+				},
+				Description: "The nodes that match all filters, ordered by name.",
+			},
+		},
+	}
+}
+
+func dataReadNodes(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	list, err := dataNodeList(ctx, pconf.Client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	bridge := d.Get("bridge").(string)
+	nodes := make([]interface{}, 0, len(list))
+	names := make([]string, 0, len(list))
+	sorted := make([]string, 0, len(list))
+	for name := range list {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	for _, name := range sorted {
+		n, err := dataNode(ctx, pconf.Client, list[name])
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if (d.Get("online").(bool) || bridge != "") && !n["online"].(bool) {
+			continue
+		}
+		if bridge != "" && !dataNodeHasBridge(n, bridge) {
+			continue
+		}
+		nodes = append(nodes, n)
+		names = append(names, name)
+	}
+	d.SetId("nodes")
+	d.Set("nodes", nodes)
+	d.Set("names", names)
+	return nil
+}
+
+func dataNodeHasBridge(n map[string]interface{}, bridge string) bool {
+	for _, b := range n["bridges"].([]string) {
+		if b == bridge {
+			return true
+		}
+	}
+	return false
+}
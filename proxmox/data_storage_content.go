@@ -0,0 +1,169 @@
+package proxmox
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/node"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func DataStorageContent() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataReadStorageContent,
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: node.ValidateName("node"),
+				Description:      "The node to list the storage content of.",
+			},
+			"storage": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The storage to list the content of.",
+			},
+			"content": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"backup", "images", "import", "iso", "rootdir", "snippets", "vztmpl"}, false),
+				Description:  "Only list the volumes of the content type.",
+			},
+			"volid_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Only list the volumes whose volume ID matches the regular expression.",
+			},
+			"vmid": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(100, 999999999),
+				Description:  "Only list the volumes that belong to the guest, like its disks and backups.",
+			},
+			"most_recent": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Only return the newest volume, fails when no volume matches.",
+			},
+			"volumes": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"volid":   {Type: schema.TypeString, Computed: true},
+						"file":    {Type: schema.TypeString, Computed: true},
+						"content": {Type: schema.TypeString, Computed: true},
+						"format":  {Type: schema.TypeString, Computed: true},
+						"size":    {Type: schema.TypeInt, Computed: true},
+						"vmid":    {Type: schema.TypeInt, Computed: true},
+						"created": {Type: schema.TypeString, Computed: true},
+						"notes":   {Type: schema.TypeString, Computed: true},
+					},
+				},
+				Description: "The volumes that match all filters, the newest first.",
+			},
+		},
+	}
+}
+
+// storageContent returns the volumes on a storage of a node.
+func storageContent(ctx context.Context, client *pveSDK.Client, storage string, node pveSDK.NodeName) ([]map[string]interface{}, error) {
+	content, err := client.GetStorageContent(ctx, storage, node)
+	if err != nil {
+		return nil, err
+	}
+	data, _ := content["data"].([]interface{})
+	volumes := make([]map[string]interface{}, 0, len(data))
+	for _, e := range data {
+		if volume, ok := e.(map[string]interface{}); ok {
+			volumes = append(volumes, volume)
+		}
+	}
+	return volumes, nil
+}
+
+// storageContentFile returns the name of the file of a volume without the storage and content directory,
+// the format the template block of proxmox_lxc_guest takes.
+func storageContentFile(volid string) string {
+	_, path, _ := strings.Cut(volid, ":")
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
+
+func dataReadStorageContent(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	nodeName, storage := d.Get("node").(string), d.Get("storage").(string)
+	list, err := storageContent(ctx, pconf.Client, storage, pveSDK.NodeName(nodeName))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var rx *regexp.Regexp
+	if expr := d.Get("volid_regex").(string); expr != "" {
+		if rx, err = regexp.Compile(expr); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	content, vmid := d.Get("content").(string), d.Get("vmid").(int)
+
+	volumes := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		volid := itemValue(item, "volid")
+		volumeVmid, _ := item["vmid"].(float64)
+		if (content != "" && itemValue(item, "content") != content) || (vmid != 0 && int(volumeVmid) != vmid) ||
+			(rx != nil && !rx.MatchString(volid)) {
+			continue
+		}
+		size, _ := item["size"].(float64)
+		ctime, _ := item["ctime"].(float64)
+		created := ""
+		if ctime > 0 {
+			created = time.Unix(int64(ctime), 0).UTC().Format(time.RFC3339)
+		}
+		volumes = append(volumes, map[string]interface{}{
+			"volid":   volid,
+			"file":    storageContentFile(volid),
+			"content": itemValue(item, "content"),
+			"format":  itemValue(item, "format"),
+			"size":    int64(size),
+			"vmid":    int(volumeVmid),
+			"created": created,
+			"notes":   itemValue(item, "notes"),
+			"ctime":   int64(ctime),
+		})
+	}
+	// Volumes created in the same second, like the templates of a storage that was filled at once, are ordered by
+	// their volume ID, which puts the higher version first for names like debian-12-standard.
+	sort.SliceStable(volumes, func(i, j int) bool {
+		if volumes[i]["ctime"].(int64) != volumes[j]["ctime"].(int64) {
+			return volumes[i]["ctime"].(int64) > volumes[j]["ctime"].(int64)
+		}
+		return volumes[i]["volid"].(string) > volumes[j]["volid"].(string)
+	})
+	if d.Get("most_recent").(bool) {
+		if len(volumes) == 0 {
+			return diag.Errorf("no volume on storage '%s' of node '%s' matches the filters", storage, nodeName)
+		}
+		volumes = volumes[:1]
+	}
+	result := make([]interface{}, len(volumes))
+	for i, volume := range volumes {
+		delete(volume, "ctime")
+		result[i] = volume
+	}
+	d.SetId(nodeName + "/" + storage)
+	d.Set("volumes", result)
+	return nil
+}
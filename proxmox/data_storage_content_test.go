@@ -0,0 +1,74 @@
+package proxmox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DataStorageContent_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t, "pve")
+	fake.PutFile("pve", "local", "vztmpl", "debian-11-standard_11.7-1_amd64.tar.zst", []byte("bullseye"))
+	fake.PutFile("pve", "local", "vztmpl", "debian-12-standard_12.7-1_amd64.tar.zst", []byte("bookworm"))
+	fake.PutFile("pve", "local", "vztmpl", "alpine-3.22-default_20250617_amd64.tar.xz", []byte("alpine"))
+	fake.PutFile("pve", "local", "iso", "debian-12.iso", []byte("iso"))
+	fake.PutFile("pve", "local", "backup", "vzdump-qemu-120-2026_01_02-03_00_00.vma.zst", []byte("newer"))
+	fake.PutFile("pve", "local", "backup", "vzdump-qemu-120-2026_01_01-03_00_00.vma.zst", []byte("older"))
+	fake.PutFile("pve", "local", "backup", "vzdump-lxc-121-2026_01_03-03_00_00.tar.zst", []byte("other guest"))
+	meta := testFakeMeta(t, fake)
+
+	r := DataStorageContent()
+	volids := func(config map[string]any) []string {
+		config["node"], config["storage"] = "pve", "local"
+		d := schema.TestResourceDataRaw(t, r.Schema, config)
+		testFakeRead(t, r, meta, d)
+		ids := []string{}
+		for _, volume := range d.Get("volumes").([]any) {
+			ids = append(ids, volume.(map[string]any)["volid"].(string))
+		}
+		return ids
+	}
+
+	require.Equal(t, []string{"local:iso/debian-12.iso"}, volids(map[string]any{"content": "iso"}))
+	require.Equal(t, []string{
+		"local:backup/vzdump-qemu-120-2026_01_02-03_00_00.vma.zst",
+		"local:backup/vzdump-qemu-120-2026_01_01-03_00_00.vma.zst",
+	}, volids(map[string]any{"content": "backup", "vmid": 120}))
+	require.Equal(t, []string{"local:backup/vzdump-qemu-120-2026_01_02-03_00_00.vma.zst"},
+		volids(map[string]any{"content": "backup", "vmid": 120, "most_recent": true}))
+	require.Equal(t, []string{"local:vztmpl/debian-12-standard_12.7-1_amd64.tar.zst"},
+		volids(map[string]any{"content": "vztmpl", "volid_regex": "/debian-", "most_recent": true}))
+	require.Len(t, volids(map[string]any{"volid_regex": "debian"}), 3)
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]any{"node": "pve", "storage": "local", "vmid": 121})
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "vzdump-lxc-121-2026_01_03-03_00_00.tar.zst", d.Get("volumes.0.file"))
+	require.Equal(t, 121, d.Get("volumes.0.vmid"))
+	require.NotEmpty(t, d.Get("volumes.0.created"))
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]any{"node": "pve", "storage": "local", "content": "vztmpl", "volid_regex": "ubuntu"})
+	testFakeRead(t, r, meta, d)
+	require.Empty(t, d.Get("volumes"))
+	d.Set("most_recent", true)
+	require.True(t, r.ReadContext(context.Background(), d, meta).HasError())
+}
+
+func Test_DataStorageContent_Validation(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]any
+	}{
+		{name: "node", config: map[string]any{"node": "-pve", "storage": "local"}},
+		{name: "content", config: map[string]any{"node": "pve", "storage": "local", "content": "template"}},
+		{name: "regex", config: map[string]any{"node": "pve", "storage": "local", "volid_regex": "debian("}},
+		{name: "vmid", config: map[string]any{"node": "pve", "storage": "local", "vmid": 99}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.True(t, DataStorageContent().Validate(terraform.NewResourceConfigRaw(test.config)).HasError())
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/fakepve"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/gocty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// testAccFakeProvider starts a fake Proxmox API for the duration of the test.
+// It returns the fake together with a provider block that points at it, which should be prepended to the test config.
+func testAccFakeProvider(t *testing.T, nodes ...string) (*fakepve.Server, string) {
+	fake := fakepve.New(nodes...)
+	t.Cleanup(fake.Close)
+	return fake, fmt.Sprintf(`
+provider "proxmox" {
+  pm_api_url      = "%s"
+  pm_user         = "%s"
+  pm_password     = "%s"
+  pm_tls_insecure = true
+}
+`, fake.ApiURL(), fakepve.User, fakepve.Password)
+}
+
+// testFakeMeta configures the provider against the fake, the same way Terraform would.
+func testFakeMeta(t *testing.T, fake *fakepve.Server) *providerConfiguration {
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]any{
+		schemaPmApiUrl:      fake.ApiURL(),
+		schemaPmUser:        fakepve.User,
+		schemaPmPassword:    fakepve.Password,
+		schemaPmTlsInsecure: true})
+	meta, err := providerConfigure(d)
+	if err != nil {
+		t.Fatalf("configuring provider against fake API: %v", err)
+	}
+	return meta.(*providerConfiguration)
+}
+
+// testFakeCreate runs the create function of a resource against the fake and fails the test on any error.
+func testFakeCreate(t *testing.T, r *schema.Resource, meta *providerConfiguration, config map[string]any) *schema.ResourceData {
+	d := schema.TestResourceDataRaw(t, r.Schema, config)
+	if diags := r.CreateContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("create: %+v", diags)
+	}
+	return d
+}
+
+// testFakeCreateRaw creates a resource with the raw config Terraform sends along, for create functions that tell
+// a value set to its zero value from an unset value. Only top level attributes of a primitive type are supported.
+// The diagnostics of the create are returned instead of failing the test.
+func testFakeCreateRaw(t *testing.T, r *schema.Resource, meta *providerConfiguration, config map[string]any) (*schema.ResourceData, diag.Diagnostics) {
+	ty := r.CoreConfigSchema().ImpliedType()
+	attrs := make(map[string]cty.Value, len(ty.AttributeTypes()))
+	for name, attrType := range ty.AttributeTypes() {
+		attrs[name] = cty.NullVal(attrType)
+		if v, ok := config[name]; ok {
+			value, err := gocty.ToCtyValue(v, attrType)
+			if err != nil {
+				t.Fatalf("raw config of %s: %v", name, err)
+			}
+			attrs[name] = value
+		}
+	}
+	state := &terraform.InstanceState{RawConfig: cty.ObjectVal(attrs)}
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), meta)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	state, diags := r.Apply(context.Background(), state, diff, meta)
+	return r.Data(state), diags
+}
+
+// testFakeUpdate plans the new config against the state of d and applies it, the same way Terraform would.
+func testFakeUpdate(t *testing.T, r *schema.Resource, meta *providerConfiguration, d *schema.ResourceData, config map[string]any) *schema.ResourceData {
+	state := d.State()
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), meta)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if diff == nil { // nothing to change
+		return d
+	}
+	state, diags := r.Apply(context.Background(), state, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("update: %+v", diags)
+	}
+	return r.Data(state)
+}
+
+// testFakeRead runs the read function of a resource against the fake and fails the test on any error.
+func testFakeRead(t *testing.T, r *schema.Resource, meta *providerConfiguration, d *schema.ResourceData) {
+	if diags := r.ReadContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("read: %+v", diags)
+	}
+}
+
+// testFakeDelete runs the delete function of a resource against the fake and fails the test on any error.
+func testFakeDelete(t *testing.T, r *schema.Resource, meta *providerConfiguration, d *schema.ResourceData) {
+	if diags := r.DeleteContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("delete: %+v", diags)
+	}
+}
+
+// testFakeUnknown fails the test when the provider called API endpoints the fake does not implement.
+func testFakeUnknown(t *testing.T, fake *fakepve.Server) {
+	if unknown := fake.Unknown(); len(unknown) > 0 {
+		t.Fatalf("unhandled API calls: %v", unknown)
+	}
+}
@@ -3,10 +3,15 @@ package proxmox
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/url"
 	"path"
 	"strconv"
+	"strings"
 
 	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/_sub/vnet"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/migration"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -31,6 +36,58 @@ func guestDelete(ctx context.Context, d *schema.ResourceData, meta any, kind str
 	return nil
 }
 
+// guestMigrate moves the guest to the target node and updates the node of vmr.
+// When the migration fails, the error contains the log of the migration task and vmr is set to the node the guest is on afterwards.
+func guestMigrate(ctx context.Context, client *pveSDK.Client, vmr *pveSDK.VmRef, target pveSDK.NodeName, config migration.Config) error {
+	source := vmr.Node()
+	guestType := vmr.GetVmType()
+	_, err := client.PostWithTask(ctx, config.Params(guestType, target), "/nodes/"+source.String()+"/"+guestType.String()+"/"+vmr.VmId().String()+"/migrate")
+	if err == nil {
+		vmr.SetNode(target.String())
+		return nil
+	}
+	current := source
+	if currentVmr, errRef := client.GetVmRefById(ctx, vmr.VmId()); errRef == nil {
+		current = currentVmr.Node()
+		vmr.SetNode(current.String())
+	}
+	msg := fmt.Sprintf("migration of guest %d from node '%s' to node '%s' failed, the guest is on node '%s': %v", vmr.VmId(), source, target, current, err)
+	if log := guestMigrationLog(ctx, client, source, vmr.VmId(), guestType); log != "" {
+		msg += "\n\n" + log
+	}
+	return errors.New(msg)
+}
+
+// guestMigrationLog returns the log of the last migration task of the guest, or an empty string when it can't be retrieved.
+func guestMigrationLog(ctx context.Context, client *pveSDK.Client, node pveSDK.NodeName, id pveSDK.GuestID, guestType pveSDK.GuestType) string {
+	taskType := "qmigrate"
+	if guestType == pveSDK.GuestLxc {
+		taskType = "vzmigrate"
+	}
+	tasks, err := client.GetItemListInterfaceArray(ctx, "/nodes/"+node.String()+"/tasks?limit=1&typefilter="+taskType+"&vmid="+id.String())
+	if err != nil || len(tasks) == 0 {
+		return ""
+	}
+	task, ok := tasks[0].(map[string]any)
+	if !ok {
+		return ""
+	}
+	upid, _ := task["upid"].(string)
+	lines, err := client.GetItemListInterfaceArray(ctx, "/nodes/"+node.String()+"/tasks/"+url.PathEscape(upid)+"/log")
+	if err != nil {
+		return ""
+	}
+	log := make([]string, 0, len(lines))
+	for _, e := range lines {
+		if line, ok := e.(map[string]any); ok {
+			if text, ok := line["t"].(string); ok {
+				log = append(log, text)
+			}
+		}
+	}
+	return strings.Join(log, "\n")
+}
+
 func guestGetSourceVmr(
 	ctx context.Context,
 	client pveSDK.GuestInterface,
@@ -39,54 +96,86 @@ func guestGetSourceVmr(
 	preferredNode pveSDK.NodeName,
 	guest pveSDK.GuestType,
 	fieldName, fieldID string) (*pveSDK.VmRef, error) {
+	if name == "" && id == 0 {
+		return nil, errors.New("either '" + fieldName + "' or '" + fieldID + "' must be specified")
+	}
+	rawGuests, err := client.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var raw pveSDK.RawGuestResource
 	if name != "" {
-		rawGuests, err := client.List(ctx)
-		if err != nil {
-			return nil, err
-		}
-		return guestGetSourceVmrByNode(rawGuests, name, preferredNode, guest)
-	} else if id != 0 {
-		rawGuests, err := client.List(ctx)
-		if err != nil {
-			return nil, err
-		}
-		for e := range rawGuests.Iter() {
-			if localID := e.GetID(); localID == id {
-				if e.GetType() != guest {
-					return nil, errors.New("guest with ID '" + id.String() + "' is not of type '" + string(guest) + "'")
-				}
-				guestRef := pveSDK.NewVmRef(e.GetID())
-				guestRef.SetNode(string(e.GetNode()))
-				guestRef.SetVmType(guest)
-				return guestRef, nil
+		raw, err = guestGetSourceByName(rawGuests, name, preferredNode, guest)
+	} else {
+		raw, err = guestGetSourceByID(rawGuests, id, guest)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return guestRef(raw, guest), nil
+}
+
+// guestGetSourceByName returns the guest of the type with the name, the guest on preferredNode is preferred when several guests have the name.
+func guestGetSourceByName(raw pveSDK.RawGuestResources, name pveSDK.GuestName, preferredNode pveSDK.NodeName, guest pveSDK.GuestType) (pveSDK.RawGuestResource, error) {
+	var found pveSDK.RawGuestResource
+	for e := range raw.Iter() {
+		if e.GetName() == name && e.GetType() == guest {
+			if e.GetNode() == preferredNode { // Prefer source VM on the same node
+				return e, nil
+			}
+			if found == nil { // remember the first we find
+				found = e
 			}
 		}
-		return nil, errors.New("guest with ID '" + id.String() + "' does not exist")
 	}
-	return nil, errors.New("either '" + fieldName + "' or '" + fieldID + "' must be specified")
+	if found == nil {
+		return nil, errors.New("no guest with name '" + name.String() + "' found")
+	}
+	return found, nil
 }
 
-func guestGetSourceVmrByNode(raw pveSDK.RawGuestResources, name pveSDK.GuestName, preferredNode pveSDK.NodeName, guest pveSDK.GuestType) (*pveSDK.VmRef, error) {
-	var guestRef *pveSDK.VmRef
+// guestGetSourceByID returns the guest with the ID, it must be of the type.
+func guestGetSourceByID(raw pveSDK.RawGuestResources, id pveSDK.GuestID, guest pveSDK.GuestType) (pveSDK.RawGuestResource, error) {
 	for e := range raw.Iter() {
-		if e.GetName() == name {
-			if e.GetType() == guest {
-				if node := e.GetNode(); node == preferredNode { // Prefer source VM on the same node
-					guestRef = pveSDK.NewVmRef(e.GetID())
-					guestRef.SetNode(string(node))
-					guestRef.SetVmType(guest)
-					return guestRef, nil
-				}
-				if guestRef == nil { // remember the first we find
-					guestRef = pveSDK.NewVmRef(e.GetID())
-					guestRef.SetNode(string(e.GetNode()))
-					guestRef.SetVmType(guest)
-				}
+		if e.GetID() == id {
+			if e.GetType() != guest {
+				return nil, errors.New("guest with ID '" + id.String() + "' is not of type '" + guest.String() + "'")
 			}
+			return e, nil
 		}
 	}
-	if guestRef == nil {
-		return nil, errors.New("no guest with name '" + name.String() + "' found")
+	return nil, errors.New("guest with ID '" + id.String() + "' does not exist")
+}
+
+func guestRef(raw pveSDK.RawGuestResource, guest pveSDK.GuestType) *pveSDK.VmRef {
+	ref := pveSDK.NewVmRef(raw.GetID())
+	ref.SetNode(string(raw.GetNode()))
+	ref.SetVmType(guest)
+	return ref
+}
+
+// guestBridges looks up the SDN VNets and the bridges of the node, which guests on the node can connect their network devices to.
+func guestBridges(ctx context.Context, client *pveSDK.Client, node pveSDK.NodeName) vnet.Lookup {
+	return func() (map[string]struct{}, error) {
+		bridges := map[string]struct{}{}
+		vnets, err := client.GetItemListInterfaceArray(ctx, "/cluster/sdn/vnets")
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range vnets {
+			if item, ok := e.(map[string]interface{}); ok {
+				bridges[itemValue(item, "vnet")] = struct{}{}
+			}
+		}
+		interfaces, err := client.GetItemListInterfaceArray(ctx, "/nodes/"+node.String()+"/network?type=any_bridge")
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range interfaces {
+			if item, ok := e.(map[string]interface{}); ok {
+				bridges[itemValue(item, "iface")] = struct{}{}
+			}
+		}
+		return bridges, nil
 	}
-	return guestRef, nil
 }
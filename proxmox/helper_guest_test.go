@@ -142,9 +142,13 @@ func Test_UserID_Validate(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			vmref, err := guestGetSourceVmrByNode(raw, test.input.name, test.input.preferredNode, test.input.guestType)
-			require.Equal(t, test.output, vmref)
+			e, err := guestGetSourceByName(raw, test.input.name, test.input.preferredNode, test.input.guestType)
 			require.Equal(t, test.err, err)
+			if err != nil {
+				require.Nil(t, e)
+				return
+			}
+			require.Equal(t, test.output, guestRef(e, test.input.guestType))
 		})
 	}
 }
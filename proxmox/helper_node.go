@@ -0,0 +1,162 @@
+package proxmox
+
+import (
+	"context"
+	"path"
+	"strconv"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/node"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/placementgroup"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// nodePlacement returns the live data of the nodes that the placement strategies rank the nodes by.
+// storage is the storage of the guest, it is only used by the storage strategy.
+func nodePlacement(ctx context.Context, pconf *providerConfiguration, storage string) *node.Placement {
+	client := pconf.Client
+	candidates := func(strategy node.Strategy, nodes []pveSDK.NodeName) ([]node.Candidate, error) {
+		list, err := client.GetNodeList(ctx)
+		if err != nil {
+			return nil, err
+		}
+		status := map[pveSDK.NodeName]map[string]any{}
+		if data, ok := list["data"].([]any); ok {
+			for _, e := range data {
+				if info, ok := e.(map[string]any); ok {
+					name, _ := info["node"].(string)
+					status[pveSDK.NodeName(name)] = info
+				}
+			}
+		}
+		guests := map[pveSDK.NodeName]int{}
+		newest := map[pveSDK.NodeName]pveSDK.GuestID{}
+		if strategy == node.StrategyGuests || strategy == node.StrategyRoundRobin {
+			resources, err := client.GetResourceList(ctx, "vm")
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range resources {
+				if info, ok := e.(map[string]any); ok {
+					name, _ := info["node"].(string)
+					guests[pveSDK.NodeName(name)]++
+					if id, _ := info["vmid"].(float64); pveSDK.GuestID(id) > newest[pveSDK.NodeName(name)] {
+						newest[pveSDK.NodeName(name)] = pveSDK.GuestID(id)
+					}
+				}
+			}
+		}
+		candidates := make([]node.Candidate, len(nodes))
+		for i, name := range nodes {
+			candidates[i] = node.Candidate{Name: name, Guests: guests[name], Newest: newest[name], StorageAvailable: -1}
+			info, ok := status[name]
+			if !ok || info["status"] != "online" {
+				continue
+			}
+			candidates[i].Online = true
+			switch strategy {
+			case node.StrategyMemory:
+				candidates[i].MemoryPressure = nodeMemoryPressure(ctx, client, name, info)
+			case node.StrategyStorage:
+				candidates[i].StorageAvailable = nodeStorageAvailable(ctx, client, name, storage)
+			}
+		}
+		return candidates, nil
+	}
+	return &node.Placement{Candidates: candidates, Rotation: pconf.Rotation}
+}
+
+// nodeMemoryPressure returns the average fraction of the memory that was in use over the last hour.
+// When the RRD data is not available, the current usage from the node list is used.
+func nodeMemoryPressure(ctx context.Context, client *pveSDK.Client, name pveSDK.NodeName, info map[string]any) float64 {
+	var sum float64
+	var samples int
+	if rrd, err := client.GetItemListInterfaceArray(ctx, "/nodes/"+name.String()+"/rrddata?timeframe=hour&cf=AVERAGE"); err == nil {
+		for _, e := range rrd {
+			sample, ok := e.(map[string]any)
+			if !ok {
+				continue
+			}
+			used, _ := sample["memused"].(float64)
+			total, _ := sample["memtotal"].(float64)
+			if total > 0 {
+				sum += used / total
+				samples++
+			}
+		}
+	}
+	if samples > 0 {
+		return sum / float64(samples)
+	}
+	used, _ := info["mem"].(float64)
+	total, _ := info["maxmem"].(float64)
+	if total > 0 {
+		return used / total
+	}
+	return 0
+}
+
+// nodeStorageAvailable returns the free space in bytes on the storage of the node, or -1 when the storage is not available on the node.
+// Without a storage every node is considered to have the same space available.
+func nodeStorageAvailable(ctx context.Context, client *pveSDK.Client, name pveSDK.NodeName, storage string) int64 {
+	if storage == "" {
+		return 0
+	}
+	status, err := client.GetItemConfigMapStringInterface(ctx, "/nodes/"+name.String()+"/storage/"+storage+"/status", "storage", "status")
+	if err != nil {
+		return -1
+	}
+	if active, ok := status["active"].(float64); ok && active == 0 {
+		return -1
+	}
+	avail, _ := status["avail"].(float64)
+	return int64(avail)
+}
+
+// nodeGroupFilter limits the nodes to the ones allowed by the placement group of the guest.
+// The guest with the ID self is not counted as a member of the group, so an existing guest does not conflict with itself.
+func nodeGroupFilter(ctx context.Context, client *pveSDK.Client, group *placementgroup.Config, self pveSDK.GuestID) node.Filter {
+	if group == nil {
+		return nil
+	}
+	return func(nodes []pveSDK.NodeName) ([]pveSDK.NodeName, error) {
+		guests, err := client.GetResourceList(ctx, "vm")
+		if err != nil {
+			return nil, err
+		}
+		members, err := group.Members(guests, self)
+		if err != nil {
+			return nil, err
+		}
+		return group.Filter(members, nodes)
+	}
+}
+
+// placementGroupCustomizeDiff fails the plan when the placement group of the guest can not be satisfied by target_node or target_nodes.
+func placementGroupCustomizeDiff() schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+		group := placementgroup.SDKDiff(d)
+		pconf, ok := meta.(*providerConfiguration)
+		if group == nil || !ok || pconf == nil {
+			return nil
+		}
+		var nodes []pveSDK.NodeName
+		if v, ok := d.GetOk(node.RootNode); ok {
+			nodes = []pveSDK.NodeName{pveSDK.NodeName(v.(string))}
+		} else if v, ok := d.GetOk(node.RootNodes); ok {
+			for _, e := range v.(*schema.Set).List() {
+				nodes = append(nodes, pveSDK.NodeName(e.(string)))
+			}
+		}
+		if len(nodes) == 0 || !d.NewValueKnown(node.RootNode) || !d.NewValueKnown(node.RootNodes) {
+			return nil
+		}
+		var self pveSDK.GuestID
+		if d.Id() != "" {
+			id, _ := strconv.Atoi(path.Base(d.Id()))
+			self = pveSDK.GuestID(id)
+		}
+		_, err := nodeGroupFilter(ctx, pconf.Client, group, self)(nodes)
+		return err
+	}
+}
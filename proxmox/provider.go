@@ -13,6 +13,7 @@ import (
 	"time"
 
 	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/node"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/util"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/validator"
 	"github.com/hashicorp/go-cty/cty"
@@ -53,6 +54,10 @@ type providerConfiguration struct {
 	LogFile                            string
 	LogLevels                          map[string]string
 	DangerouslyIgnoreUnknownAttributes bool
+	// NetworkMutex serializes the changes to the network of the nodes, as PVE applies and reverts all pending changes of a node at once.
+	NetworkMutex *sync.Mutex
+	// Rotation remembers the nodes the round robin placement strategy picked.
+	Rotation *node.Rotation
 }
 
 // Provider - Terrafrom properties for proxmox
@@ -203,19 +208,73 @@ func Provider() *schema.Provider {
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"proxmox_vm_qemu":         resourceVmQemu(),
-			"proxmox_lxc":             resourceLxc(),
-			"proxmox_lxc_disk":        resourceLxcDisk(),
-			"proxmox_lxc_guest":       resourceLxcGuest(),
-			"proxmox_pool":            resourcePool(),
-			"proxmox_cloud_init_disk": resourceCloudInitDisk(),
-			"proxmox_storage_iso":     resourceStorageIso(),
-			// TODO - proxmox_bridge
-			// TODO - proxmox_vm_qemu_template
+			"proxmox_vm_qemu":             resourceVmQemu(),
+			"proxmox_lxc":                 resourceLxc(),
+			"proxmox_lxc_disk":            resourceLxcDisk(),
+			"proxmox_lxc_guest":           resourceLxcGuest(),
+			"proxmox_pool":                resourcePool(),
+			"proxmox_pool_membership":     resourcePoolMembership(),
+			"proxmox_cloud_init_disk":     resourceCloudInitDisk(),
+			"proxmox_storage_iso":         resourceStorageIso(),
+			"proxmox_storage_file":        resourceStorageFile(),
+			"proxmox_vm_qemu_snapshot":    resourceVmQemuSnapshot(),
+			"proxmox_lxc_snapshot":        resourceLxcSnapshot(),
+			"proxmox_backup_job":          resourceBackupJob(),
+			"proxmox_vm_qemu_template":    resourceVmQemuTemplate(),
+			"proxmox_ha_group":            resourceHAGroup(),
+			"proxmox_ha_resource":         resourceHAResource(),
+			"proxmox_ha_rule":             resourceHARule(),
+			"proxmox_user":                resourceUser(),
+			"proxmox_group":               resourceGroup(),
+			"proxmox_role":                resourceRole(),
+			"proxmox_acl":                 resourceACL(),
+			"proxmox_api_token":           resourceApiToken(),
+			"proxmox_realm_ldap":          resourceRealmLdap(),
+			"proxmox_realm_ad":            resourceRealmAd(),
+			"proxmox_realm_openid":        resourceRealmOpenID(),
+			"proxmox_storage_dir":         resourceStorageDir(),
+			"proxmox_storage_nfs":         resourceStorageNfs(),
+			"proxmox_storage_cifs":        resourceStorageCifs(),
+			"proxmox_storage_lvm":         resourceStorageLvm(),
+			"proxmox_storage_lvmthin":     resourceStorageLvmThin(),
+			"proxmox_storage_zfspool":     resourceStorageZfsPool(),
+			"proxmox_storage_rbd":         resourceStorageRbd(),
+			"proxmox_storage_cephfs":      resourceStorageCephFS(),
+			"proxmox_storage_pbs":         resourceStoragePbs(),
+			"proxmox_network_bridge":      resourceNetworkBridge(),
+			"proxmox_network_bond":        resourceNetworkBond(),
+			"proxmox_network_vlan":        resourceNetworkVlan(),
+			"proxmox_sdn_zone_simple":     resourceSDNZoneSimple(),
+			"proxmox_sdn_zone_vlan":       resourceSDNZoneVlan(),
+			"proxmox_sdn_zone_qinq":       resourceSDNZoneQinQ(),
+			"proxmox_sdn_zone_vxlan":      resourceSDNZoneVxlan(),
+			"proxmox_sdn_zone_evpn":       resourceSDNZoneEvpn(),
+			"proxmox_sdn_vnet":            resourceSDNVNet(),
+			"proxmox_sdn_subnet":          resourceSDNSubnet(),
+			"proxmox_sdn_controller_evpn": resourceSDNControllerEvpn(),
+			"proxmox_sdn_controller_bgp":  resourceSDNControllerBgp(),
+			"proxmox_sdn_controller_isis": resourceSDNControllerIsis(),
+			"proxmox_sdn_apply":           resourceSDNApply(),
+			"proxmox_firewall_options":    resourceFirewallOptions(),
+			"proxmox_firewall_rules":      resourceFirewallRules(),
+			"proxmox_firewall_alias":      resourceFirewallAlias(),
+			"proxmox_firewall_ipset":      resourceFirewallIPSet(),
+			"proxmox_firewall_group":      resourceFirewallGroup(),
+			"proxmox_mapping_pci":         resourceMappingPCI(),
+			"proxmox_mapping_usb":         resourceMappingUSB(),
+			"proxmox_replication_job":     resourceReplicationJob(),
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"proxmox_ha_groups": DataHAGroup(),
+			"proxmox_ha_groups":        DataHAGroup(),
+			"proxmox_vm_qemu":          DataVmQemu(),
+			"proxmox_lxc_guest":        DataLxcGuest(),
+			"proxmox_guests":           DataGuests(),
+			"proxmox_node":             DataNode(),
+			"proxmox_nodes":            DataNodes(),
+			"proxmox_storage_content":  DataStorageContent(),
+			"proxmox_node_pci_devices": DataNodePciDevices(),
+			"proxmox_node_usb_devices": DataNodeUsbDevices(),
 		},
 
 		ConfigureFunc: providerConfigure,
@@ -321,6 +380,8 @@ func providerConfigure(d *schema.ResourceData) (any, error) {
 		MaxGuestID:                         0,
 		Mutex:                              &mut,
 		Cond:                               sync.NewCond(&mut),
+		NetworkMutex:                       &sync.Mutex{},
+		Rotation:                           &node.Rotation{},
 		LogFile:                            d.Get(schemaPmLogFile).(string),
 		LogLevels:                          logLevels,
 		DangerouslyIgnoreUnknownAttributes: d.Get(schemaPmDangerouslyIgnoreUnknownAttributes).(bool),
@@ -0,0 +1,200 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const aclResourceType = "acl"
+
+var rxACLPath = regexp.MustCompile(`^/`)
+
+// aclTargets maps the argument that holds the user, group or token of an ACL entry to the type PVE uses for it.
+var aclTargets = map[string]string{
+	"user_id":  "user",
+	"group":    "group",
+	"token_id": "token",
+}
+
+func resourceACL() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceACLCreate,
+		ReadContext:   resourceACLRead,
+		UpdateContext: resourceACLUpdate,
+		DeleteContext: resourceACLDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"path": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(rxACLPath, "must start with '/'"),
+				Description:  "The path the role is granted on, e.g. `/vms/100` or `/pool/team-a`.",
+			},
+			"role": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The role that is granted.",
+			},
+			"user_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"user_id", "group", "token_id"},
+				Description:  "The user the role is granted to, in the format `name@realm`.",
+			},
+			"group": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The group the role is granted to.",
+			},
+			"token_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The API token the role is granted to, in the format `name@realm!token`.",
+			},
+			"propagate": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Also grant the role on the paths below the path.",
+			},
+		},
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+// aclTarget returns the PVE type and the ID of the user, group or token of the ACL entry.
+func aclTarget(d *schema.ResourceData) (string, string) {
+	for key, kind := range aclTargets {
+		if v := d.Get(key).(string); v != "" {
+			return kind, v
+		}
+	}
+	return "", ""
+}
+
+// aclResourceId returns the ID of an ACL entry in the format `acl/<type>/<ugid>/<role><path>`.
+func aclResourceId(kind, ugid, role, path string) string {
+	return clusterResourceId(aclResourceType, kind+"/"+ugid+"/"+role+path)
+}
+
+func parseACLResourceId(id string) (kind, ugid, role, path string, err error) {
+	parts := strings.SplitN(strings.TrimPrefix(id, aclResourceType+"/"), "/", 4)
+	if !strings.HasPrefix(id, aclResourceType+"/") || len(parts) != 4 {
+		return "", "", "", "", fmt.Errorf("invalid resource format: %s. Must be %s/<type>/<ugid>/<role>/<path>", id, aclResourceType)
+	}
+	return parts[0], parts[1], parts[2], "/" + parts[3], nil
+}
+
+// aclParams returns the parameters of the request that grants or, when remove is set, revokes the ACL entry.
+func aclParams(kind, ugid, role, path string, propagate, remove bool) map[string]interface{} {
+	params := map[string]interface{}{
+		"path":      path,
+		"roles":     role,
+		kind + "s":  ugid,
+		"propagate": propagate,
+	}
+	if remove {
+		params["delete"] = true
+	}
+	return params
+}
+
+func resourceACLCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	kind, ugid := aclTarget(d)
+	role := d.Get("role").(string)
+	path := d.Get("path").(string)
+	if err := pconf.Client.Put(ctx, aclParams(kind, ugid, role, path, d.Get("propagate").(bool), false), "/access/acl"); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(aclResourceId(kind, ugid, role, path))
+	return diag.FromErr(_resourceACLRead(ctx, d, pconf.Client))
+}
+
+func resourceACLRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return diag.FromErr(_resourceACLRead(ctx, d, pconf.Client))
+}
+
+func _resourceACLRead(ctx context.Context, d *schema.ResourceData, client *pveSDK.Client) error {
+	kind, ugid, role, path, err := parseACLResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+	list, err := client.GetItemListInterfaceArray(ctx, "/access/acl")
+	if err != nil {
+		return err
+	}
+	var item map[string]interface{}
+	for _, e := range list {
+		entry, ok := e.(map[string]interface{})
+		if ok && itemValue(entry, "path") == path && itemValue(entry, "type") == kind &&
+			itemValue(entry, "ugid") == ugid && itemValue(entry, "roleid") == role {
+			item = entry
+			break
+		}
+	}
+	if item == nil {
+		d.SetId("")
+		return nil
+	}
+	d.Set("path", path)
+	d.Set("role", role)
+	for key, target := range aclTargets {
+		if target == kind {
+			d.Set(key, ugid)
+		} else {
+			d.Set(key, "")
+		}
+	}
+	d.Set("propagate", itemValue(item, "propagate") != "0")
+	return nil
+}
+
+func resourceACLUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	kind, ugid, role, path, err := parseACLResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err = pconf.Client.Put(ctx, aclParams(kind, ugid, role, path, d.Get("propagate").(bool), false), "/access/acl"); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(_resourceACLRead(ctx, d, pconf.Client))
+}
+
+func resourceACLDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	kind, ugid, role, path, err := parseACLResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(pconf.Client.Put(ctx, aclParams(kind, ugid, role, path, d.Get("propagate").(bool), true), "/access/acl"))
+}
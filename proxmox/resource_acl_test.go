@@ -0,0 +1,63 @@
+package proxmox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResourceACL_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	testFakeCreate(t, resourceUser(), meta, map[string]any{"user_id": "ci@pve"})
+	testFakeCreate(t, resourceApiToken(), meta, map[string]any{"user_id": "ci@pve", "name": "pipeline"})
+	r := resourceACL()
+
+	d := testFakeCreate(t, r, meta, map[string]any{"path": "/", "role": "PVEAuditor", "user_id": "ci@pve"})
+	require.Equal(t, "acl/user/ci@pve/PVEAuditor/", d.Id())
+	token := testFakeCreate(t, r, meta, map[string]any{"path": "/vms/100", "role": "PVEVMUser", "token_id": "ci@pve!pipeline"})
+	require.Equal(t, "acl/token/ci@pve!pipeline/PVEVMUser/vms/100", token.Id())
+	require.Equal(t, []string{"/:user:ci@pve:PVEAuditor:true", "/vms/100:token:ci@pve!pipeline:PVEVMUser:true"}, fake.AccessACL())
+
+	d = testFakeUpdate(t, r, meta, d, map[string]any{"path": "/", "role": "PVEAuditor", "user_id": "ci@pve", "propagate": false})
+	require.Equal(t, "/:user:ci@pve:PVEAuditor:false", fake.AccessACL()[0])
+	require.False(t, d.Get("propagate").(bool))
+
+	testFakeDelete(t, r, meta, d)
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "", d.Id())
+	require.Len(t, fake.AccessACL(), 1)
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceACL_Validation(t *testing.T) {
+	r := resourceACL()
+	tests := []struct {
+		name   string
+		config map[string]any
+	}{
+		{name: "no target", config: map[string]any{"path": "/", "role": "PVEAuditor"}},
+		{name: "two targets", config: map[string]any{"path": "/", "role": "PVEAuditor", "user_id": "ci@pve", "group": "devs"}},
+		{name: "relative path", config: map[string]any{"path": "vms/100", "role": "PVEAuditor", "group": "devs"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := terraform.NewResourceConfigRaw(test.config)
+			diags := r.Validate(config)
+			if !diags.HasError() {
+				_, err := r.Diff(context.Background(), nil, config, nil)
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func Test_parseACLResourceId(t *testing.T) {
+	kind, ugid, role, path, err := parseACLResourceId(aclResourceId("group", "devs", "PVEVMUser", "/pool/team-a"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"group", "devs", "PVEVMUser", "/pool/team-a"}, []string{kind, ugid, role, path})
+	_, _, _, _, err = parseACLResourceId("acl/group/devs")
+	require.Error(t, err)
+}
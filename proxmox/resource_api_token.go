@@ -0,0 +1,182 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/util"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const apiTokenResourceType = "api_token"
+
+func resourceApiToken() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceApiTokenCreate,
+		ReadContext:   resourceApiTokenRead,
+		UpdateContext: resourceApiTokenUpdate,
+		DeleteContext: resourceApiTokenDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"user_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateDiagFunc: func(i interface{}, path cty.Path) diag.Diagnostics {
+					var id pveSDK.UserID
+					return diag.FromErr(id.Parse(i.(string)))
+				},
+				Description: "The user that owns the token, in the format `name@realm`.",
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateDiagFunc: func(i interface{}, path cty.Path) diag.Diagnostics {
+					return diag.FromErr(pveSDK.ApiTokenName(i.(string)).Validate())
+				},
+				Description: "The name of the token.",
+			},
+			"privilege_separation": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Limit the token to the permissions granted to it with ACLs, instead of all permissions of the user.",
+			},
+			"expire": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "When the token expires as a unix timestamp, 0 never expires.",
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"token_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the token in the format `name@realm!token`.",
+			},
+			"secret": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The secret of the token, it is only known when the token is created.",
+			},
+		},
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+func apiTokenConfig(d *schema.ResourceData) pveSDK.ApiTokenConfig {
+	return pveSDK.ApiTokenConfig{
+		Name:                pveSDK.ApiTokenName(d.Get("name").(string)),
+		Comment:             util.Pointer(d.Get("comment").(string)),
+		Expiration:          util.Pointer(uint(d.Get("expire").(int))),
+		PrivilegeSeparation: util.Pointer(d.Get("privilege_separation").(bool)),
+	}
+}
+
+func resourceApiTokenCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	var user pveSDK.UserID
+	if err := user.Parse(d.Get("user_id").(string)); err != nil {
+		return diag.FromErr(err)
+	}
+	config := apiTokenConfig(d)
+	secret, err := pconf.NewClient.ApiToken.Create(ctx, user, config)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	id := pveSDK.ApiTokenID{User: user, TokenName: config.Name}
+	d.SetId(clusterResourceId(apiTokenResourceType, id.String()))
+	d.Set("secret", secret.String())
+	return diag.FromErr(_resourceApiTokenRead(ctx, d, pconf.NewClient))
+}
+
+func resourceApiTokenRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return diag.FromErr(_resourceApiTokenRead(ctx, d, pconf.NewClient))
+}
+
+func _resourceApiTokenRead(ctx context.Context, d *schema.ResourceData, client *pveSDK.ClientNew) error {
+	_, rawID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+	var id pveSDK.ApiTokenID
+	if err = id.Parse(rawID); err != nil {
+		d.SetId("")
+		return fmt.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+	exists, err := client.ApiToken.Exists(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		d.SetId("")
+		return nil
+	}
+	raw, err := client.ApiToken.Read(ctx, id)
+	if err != nil {
+		return err
+	}
+	d.Set("user_id", id.User.String())
+	d.Set("name", id.TokenName.String())
+	d.Set("token_id", id.String())
+	d.Set("privilege_separation", raw.GetPrivilegeSeparation())
+	d.Set("expire", int(raw.GetExpiration()))
+	d.Set("comment", raw.GetComment())
+	return nil
+}
+
+func resourceApiTokenUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, rawID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var id pveSDK.ApiTokenID
+	if err = id.Parse(rawID); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = pconf.NewClient.ApiToken.Update(ctx, id.User, apiTokenConfig(d)); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(_resourceApiTokenRead(ctx, d, pconf.NewClient))
+}
+
+func resourceApiTokenDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, rawID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var id pveSDK.ApiTokenID
+	if err = id.Parse(rawID); err != nil {
+		return diag.FromErr(err)
+	}
+	_, err = pconf.NewClient.ApiToken.Delete(ctx, id)
+	return diag.FromErr(err)
+}
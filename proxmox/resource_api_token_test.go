@@ -0,0 +1,34 @@
+package proxmox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResourceApiToken_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	testFakeCreate(t, resourceUser(), meta, map[string]any{"user_id": "ci@pve"})
+	r := resourceApiToken()
+
+	d := testFakeCreate(t, r, meta, map[string]any{"user_id": "ci@pve", "name": "pipeline", "comment": "deploys"})
+	require.Equal(t, "api_token/ci@pve!pipeline", d.Id())
+	require.Equal(t, "ci@pve!pipeline", d.Get("token_id"))
+	require.NotEmpty(t, d.Get("secret"))
+	require.True(t, d.Get("privilege_separation").(bool))
+	secret := d.Get("secret")
+
+	d = testFakeUpdate(t, r, meta, d, map[string]any{"user_id": "ci@pve", "name": "pipeline", "privilege_separation": false, "expire": 1900000000})
+	token, ok := fake.AccessToken("ci@pve!pipeline")
+	require.True(t, ok)
+	require.Equal(t, "0", token["privsep"])
+	require.Equal(t, "1900000000", token["expire"])
+	require.Equal(t, "", token["comment"])
+	require.Equal(t, secret, d.Get("secret"))
+
+	testFakeDelete(t, r, meta, d)
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "", d.Id())
+	testFakeUnknown(t, fake)
+}
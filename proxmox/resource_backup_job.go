@@ -0,0 +1,437 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const backupJobResourceType = "backup"
+
+var backupJobSelection = []string{"vmids", "pool", "all"}
+
+// backupJobRetention maps the keys of the retention block to the keys of the `prune-backups` property.
+var backupJobRetention = map[string]string{
+	"keep_last":    "keep-last",
+	"keep_hourly":  "keep-hourly",
+	"keep_daily":   "keep-daily",
+	"keep_weekly":  "keep-weekly",
+	"keep_monthly": "keep-monthly",
+	"keep_yearly":  "keep-yearly",
+}
+
+// backupJobOptional are the API keys that are removed when they are no longer configured.
+var backupJobOptional = []string{"all", "comment", "exclude", "mailto", "node", "pool", "prune-backups", "vmid"}
+
+// backupRetentionSchema returns the retention block, which is converted to the `prune-backups` property.
+func backupRetentionSchema(description string) *schema.Schema {
+	retention := map[string]*schema.Schema{
+		"keep_all": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Keep all backups, conflicts with the other keep options.",
+		},
+	}
+	for key := range backupJobRetention {
+		retention[key] = &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+		}
+	}
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: description,
+		Elem:        &schema.Resource{Schema: retention},
+	}
+}
+
+// backupRetentionValidate checks that keep_all is not combined with the other keep options of the retention block.
+func backupRetentionValidate(d *schema.ResourceDiff) error {
+	v, ok := d.GetOk("retention")
+	if !ok || v.([]interface{})[0] == nil {
+		return nil
+	}
+	retention := v.([]interface{})[0].(map[string]interface{})
+	if !retention["keep_all"].(bool) {
+		return nil
+	}
+	for key := range backupJobRetention {
+		if retention[key].(int) > 0 {
+			return fmt.Errorf("retention: keep_all conflicts with %s", key)
+		}
+	}
+	return nil
+}
+
+func resourceBackupJob() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceBackupJobCreate,
+		ReadContext:   resourceBackupJobRead,
+		UpdateContext: resourceBackupJobUpdate,
+		DeleteContext: resourceBackupJobDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"job_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The ID of the backup job, generated in the format Proxmox uses when not set.",
+			},
+			"schedule": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "When the job runs, in systemd calendar event format.",
+			},
+			"storage": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"node": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only run the job on this node.",
+			},
+			"mode": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "snapshot",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+					"snapshot",
+					"suspend",
+					"stop",
+				}, false)),
+			},
+			"compress": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "zstd",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+					"0",
+					"1",
+					"gzip",
+					"lz4",
+					"zstd",
+				}, false)),
+			},
+			"vmids": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				Elem:         &schema.Schema{Type: schema.TypeInt},
+				ExactlyOneOf: backupJobSelection,
+				Description:  "The guests to back up.",
+			},
+			"pool": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: backupJobSelection,
+				Description:  "Back up all guests in the pool.",
+			},
+			"all": {
+				Type:         schema.TypeBool,
+				Optional:     true,
+				ExactlyOneOf: backupJobSelection,
+				Description:  "Back up all guests, except the ones in exclude.",
+			},
+			"exclude": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "The guests to skip when all or pool is used.",
+			},
+			"retention": backupRetentionSchema("How many backups are kept, when not set the retention of the storage applies."),
+			"mailto": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"mail_notification": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "always",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+					"always",
+					"failure",
+				}, false)),
+			},
+			"notification_mode": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "auto",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+					"auto",
+					"legacy-sendmail",
+					"notification-system",
+				}, false)),
+			},
+		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if d.Get("exclude").(*schema.Set).Len() > 0 && d.Get("vmids").(*schema.Set).Len() > 0 {
+				return fmt.Errorf("exclude can only be used together with all or pool")
+			}
+			return backupRetentionValidate(d)
+		},
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+// backupJobParams converts the resource to the parameters of the /cluster/backup endpoint.
+// Unset optional keys are returned in the `delete` parameter when update is true.
+func backupJobParams(d *schema.ResourceData, update bool) map[string]interface{} {
+	params := map[string]interface{}{
+		"schedule":          d.Get("schedule").(string),
+		"storage":           d.Get("storage").(string),
+		"enabled":           d.Get("enabled").(bool),
+		"mode":              d.Get("mode").(string),
+		"compress":          d.Get("compress").(string),
+		"mailnotification":  d.Get("mail_notification").(string),
+		"notification-mode": d.Get("notification_mode").(string),
+		"comment":           d.Get("comment").(string),
+		"node":              d.Get("node").(string),
+		"pool":              d.Get("pool").(string),
+		"vmid":              backupJobGuestList(d.Get("vmids").(*schema.Set)),
+		"exclude":           backupJobGuestList(d.Get("exclude").(*schema.Set)),
+	}
+	if d.Get("all").(bool) {
+		params["all"] = true
+	}
+	mailto := make([]string, 0)
+	for _, e := range d.Get("mailto").([]interface{}) {
+		mailto = append(mailto, e.(string))
+	}
+	params["mailto"] = strings.Join(mailto, ",")
+	if v, ok := d.GetOk("retention"); ok && v.([]interface{})[0] != nil {
+		params["prune-backups"] = backupJobPruneBackups(v.([]interface{})[0].(map[string]interface{}))
+	}
+
+	deleteKeys := make([]string, 0)
+	for _, key := range backupJobOptional {
+		if v, ok := params[key]; !ok || v == "" {
+			delete(params, key)
+			deleteKeys = append(deleteKeys, key)
+		}
+	}
+	if update && len(deleteKeys) > 0 {
+		params["delete"] = strings.Join(deleteKeys, ",")
+	}
+	return params
+}
+
+func backupJobGuestList(guests *schema.Set) string {
+	ids := make([]int, 0, guests.Len())
+	for _, e := range guests.List() {
+		ids = append(ids, e.(int))
+	}
+	sort.Ints(ids)
+	list := make([]string, len(ids))
+	for i := range ids {
+		list[i] = strconv.Itoa(ids[i])
+	}
+	return strings.Join(list, ",")
+}
+
+func backupJobPruneBackups(retention map[string]interface{}) string {
+	if retention["keep_all"].(bool) {
+		return "keep-all=1"
+	}
+	keep := make([]string, 0)
+	for key, apiKey := range backupJobRetention {
+		if v := retention[key].(int); v > 0 {
+			keep = append(keep, apiKey+"="+strconv.Itoa(v))
+		}
+	}
+	sort.Strings(keep)
+	return strings.Join(keep, ",")
+}
+
+func resourceBackupJobCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client := pconf.Client
+	params := backupJobParams(d, false)
+	jobID := d.Get("job_id").(string)
+	if jobID == "" {
+		// PVE does not return the ID it generates, so the ID is generated the same way it does.
+		jobID = "backup-" + uuid.New().String()[:13]
+	}
+	params["id"] = jobID
+	if err := client.Post(ctx, params, "/cluster/backup"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(clusterResourceId(backupJobResourceType, jobID))
+	return diag.FromErr(_resourceBackupJobRead(ctx, d, meta))
+}
+
+// backupJobValue returns the value of a key from the API like itemValue, newer versions of PVE return property strings
+// like `prune-backups` as objects, which are joined back into the property string.
+func backupJobValue(job map[string]interface{}, key string) string {
+	v, ok := job[key].(map[string]interface{})
+	if !ok {
+		return itemValue(job, key)
+	}
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	list := make([]string, len(keys))
+	for i, k := range keys {
+		list[i] = k + "=" + fmt.Sprint(v[k])
+	}
+	return strings.Join(list, ",")
+}
+
+func resourceBackupJobRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return diag.FromErr(_resourceBackupJobRead(ctx, d, meta))
+}
+
+func _resourceBackupJobRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	client := pconf.Client
+
+	_, jobID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	jobs, err := client.GetItemListInterfaceArray(ctx, "/cluster/backup")
+	if err != nil {
+		return err
+	}
+	var job map[string]interface{}
+	for _, e := range jobs {
+		if e.(map[string]interface{})["id"] == jobID {
+			job = e.(map[string]interface{})
+			break
+		}
+	}
+	if job == nil {
+		d.SetId("")
+		return nil
+	}
+
+	str := func(key string) string { return backupJobValue(job, key) }
+	d.Set("job_id", jobID)
+	d.Set("schedule", str("schedule"))
+	d.Set("storage", str("storage"))
+	d.Set("enabled", str("enabled") != "0")
+	d.Set("comment", str("comment"))
+	d.Set("node", str("node"))
+	d.Set("pool", str("pool"))
+	d.Set("vmids", backupJobParseGuestList(str("vmid")))
+	d.Set("exclude", backupJobParseGuestList(str("exclude")))
+	if str("all") == "1" {
+		d.Set("all", true)
+	} else if _, ok := d.GetOk("all"); ok {
+		d.Set("all", false)
+	}
+	if v := str("mode"); v != "" {
+		d.Set("mode", v)
+	}
+	if v := str("compress"); v != "" {
+		d.Set("compress", v)
+	}
+	if v := str("mailnotification"); v != "" {
+		d.Set("mail_notification", v)
+	}
+	if v := str("notification-mode"); v != "" {
+		d.Set("notification_mode", v)
+	}
+	mailto := make([]string, 0)
+	for _, e := range strings.FieldsFunc(str("mailto"), func(r rune) bool { return r == ',' || r == ';' || r == ' ' }) {
+		mailto = append(mailto, e)
+	}
+	d.Set("mailto", mailto)
+	d.Set("retention", backupJobParseRetention(str("prune-backups")))
+	return nil
+}
+
+func backupJobParseGuestList(raw string) []int {
+	ids := make([]int, 0)
+	for _, e := range strings.Split(raw, ",") {
+		if id, err := strconv.Atoi(strings.TrimSpace(e)); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func backupJobParseRetention(raw string) []interface{} {
+	if raw == "" {
+		return nil
+	}
+	retention := map[string]interface{}{"keep_all": false}
+	for key := range backupJobRetention {
+		retention[key] = 0
+	}
+	for _, e := range strings.Split(raw, ",") {
+		key, value, _ := strings.Cut(e, "=")
+		if key == "keep-all" {
+			retention["keep_all"] = value == "1"
+			continue
+		}
+		for k, apiKey := range backupJobRetention {
+			if apiKey == key {
+				retention[k], _ = strconv.Atoi(value)
+			}
+		}
+	}
+	return []interface{}{retention}
+}
+
+func resourceBackupJobUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, jobID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err = pconf.Client.Put(ctx, backupJobParams(d, true), "/cluster/backup/"+jobID); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(_resourceBackupJobRead(ctx, d, meta))
+}
+
+func resourceBackupJobDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, jobID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(pconf.Client.Delete(ctx, "/cluster/backup/"+jobID))
+}
@@ -0,0 +1,146 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func testAccExampleBackupJob(schedule string) string {
+	return testAccExampleQemuFake("test-vm", 512) + fmt.Sprintf(`
+resource "proxmox_backup_job" "test" {
+  schedule = "%s"
+  storage  = "local"
+  vmids    = [proxmox_vm_qemu.test.vmid]
+  mailto   = ["admin@example.com"]
+
+  retention {
+    keep_last  = 3
+    keep_daily = 7
+  }
+}
+`, schedule)
+}
+
+func TestAccProxmoxBackupJob_Fake(t *testing.T) {
+	_, provider := testAccFakeProvider(t)
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProxmoxProviderFactory(),
+		Steps: []resource.TestStep{
+			{
+				Config: provider + testAccExampleBackupJob("daily"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_backup_job.test", "schedule", "daily"),
+					resource.TestCheckResourceAttr("proxmox_backup_job.test", "vmids.#", "1"),
+					resource.TestCheckResourceAttr("proxmox_backup_job.test", "retention.0.keep_daily", "7"),
+				),
+			},
+			{
+				Config: provider + testAccExampleBackupJob("sun 02:00"),
+				Check:  resource.TestCheckResourceAttr("proxmox_backup_job.test", "schedule", "sun 02:00"),
+			},
+			{
+				ResourceName:      "proxmox_backup_job.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func Test_ResourceBackupJob_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceBackupJob()
+
+	config := map[string]any{
+		"schedule":  "daily",
+		"storage":   "local",
+		"vmids":     []any{101, 100},
+		"mailto":    []any{"admin@example.com", "ops@example.com"},
+		"retention": []any{map[string]any{"keep_last": 3, "keep_weekly": 2}}}
+	d := testFakeCreate(t, r, meta, config)
+	jobID := d.Get("job_id").(string)
+	require.Regexp(t, `^backup-[0-9a-f]{8}-[0-9a-f]{4}$`, jobID)
+	require.Equal(t, "backup/"+jobID, d.Id())
+	job, ok := fake.BackupJob(jobID)
+	require.True(t, ok)
+	require.Equal(t, "100,101", job["vmid"])
+	require.Equal(t, "keep-last=3,keep-weekly=2", job["prune-backups"])
+	require.Equal(t, "admin@example.com,ops@example.com", job["mailto"])
+	require.Equal(t, "zstd", job["compress"])
+	require.Equal(t, "snapshot", job["mode"])
+
+	// Switching to "all except" removes the guest list and the retention.
+	config = map[string]any{
+		"schedule":          "daily",
+		"storage":           "local",
+		"all":               true,
+		"exclude":           []any{100},
+		"mode":              "stop",
+		"mail_notification": "failure",
+		"enabled":           false}
+	d = testFakeUpdate(t, r, meta, d, config)
+	job, _ = fake.BackupJob(jobID)
+	require.Equal(t, "1", job["all"])
+	require.Equal(t, "100", job["exclude"])
+	require.Equal(t, "0", job["enabled"])
+	require.NotContains(t, job, "vmid")
+	require.NotContains(t, job, "prune-backups")
+	require.NotContains(t, job, "mailto")
+	require.Len(t, d.Get("retention"), 0)
+	require.Equal(t, false, d.Get("enabled"))
+
+	diff, err := r.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(config), meta)
+	require.NoError(t, err)
+	require.True(t, diff == nil || diff.Empty())
+
+	d = testFakeUpdate(t, r, meta, d, map[string]any{"schedule": "daily", "storage": "local", "pool": "backups"})
+	job, _ = fake.BackupJob(jobID)
+	require.Equal(t, "backups", job["pool"])
+	require.NotContains(t, job, "all")
+	require.NotContains(t, job, "exclude")
+
+	testFakeDelete(t, r, meta, d)
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "", d.Id())
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceBackupJob_Validation(t *testing.T) {
+	r := resourceBackupJob()
+	tests := []struct {
+		name   string
+		config map[string]any
+	}{
+		{name: "no selection", config: map[string]any{"schedule": "daily", "storage": "local"}},
+		{name: "vmids and all", config: map[string]any{"schedule": "daily", "storage": "local", "vmids": []any{100}, "all": true}},
+		{name: "exclude with vmids", config: map[string]any{"schedule": "daily", "storage": "local", "vmids": []any{100}, "exclude": []any{101}}},
+		{name: "keep all with keep last", config: map[string]any{"schedule": "daily", "storage": "local", "all": true,
+			"retention": []any{map[string]any{"keep_all": true, "keep_last": 1}}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := terraform.NewResourceConfigRaw(test.config)
+			diags := r.Validate(config)
+			if !diags.HasError() {
+				_, err := r.Diff(context.Background(), nil, config, nil)
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func Test_BackupJobValue(t *testing.T) {
+	job := map[string]interface{}{
+		"enabled":       float64(1),
+		"prune-backups": map[string]interface{}{"keep-weekly": "2", "keep-last": "3"},
+	}
+	require.Equal(t, "1", backupJobValue(job, "enabled"))
+	require.Equal(t, "keep-last=3,keep-weekly=2", backupJobValue(job, "prune-backups"))
+	require.Equal(t, "", backupJobValue(job, "comment"))
+}
@@ -150,13 +150,11 @@ func resourceCloudInitDiskRead(ctx context.Context, d *schema.ResourceData, m in
 	client := pconf.Client
 
 	var isoFound bool
-	storageContent, err := client.GetStorageContent(ctx, d.Get("storage").(string), pveSDK.NodeName(d.Get("pve_node").(string)))
+	volumes, err := storageContent(ctx, client, d.Get("storage").(string), pveSDK.NodeName(d.Get("pve_node").(string)))
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	contents := storageContent["data"].([]interface{})
-	for c := range contents {
-		storageContentMap := contents[c].(map[string]interface{})
+	for _, storageContentMap := range volumes {
 		if storageContentMap["volid"].(string) == d.Id() {
 			size := storageContentMap["size"].(float64)
 			d.Set("size", ByteCountIEC(int64(size)))
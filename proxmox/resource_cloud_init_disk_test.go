@@ -0,0 +1,59 @@
+package proxmox
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func testAccExampleCloudInitDisk(userData string) string {
+	return fmt.Sprintf(`
+resource "proxmox_cloud_init_disk" "test" {
+  name      = "test"
+  pve_node  = "pve"
+  storage   = "local"
+  meta_data = "local-hostname: test"
+  user_data = "%s"
+}
+`, userData)
+}
+
+func TestAccProxmoxCloudInitDisk_Fake(t *testing.T) {
+	_, provider := testAccFakeProvider(t)
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProxmoxProviderFactory(),
+		Steps: []resource.TestStep{
+			{
+				Config: provider + testAccExampleCloudInitDisk("#cloud-config"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_cloud_init_disk.test", "id", "local:iso/tf-ci-test.iso"),
+					resource.TestCheckResourceAttrSet("proxmox_cloud_init_disk.test", "sha256"),
+					resource.TestCheckResourceAttrSet("proxmox_cloud_init_disk.test", "size"),
+				),
+			},
+		},
+	})
+}
+
+func Test_ResourceCloudInitDisk_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceCloudInitDisk()
+
+	d := testFakeCreate(t, r, meta, map[string]any{
+		"name":      "test",
+		"pve_node":  "pve",
+		"storage":   "local",
+		"meta_data": "local-hostname: test",
+		"user_data": "#cloud-config"})
+	require.Equal(t, "local:iso/tf-ci-test.iso", d.Id())
+	require.NotEmpty(t, d.Get("sha256"))
+	require.NotEmpty(t, d.Get("size"))
+
+	testFakeDelete(t, r, meta, d)
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "", d.Id())
+	testFakeUnknown(t, fake)
+}
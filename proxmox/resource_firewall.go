@@ -0,0 +1,259 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var (
+	// rxFirewallName matches the names of aliases, IP sets and security groups.
+	rxFirewallName    = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9\-_]+$`)
+	firewallActions   = []string{"ACCEPT", "DROP", "REJECT"}
+	firewallLogLevels = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug", "nolog"}
+)
+
+// firewallRuleOptions maps the optional arguments of a rule to the API options.
+var firewallRuleOptions = map[string]string{
+	"macro":     "macro",
+	"source":    "source",
+	"dest":      "dest",
+	"proto":     "proto",
+	"sport":     "sport",
+	"dport":     "dport",
+	"iface":     "iface",
+	"icmp_type": "icmp-type",
+	"log":       "log",
+	"comment":   "comment",
+}
+
+func firewallGuestSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+		Description: "The ID of the guest in the format <node>/<type>/<vmid>.",
+	}
+}
+
+// firewallRuleSchema returns the schema of an ordered list of rules, group rules are only allowed for guests.
+func firewallRuleSchema(groups bool) *schema.Schema {
+	types := []string{"in", "out"}
+	if groups {
+		types = append(types, "group")
+	}
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"type": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringInSlice(types, false),
+					Description:  "The direction of the rule, `group` inserts the rules of the security group in `action`.",
+				},
+				"action": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "`ACCEPT`, `DROP` or `REJECT`, or the name of the security group for group rules.",
+				},
+				"enabled": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  true,
+				},
+				"macro": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The predefined rule set of a service, e.g. `SSH`.",
+				},
+				"source": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The source addresses, networks, aliases or IP sets (`+<name>`), comma separated.",
+				},
+				"dest": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The destination addresses, networks, aliases or IP sets (`+<name>`), comma separated.",
+				},
+				"proto": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The IP protocol, e.g. `tcp` or `udp`.",
+				},
+				"sport": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The source ports or port ranges, e.g. `80,8000:8080`.",
+				},
+				"dport": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The destination ports or port ranges, e.g. `80,8000:8080`.",
+				},
+				"iface": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The network interface of the guest the rule applies to, e.g. `net0`.",
+				},
+				"icmp_type": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The ICMP type, only for the `icmp` and `ipv6-icmp` protocols.",
+				},
+				"log": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validation.StringInSlice(firewallLogLevels, false),
+					Description:  "The log level of the packets that match the rule.",
+				},
+				"comment": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+			},
+		},
+		Description: "The rules in the order they are evaluated.",
+	}
+}
+
+// firewallGuestURL returns the URL of the firewall of a guest on the node it is on now, the URL is empty when the guest no longer exists.
+func firewallGuestURL(ctx context.Context, client *pveSDK.Client, guest id.Guest) (string, error) {
+	ok, err := guest.ID.Exists(ctx, client)
+	if err != nil || !ok {
+		return "", err
+	}
+	vmr := pveSDK.NewVmRef(guest.ID)
+	if err = client.CheckVmRef(ctx, vmr); err != nil {
+		return "", err
+	}
+	if vmr.GetVmType().String() != guest.Type {
+		return "", fmt.Errorf("guest '%s' is not of type '%s'", guest.ID.String(), guest.Type)
+	}
+	return "/nodes/" + vmr.Node().String() + "/" + guest.Type + "/" + guest.ID.String() + "/firewall", nil
+}
+
+// firewallCreateURL parses the guest argument of a guest firewall resource and returns the URL of the firewall of the guest.
+func firewallCreateURL(ctx context.Context, client *pveSDK.Client, d *schema.ResourceData) (id.Guest, string, error) {
+	var guestID id.Guest
+	if err := guestID.Parse(d.Get("guest").(string)); err != nil {
+		return guestID, "", err
+	}
+	url, err := firewallGuestURL(ctx, client, guestID)
+	if err == nil && url == "" {
+		err = fmt.Errorf("guest '%s' does not exist", guestID.String())
+	}
+	return guestID, url, err
+}
+
+// firewallResolve parses the ID of a guest firewall resource of kind and returns the URL of the firewall of the guest.
+func firewallResolve(ctx context.Context, client *pveSDK.Client, resourceID, kind string) (id.GuestFirewall, string, error) {
+	var firewallID id.GuestFirewall
+	if err := firewallID.Parse(resourceID); err != nil {
+		return firewallID, "", err
+	}
+	if firewallID.Kind != kind {
+		return firewallID, "", fmt.Errorf("resource '%s' is not of kind '%s'", resourceID, kind)
+	}
+	url, err := firewallGuestURL(ctx, client, firewallID.Guest)
+	return firewallID, url, err
+}
+
+// firewallRuleParams returns the parameters of a rule, unset options are removed on update.
+func firewallRuleParams(rule map[string]interface{}, update bool) map[string]interface{} {
+	params := map[string]interface{}{
+		"type":   rule["type"],
+		"action": rule["action"],
+		"enable": rule["enabled"],
+	}
+	deleteKeys := make([]string, 0)
+	for key, option := range firewallRuleOptions {
+		if v := rule[key].(string); v != "" {
+			params[option] = v
+		} else {
+			deleteKeys = append(deleteKeys, option)
+		}
+	}
+	if update && len(deleteKeys) > 0 {
+		sort.Strings(deleteKeys)
+		params["delete"] = strings.Join(deleteKeys, ",")
+	}
+	return params
+}
+
+// firewallRules returns the rules of a rule list endpoint in the format of the rule argument.
+func firewallRules(ctx context.Context, client *pveSDK.Client, url string) ([]interface{}, error) {
+	list, err := client.GetItemListInterfaceArray(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]map[string]interface{}, 0, len(list))
+	for _, e := range list {
+		if item, ok := e.(map[string]interface{}); ok {
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		pi, _ := strconv.Atoi(itemValue(items[i], "pos"))
+		pj, _ := strconv.Atoi(itemValue(items[j], "pos"))
+		return pi < pj
+	})
+	rules := make([]interface{}, len(items))
+	for i, item := range items {
+		rule := map[string]interface{}{
+			"type":    itemValue(item, "type"),
+			"action":  itemValue(item, "action"),
+			"enabled": itemValue(item, "enable") == "1",
+		}
+		for key, option := range firewallRuleOptions {
+			rule[key] = itemValue(item, option)
+		}
+		rules[i] = rule
+	}
+	return rules, nil
+}
+
+// firewallRulesSet makes the rules of a rule list endpoint match rules, so changes made outside of Terraform are reverted.
+// Surplus rules are removed from the bottom and missing rules are added at the top, which moves the existing rules down
+// into the positions they are compared with. Only the rules that differ are then updated in place.
+func firewallRulesSet(ctx context.Context, client *pveSDK.Client, url string, rules []interface{}) error {
+	current, err := firewallRules(ctx, client, url)
+	if err != nil {
+		return err
+	}
+	for pos := len(current) - 1; pos >= len(rules); pos-- {
+		if err = client.Delete(ctx, url+"/"+strconv.Itoa(pos)); err != nil {
+			return err
+		}
+	}
+	if len(current) > len(rules) {
+		current = current[:len(rules)]
+	}
+	added := len(rules) - len(current)
+	// PVE adds a new rule at the top, so the rules are added in reverse.
+	for i := added - 1; i >= 0; i-- {
+		if err = client.Post(ctx, firewallRuleParams(rules[i].(map[string]interface{}), false), url); err != nil {
+			return err
+		}
+	}
+	for pos := added; pos < len(rules); pos++ {
+		if reflect.DeepEqual(current[pos-added], rules[pos]) {
+			continue
+		}
+		if err = client.Put(ctx, firewallRuleParams(rules[pos].(map[string]interface{}), true), url+"/"+strconv.Itoa(pos)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
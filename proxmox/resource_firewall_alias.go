@@ -0,0 +1,135 @@
+package proxmox
+
+import (
+	"context"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const firewallAliasKind = "aliases"
+
+func resourceFirewallAlias() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceFirewallAliasCreate,
+		ReadContext:   resourceFirewallAliasRead,
+		UpdateContext: resourceFirewallAliasUpdate,
+		DeleteContext: resourceFirewallAliasDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"guest": firewallGuestSchema(),
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(rxFirewallName, "must start with a letter and only contain letters, digits, '-' and '_'"),
+				Description:  "The name of the alias, rules of the guest refer to it in their source and destination.",
+			},
+			"cidr": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.Any(validation.IsIPAddress, validation.IsCIDR),
+				Description:  "The address or network of the alias.",
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+func firewallAliasParams(d *schema.ResourceData) map[string]interface{} {
+	params := map[string]interface{}{"cidr": d.Get("cidr")}
+	if comment := d.Get("comment").(string); comment != "" {
+		params["comment"] = comment
+	}
+	return params
+}
+
+func resourceFirewallAliasCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	guestID, url, err := firewallCreateURL(ctx, pconf.Client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	name := d.Get("name").(string)
+	params := firewallAliasParams(d)
+	params["name"] = name
+	if err = pconf.Client.Post(ctx, params, url+"/aliases"); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(id.GuestFirewall{Guest: guestID, Kind: firewallAliasKind, Name: name}.String())
+	return _resourceFirewallAliasRead(ctx, d, pconf.Client)
+}
+
+func resourceFirewallAliasRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceFirewallAliasRead(ctx, d, pconf.Client)
+}
+
+func _resourceFirewallAliasRead(ctx context.Context, d *schema.ResourceData, client *pveSDK.Client) diag.Diagnostics {
+	firewallID, url, err := firewallResolve(ctx, client, d.Id(), firewallAliasKind)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if url == "" {
+		return diag.Diagnostics{resourceDriftDeletionDiagnostic(d)}
+	}
+	item, err := listItem(ctx, client, url+"/aliases", "name", firewallID.Name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if item == nil {
+		return diag.Diagnostics{resourceDriftDeletionDiagnostic(d)}
+	}
+	if d.Get("guest").(string) == "" {
+		d.Set("guest", firewallID.Guest.String())
+	}
+	d.Set("name", firewallID.Name)
+	d.Set("cidr", itemValue(item, "cidr"))
+	d.Set("comment", itemValue(item, "comment"))
+	return nil
+}
+
+// resourceFirewallAliasUpdate replaces the alias, PVE clears the comment when it is not given.
+func resourceFirewallAliasUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	firewallID, url, err := firewallResolve(ctx, pconf.Client, d.Id(), firewallAliasKind)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if url == "" {
+		return diag.Errorf("the guest of '%s' does not exist", d.Id())
+	}
+	if err = pconf.Client.Put(ctx, firewallAliasParams(d), url+"/aliases/"+firewallID.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	return _resourceFirewallAliasRead(ctx, d, pconf.Client)
+}
+
+func resourceFirewallAliasDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	firewallID, url, err := firewallResolve(ctx, pconf.Client, d.Id(), firewallAliasKind)
+	if err != nil || url == "" { // the alias was removed together with the guest
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(pconf.Client.Delete(ctx, url+"/aliases/"+firewallID.Name))
+}
@@ -0,0 +1,136 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const firewallGroupResourceType = "firewall_group"
+
+func resourceFirewallGroup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceFirewallGroupCreate,
+		ReadContext:   resourceFirewallGroupRead,
+		UpdateContext: resourceFirewallGroupUpdate,
+		DeleteContext: resourceFirewallGroupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.All(
+					validation.StringLenBetween(2, 18),
+					validation.StringMatch(rxFirewallName, "must start with a letter and only contain letters, digits, '-' and '_'"),
+				),
+				Description: "The name of the security group, group rules of the guests refer to it in their action.",
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"rule": firewallRuleSchema(false),
+		},
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+func firewallGroupURL(name string) string {
+	return "/cluster/firewall/groups/" + name
+}
+
+func resourceFirewallGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	name := d.Get("name").(string)
+	params := map[string]interface{}{"group": name}
+	if comment := d.Get("comment").(string); comment != "" {
+		params["comment"] = comment
+	}
+	if err := pconf.Client.Post(ctx, params, "/cluster/firewall/groups"); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(clusterResourceId(firewallGroupResourceType, name))
+	if err := firewallRulesSet(ctx, pconf.Client, firewallGroupURL(name), d.Get("rule").([]interface{})); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(_resourceFirewallGroupRead(ctx, d, pconf.Client))
+}
+
+func resourceFirewallGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return diag.FromErr(_resourceFirewallGroupRead(ctx, d, pconf.Client))
+}
+
+func _resourceFirewallGroupRead(ctx context.Context, d *schema.ResourceData, client *pveSDK.Client) error {
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+	item, err := listItem(ctx, client, "/cluster/firewall/groups", "group", name)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		d.SetId("")
+		return nil
+	}
+	rules, err := firewallRules(ctx, client, firewallGroupURL(name))
+	if err != nil {
+		return err
+	}
+	d.Set("name", name)
+	d.Set("comment", itemValue(item, "comment"))
+	d.Set("rule", rules)
+	return nil
+}
+
+func resourceFirewallGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if d.HasChange("comment") {
+		// The comment of a security group is changed by renaming it to the same name.
+		params := map[string]interface{}{"group": name, "rename": name, "comment": postString(d.Get("comment").(string))}
+		if err = pconf.Client.Post(ctx, params, "/cluster/firewall/groups"); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err = firewallRulesSet(ctx, pconf.Client, firewallGroupURL(name), d.Get("rule").([]interface{})); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(_resourceFirewallGroupRead(ctx, d, pconf.Client))
+}
+
+// resourceFirewallGroupDelete removes the rules first, as PVE only deletes empty security groups.
+func resourceFirewallGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err = firewallRulesSet(ctx, pconf.Client, firewallGroupURL(name), nil); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(pconf.Client.Delete(ctx, firewallGroupURL(name)))
+}
@@ -0,0 +1,229 @@
+package proxmox
+
+import (
+	"context"
+	"net/url"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const firewallIPSetKind = "ipset"
+
+func resourceFirewallIPSet() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceFirewallIPSetCreate,
+		ReadContext:   resourceFirewallIPSetRead,
+		UpdateContext: resourceFirewallIPSetUpdate,
+		DeleteContext: resourceFirewallIPSetDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"guest": firewallGuestSchema(),
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(rxFirewallName, "must start with a letter and only contain letters, digits, '-' and '_'"),
+				Description:  "The name of the IP set, rules of the guest refer to it as `+<name>`. The `ipfilter-net<n>` IP sets hold the addresses the guest may use on a network interface.",
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"entry": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cidr": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.Any(validation.IsIPAddress, validation.IsCIDR),
+							Description:  "The address or network.",
+						},
+						"nomatch": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Exclude the address or network from the IP set.",
+						},
+						"comment": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+				Description: "The addresses and networks of the IP set.",
+			},
+		},
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+// firewallIPSetEntryParams returns the parameters of an entry, the comment is cleared on update when it is not given.
+func firewallIPSetEntryParams(entry map[string]interface{}) map[string]interface{} {
+	params := map[string]interface{}{"nomatch": entry["nomatch"]}
+	if comment := entry["comment"].(string); comment != "" {
+		params["comment"] = comment
+	}
+	return params
+}
+
+// firewallIPSetEntries returns the entries of an IP set by their CIDR.
+func firewallIPSetEntries(ctx context.Context, client *pveSDK.Client, setURL string) (map[string]map[string]interface{}, error) {
+	list, err := client.GetItemListInterfaceArray(ctx, setURL)
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[string]map[string]interface{}, len(list))
+	for _, e := range list {
+		if item, ok := e.(map[string]interface{}); ok {
+			entries[itemValue(item, "cidr")] = map[string]interface{}{
+				"cidr":    itemValue(item, "cidr"),
+				"nomatch": itemValue(item, "nomatch") == "1",
+				"comment": itemValue(item, "comment"),
+			}
+		}
+	}
+	return entries, nil
+}
+
+// firewallIPSetSet makes the entries of an IP set match the entry argument.
+func firewallIPSetSet(ctx context.Context, client *pveSDK.Client, d *schema.ResourceData, setURL string) error {
+	current, err := firewallIPSetEntries(ctx, client, setURL)
+	if err != nil {
+		return err
+	}
+	wanted := map[string]map[string]interface{}{}
+	for _, e := range d.Get("entry").(*schema.Set).List() {
+		entry := e.(map[string]interface{})
+		wanted[entry["cidr"].(string)] = entry
+	}
+	for cidr := range current {
+		if _, ok := wanted[cidr]; !ok {
+			if err = client.Delete(ctx, setURL+"/"+url.PathEscape(cidr)); err != nil {
+				return err
+			}
+		}
+	}
+	for cidr, entry := range wanted {
+		existing, ok := current[cidr]
+		switch {
+		case !ok:
+			params := firewallIPSetEntryParams(entry)
+			params["cidr"] = cidr
+			err = client.Post(ctx, params, setURL)
+		case existing["nomatch"] != entry["nomatch"] || existing["comment"] != entry["comment"]:
+			err = client.Put(ctx, firewallIPSetEntryParams(entry), setURL+"/"+url.PathEscape(cidr))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resourceFirewallIPSetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	guestID, firewallURL, err := firewallCreateURL(ctx, pconf.Client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	name := d.Get("name").(string)
+	params := map[string]interface{}{"name": name}
+	if comment := d.Get("comment").(string); comment != "" {
+		params["comment"] = comment
+	}
+	if err = pconf.Client.Post(ctx, params, firewallURL+"/ipset"); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(id.GuestFirewall{Guest: guestID, Kind: firewallIPSetKind, Name: name}.String())
+	if err = firewallIPSetSet(ctx, pconf.Client, d, firewallURL+"/ipset/"+name); err != nil {
+		return diag.FromErr(err)
+	}
+	return _resourceFirewallIPSetRead(ctx, d, pconf.Client)
+}
+
+func resourceFirewallIPSetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceFirewallIPSetRead(ctx, d, pconf.Client)
+}
+
+func _resourceFirewallIPSetRead(ctx context.Context, d *schema.ResourceData, client *pveSDK.Client) diag.Diagnostics {
+	firewallID, firewallURL, err := firewallResolve(ctx, client, d.Id(), firewallIPSetKind)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if firewallURL == "" {
+		return diag.Diagnostics{resourceDriftDeletionDiagnostic(d)}
+	}
+	item, err := listItem(ctx, client, firewallURL+"/ipset", "name", firewallID.Name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if item == nil {
+		return diag.Diagnostics{resourceDriftDeletionDiagnostic(d)}
+	}
+	entries, err := firewallIPSetEntries(ctx, client, firewallURL+"/ipset/"+firewallID.Name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	list := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, entry)
+	}
+	if d.Get("guest").(string) == "" {
+		d.Set("guest", firewallID.Guest.String())
+	}
+	d.Set("name", firewallID.Name)
+	d.Set("comment", itemValue(item, "comment"))
+	d.Set("entry", list)
+	return nil
+}
+
+func resourceFirewallIPSetUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	firewallID, firewallURL, err := firewallResolve(ctx, pconf.Client, d.Id(), firewallIPSetKind)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if firewallURL == "" {
+		return diag.Errorf("the guest of '%s' does not exist", d.Id())
+	}
+	if d.HasChange("comment") {
+		// The comment of an IP set is changed by renaming it to the same name.
+		params := map[string]interface{}{"name": firewallID.Name, "rename": firewallID.Name, "comment": postString(d.Get("comment").(string))}
+		if err = pconf.Client.Post(ctx, params, firewallURL+"/ipset"); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err = firewallIPSetSet(ctx, pconf.Client, d, firewallURL+"/ipset/"+firewallID.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	return _resourceFirewallIPSetRead(ctx, d, pconf.Client)
+}
+
+func resourceFirewallIPSetDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	firewallID, firewallURL, err := firewallResolve(ctx, pconf.Client, d.Id(), firewallIPSetKind)
+	if err != nil || firewallURL == "" { // the IP set was removed together with the guest
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(pconf.Client.Delete(ctx, firewallURL+"/ipset/"+firewallID.Name+"?force=1"))
+}
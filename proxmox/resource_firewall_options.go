@@ -0,0 +1,196 @@
+package proxmox
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const firewallOptionsKind = "options"
+
+var firewallOptions = map[string]string{
+	"enabled":       "enable",
+	"dhcp":          "dhcp",
+	"ipfilter":      "ipfilter",
+	"macfilter":     "macfilter",
+	"ndp":           "ndp",
+	"radv":          "radv",
+	"policy_in":     "policy_in",
+	"policy_out":    "policy_out",
+	"log_level_in":  "log_level_in",
+	"log_level_out": "log_level_out",
+}
+
+func resourceFirewallOptions() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceFirewallOptionsCreate,
+		ReadContext:   resourceFirewallOptionsRead,
+		UpdateContext: resourceFirewallOptionsUpdate,
+		DeleteContext: resourceFirewallOptionsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema:   firewallOptionsSchema(),
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+func firewallOptionsSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"guest": firewallGuestSchema(),
+		"enabled": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Enable the firewall of the guest, the `firewall` argument of a network interface enables it for that interface.",
+		},
+		"dhcp": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Allow DHCP.",
+		},
+		"ipfilter": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Only allow the addresses of the `ipfilter-net<n>` IP sets, or the link local and configured addresses when they do not exist.",
+		},
+		"macfilter": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Only allow the MAC addresses of the network interfaces.",
+		},
+		"ndp": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Allow the IPv6 neighbor discovery protocol.",
+		},
+		"radv": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Allow the guest to send IPv6 router advertisements.",
+		},
+		"policy_in": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "DROP",
+			ValidateFunc: validation.StringInSlice(firewallActions, false),
+			Description:  "The action of the incoming packets that match no rule.",
+		},
+		"policy_out": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "ACCEPT",
+			ValidateFunc: validation.StringInSlice(firewallActions, false),
+			Description:  "The action of the outgoing packets that match no rule.",
+		},
+		"log_level_in": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "nolog",
+			ValidateFunc: validation.StringInSlice(firewallLogLevels, false),
+			Description:  "The log level of the incoming packets that match no rule.",
+		},
+		"log_level_out": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "nolog",
+			ValidateFunc: validation.StringInSlice(firewallLogLevels, false),
+			Description:  "The log level of the outgoing packets that match no rule.",
+		},
+	}
+}
+
+func firewallOptionsParams(d *schema.ResourceData, update bool) map[string]interface{} {
+	params := map[string]interface{}{}
+	optionParams(d, params, firewallOptions, firewallOptionsSchema(), update)
+	return params
+}
+
+func resourceFirewallOptionsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	guestID, url, err := firewallCreateURL(ctx, pconf.Client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err = pconf.Client.Put(ctx, firewallOptionsParams(d, false), url+"/options"); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(id.GuestFirewall{Guest: guestID, Kind: firewallOptionsKind}.String())
+	return _resourceFirewallOptionsRead(ctx, d, pconf.Client)
+}
+
+func resourceFirewallOptionsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceFirewallOptionsRead(ctx, d, pconf.Client)
+}
+
+func _resourceFirewallOptionsRead(ctx context.Context, d *schema.ResourceData, client *pveSDK.Client) diag.Diagnostics {
+	firewallID, url, err := firewallResolve(ctx, client, d.Id(), firewallOptionsKind)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if url == "" {
+		return diag.Diagnostics{resourceDriftDeletionDiagnostic(d)}
+	}
+	item, err := client.GetItemConfigMapStringInterface(ctx, url+"/options", "firewall", "options")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if d.Get("guest").(string) == "" {
+		d.Set("guest", firewallID.Guest.String())
+	}
+	optionRead(d, item, firewallOptions, firewallOptionsSchema())
+	return nil
+}
+
+func resourceFirewallOptionsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, url, err := firewallResolve(ctx, pconf.Client, d.Id(), firewallOptionsKind)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if url == "" {
+		return diag.Errorf("the guest of '%s' does not exist", d.Id())
+	}
+	if err = pconf.Client.Put(ctx, firewallOptionsParams(d, true), url+"/options"); err != nil {
+		return diag.FromErr(err)
+	}
+	return _resourceFirewallOptionsRead(ctx, d, pconf.Client)
+}
+
+// resourceFirewallOptionsDelete resets the options to the defaults of PVE.
+func resourceFirewallOptionsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, url, err := firewallResolve(ctx, pconf.Client, d.Id(), firewallOptionsKind)
+	if err != nil || url == "" { // the options were removed together with the guest
+		return diag.FromErr(err)
+	}
+	keys := make([]string, 0, len(firewallOptions))
+	for _, option := range firewallOptions {
+		keys = append(keys, option)
+	}
+	sort.Strings(keys)
+	return diag.FromErr(pconf.Client.Put(ctx, map[string]interface{}{"delete": strings.Join(keys, ",")}, url+"/options"))
+}
@@ -0,0 +1,102 @@
+package proxmox
+
+import (
+	"context"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const firewallRulesKind = "rules"
+
+// resourceFirewallRules manages all rules of a guest, rules that are not configured are removed.
+func resourceFirewallRules() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceFirewallRulesCreate,
+		ReadContext:   resourceFirewallRulesRead,
+		UpdateContext: resourceFirewallRulesUpdate,
+		DeleteContext: resourceFirewallRulesDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"guest": firewallGuestSchema(),
+			"rule":  firewallRuleSchema(true),
+		},
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+func resourceFirewallRulesCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	guestID, url, err := firewallCreateURL(ctx, pconf.Client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err = firewallRulesSet(ctx, pconf.Client, url+"/rules", d.Get("rule").([]interface{})); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(id.GuestFirewall{Guest: guestID, Kind: firewallRulesKind}.String())
+	return _resourceFirewallRulesRead(ctx, d, pconf.Client)
+}
+
+func resourceFirewallRulesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceFirewallRulesRead(ctx, d, pconf.Client)
+}
+
+func _resourceFirewallRulesRead(ctx context.Context, d *schema.ResourceData, client *pveSDK.Client) diag.Diagnostics {
+	firewallID, url, err := firewallResolve(ctx, client, d.Id(), firewallRulesKind)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if url == "" {
+		return diag.Diagnostics{resourceDriftDeletionDiagnostic(d)}
+	}
+	rules, err := firewallRules(ctx, client, url+"/rules")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if d.Get("guest").(string) == "" {
+		d.Set("guest", firewallID.Guest.String())
+	}
+	d.Set("rule", rules)
+	return nil
+}
+
+func resourceFirewallRulesUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, url, err := firewallResolve(ctx, pconf.Client, d.Id(), firewallRulesKind)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if url == "" {
+		return diag.Errorf("the guest of '%s' does not exist", d.Id())
+	}
+	if err = firewallRulesSet(ctx, pconf.Client, url+"/rules", d.Get("rule").([]interface{})); err != nil {
+		return diag.FromErr(err)
+	}
+	return _resourceFirewallRulesRead(ctx, d, pconf.Client)
+}
+
+func resourceFirewallRulesDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, url, err := firewallResolve(ctx, pconf.Client, d.Id(), firewallRulesKind)
+	if err != nil || url == "" { // the rules were removed together with the guest
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(firewallRulesSet(ctx, pconf.Client, url+"/rules", nil))
+}
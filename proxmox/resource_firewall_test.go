@@ -0,0 +1,230 @@
+package proxmox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResourceFirewallOptions_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	fake.AddGuest("pve", "qemu", 100, map[string]string{"name": "web"})
+	meta := testFakeMeta(t, fake)
+	r := resourceFirewallOptions()
+
+	config := map[string]any{"guest": "pve/qemu/100", "enabled": true, "policy_in": "REJECT", "log_level_in": "info"}
+	d := testFakeCreate(t, r, meta, config)
+	require.Equal(t, "pve/qemu/100/firewall/options", d.Id())
+	options := fake.FirewallOptions(100)
+	require.Equal(t, "1", options["enable"])
+	require.Equal(t, "REJECT", options["policy_in"])
+	require.Equal(t, "1", options["macfilter"])
+	require.Equal(t, "ACCEPT", d.Get("policy_out"))
+
+	config["dhcp"] = true
+	delete(config, "log_level_in")
+	d = testFakeUpdate(t, r, meta, d, config)
+	options = fake.FirewallOptions(100)
+	require.Equal(t, "1", options["dhcp"])
+	require.Equal(t, "nolog", options["log_level_in"])
+
+	// Rules and options of another kind are not read as options.
+	d.SetId("pve/qemu/100/firewall/rules")
+	require.True(t, r.ReadContext(context.Background(), d, meta).HasError())
+	d.SetId("pve/qemu/100/firewall/options")
+
+	testFakeDelete(t, r, meta, d)
+	require.Empty(t, fake.FirewallOptions(100))
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceFirewallRules_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	fake.AddGuest("pve", "lxc", 101, map[string]string{"hostname": "db"})
+	meta := testFakeMeta(t, fake)
+	r := resourceFirewallRules()
+
+	config := map[string]any{"guest": "pve/lxc/101", "rule": []any{
+		map[string]any{"type": "in", "action": "ACCEPT", "macro": "SSH", "source": "10.0.0.0/24"},
+		map[string]any{"type": "in", "action": "ACCEPT", "proto": "tcp", "dport": "5432", "iface": "net0"},
+		map[string]any{"type": "in", "action": "DROP", "log": "info", "enabled": false},
+	}}
+	d := testFakeCreate(t, r, meta, config)
+	require.Equal(t, "pve/lxc/101/firewall/rules", d.Id())
+	rules := fake.FirewallRules(101)
+	require.Len(t, rules, 3)
+	require.Equal(t, "SSH", rules[0]["macro"])
+	require.Equal(t, "5432", rules[1]["dport"])
+	require.Equal(t, "DROP", rules[2]["action"])
+	require.Equal(t, "0", rules[2]["enable"])
+
+	// Rules changed, moved and added outside of Terraform are reverted.
+	client := meta.Client
+	require.NoError(t, client.Put(context.Background(), map[string]any{"moveto": 0}, "/nodes/pve/lxc/101/firewall/rules/2"))
+	require.NoError(t, client.Put(context.Background(), map[string]any{"dport": "5433"}, "/nodes/pve/lxc/101/firewall/rules/2"))
+	require.NoError(t, client.Post(context.Background(), map[string]any{"type": "out", "action": "ACCEPT", "enable": 1}, "/nodes/pve/lxc/101/firewall/rules"))
+	testFakeRead(t, r, meta, d)
+	require.Len(t, d.Get("rule").([]any), 4)
+	d = testFakeUpdate(t, r, meta, d, config)
+	rules = fake.FirewallRules(101)
+	require.Len(t, rules, 3)
+	require.Equal(t, "SSH", rules[0]["macro"])
+	require.Equal(t, "5432", rules[1]["dport"])
+	require.Equal(t, "DROP", rules[2]["action"])
+	require.NotContains(t, rules[2], "dport")
+
+	// Removing a rule in the middle moves the rules below it up.
+	config["rule"] = []any{
+		map[string]any{"type": "in", "action": "ACCEPT", "macro": "SSH", "source": "10.0.0.0/24"},
+		map[string]any{"type": "in", "action": "DROP", "log": "info", "enabled": false},
+	}
+	d = testFakeUpdate(t, r, meta, d, config)
+	rules = fake.FirewallRules(101)
+	require.Len(t, rules, 2)
+	require.Equal(t, "DROP", rules[1]["action"])
+	require.NotContains(t, rules[1], "proto")
+
+	testFakeDelete(t, r, meta, d)
+	require.Empty(t, fake.FirewallRules(101))
+
+	// The rules are removed together with the guest.
+	d = testFakeCreate(t, r, meta, config)
+	require.NoError(t, client.Delete(context.Background(), "/nodes/pve/lxc/101"))
+	diags := r.ReadContext(context.Background(), d, meta)
+	require.False(t, diags.HasError())
+	require.Equal(t, "", d.Id())
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceFirewallAliasIPSet_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	fake.AddGuest("pve", "qemu", 100, map[string]string{"name": "web"})
+	meta := testFakeMeta(t, fake)
+
+	alias := resourceFirewallAlias()
+	config := map[string]any{"guest": "pve/qemu/100", "name": "office", "cidr": "192.168.10.0/24", "comment": "main office"}
+	d := testFakeCreate(t, alias, meta, config)
+	require.Equal(t, "pve/qemu/100/firewall/aliases/office", d.Id())
+	config["cidr"] = "192.168.20.0/24"
+	delete(config, "comment")
+	d = testFakeUpdate(t, alias, meta, d, config)
+	a, ok := fake.FirewallAlias(100, "office")
+	require.True(t, ok)
+	require.Equal(t, "192.168.20.0/24", a["cidr"])
+	require.NotContains(t, a, "comment")
+	testFakeDelete(t, alias, meta, d)
+	_, ok = fake.FirewallAlias(100, "office")
+	require.False(t, ok)
+
+	ipset := resourceFirewallIPSet()
+	config = map[string]any{"guest": "pve/qemu/100", "name": "ipfilter-net0", "entry": []any{
+		map[string]any{"cidr": "10.0.0.10"},
+		map[string]any{"cidr": "10.0.1.0/24", "comment": "tenant"},
+	}}
+	d = testFakeCreate(t, ipset, meta, config)
+	require.Equal(t, "pve/qemu/100/firewall/ipset/ipfilter-net0", d.Id())
+	_, entries, ok := fake.FirewallIPSet(100, "ipfilter-net0")
+	require.True(t, ok)
+	require.Len(t, entries, 2)
+
+	config["comment"] = "allowed addresses"
+	config["entry"] = []any{
+		map[string]any{"cidr": "10.0.1.0/24", "comment": "tenant", "nomatch": true},
+		map[string]any{"cidr": "10.0.2.0/24"},
+	}
+	d = testFakeUpdate(t, ipset, meta, d, config)
+	comment, entries, _ := fake.FirewallIPSet(100, "ipfilter-net0")
+	require.Equal(t, "allowed addresses", comment)
+	require.Len(t, entries, 2)
+	require.Equal(t, "10.0.1.0/24", entries[0]["cidr"])
+	require.Equal(t, "1", entries[0]["nomatch"])
+	require.Equal(t, "10.0.2.0/24", entries[1]["cidr"])
+	require.Equal(t, 2, d.Get("entry").(*schema.Set).Len())
+
+	delete(config, "comment")
+	d = testFakeUpdate(t, ipset, meta, d, config)
+	comment, _, _ = fake.FirewallIPSet(100, "ipfilter-net0")
+	require.Equal(t, "", comment)
+
+	testFakeDelete(t, ipset, meta, d)
+	_, _, ok = fake.FirewallIPSet(100, "ipfilter-net0")
+	require.False(t, ok)
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceFirewallGroup_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	fake.AddGuest("pve", "qemu", 100, map[string]string{"name": "web"})
+	meta := testFakeMeta(t, fake)
+	group := resourceFirewallGroup()
+	rules := resourceFirewallRules()
+
+	config := map[string]any{"name": "webserver", "comment": "web", "rule": []any{
+		map[string]any{"type": "in", "action": "ACCEPT", "macro": "HTTP"},
+		map[string]any{"type": "in", "action": "ACCEPT", "macro": "HTTPS"},
+	}}
+	g := testFakeCreate(t, group, meta, config)
+	require.Equal(t, "firewall_group/webserver", g.Id())
+	comment, groupRules, ok := fake.FirewallGroup("webserver")
+	require.True(t, ok)
+	require.Equal(t, "web", comment)
+	require.Len(t, groupRules, 2)
+
+	// Security groups cannot contain group rules.
+	d := schema.TestResourceDataRaw(t, group.Schema, map[string]any{"name": "nested", "rule": []any{
+		map[string]any{"type": "in", "action": "webserver"}}})
+	require.True(t, group.CreateContext(context.Background(), d, meta).HasError())
+
+	guestRules := testFakeCreate(t, rules, meta, map[string]any{"guest": "pve/qemu/100", "rule": []any{
+		map[string]any{"type": "group", "action": "webserver", "iface": "net0"}}})
+	require.Equal(t, "webserver", fake.FirewallRules(100)[0]["action"])
+
+	config["comment"] = ""
+	config["rule"] = []any{map[string]any{"type": "in", "action": "ACCEPT", "macro": "HTTPS"}}
+	g = testFakeUpdate(t, group, meta, g, config)
+	comment, groupRules, _ = fake.FirewallGroup("webserver")
+	require.Equal(t, "", comment)
+	require.Len(t, groupRules, 1)
+	require.Equal(t, "HTTPS", groupRules[0]["macro"])
+
+	testFakeDelete(t, rules, meta, guestRules)
+	testFakeDelete(t, group, meta, g)
+	_, _, ok = fake.FirewallGroup("webserver")
+	require.False(t, ok)
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceFirewall_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource *schema.Resource
+		config   map[string]any
+	}{
+		{name: "options policy", resource: resourceFirewallOptions(), config: map[string]any{"guest": "pve/qemu/100", "policy_in": "ALLOW"}},
+		{name: "options log level", resource: resourceFirewallOptions(), config: map[string]any{"guest": "pve/qemu/100", "log_level_out": "verbose"}},
+		{name: "rule type", resource: resourceFirewallRules(), config: map[string]any{"guest": "pve/qemu/100",
+			"rule": []any{map[string]any{"type": "forward", "action": "ACCEPT"}}}},
+		{name: "rule without action", resource: resourceFirewallRules(), config: map[string]any{"guest": "pve/qemu/100",
+			"rule": []any{map[string]any{"type": "in"}}}},
+		{name: "group rule in group", resource: resourceFirewallGroup(), config: map[string]any{"name": "web",
+			"rule": []any{map[string]any{"type": "group", "action": "other"}}}},
+		{name: "group name too long", resource: resourceFirewallGroup(), config: map[string]any{"name": "a-very-long-group-name"}},
+		{name: "alias name", resource: resourceFirewallAlias(), config: map[string]any{"guest": "pve/qemu/100", "name": "1office", "cidr": "10.0.0.0/8"}},
+		{name: "alias cidr", resource: resourceFirewallAlias(), config: map[string]any{"guest": "pve/qemu/100", "name": "office", "cidr": "office"}},
+		{name: "ipset entry", resource: resourceFirewallIPSet(), config: map[string]any{"guest": "pve/qemu/100", "name": "office",
+			"entry": []any{map[string]any{"cidr": "10.0.0.0/33"}}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := terraform.NewResourceConfigRaw(test.config)
+			diags := test.resource.Validate(config)
+			if !diags.HasError() {
+				_, err := test.resource.Diff(context.Background(), nil, config, nil)
+				require.Error(t, err)
+			}
+		})
+	}
+}
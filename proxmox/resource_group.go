@@ -0,0 +1,138 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/util"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const groupResourceType = "group"
+
+func resourceGroup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceGroupCreate,
+		ReadContext:   resourceGroupRead,
+		UpdateContext: resourceGroupUpdate,
+		DeleteContext: resourceGroupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateDiagFunc: func(i interface{}, path cty.Path) diag.Diagnostics {
+					return diag.FromErr(pveSDK.GroupName(i.(string)).Validate())
+				},
+				Description: "The name of the group.",
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"members": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The users in the group, membership is managed with the `groups` of `proxmox_user`.",
+			},
+		},
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+func resourceGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	name := pveSDK.GroupName(d.Get("name").(string))
+	err := pconf.NewClient.Group.Create(ctx, pveSDK.ConfigGroup{
+		Name:    name,
+		Comment: util.Pointer(d.Get("comment").(string)),
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(clusterResourceId(groupResourceType, name.String()))
+	return diag.FromErr(_resourceGroupRead(ctx, d, pconf.NewClient))
+}
+
+func resourceGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return diag.FromErr(_resourceGroupRead(ctx, d, pconf.NewClient))
+}
+
+func _resourceGroupRead(ctx context.Context, d *schema.ResourceData, client *pveSDK.ClientNew) error {
+	_, rawName, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+	name := pveSDK.GroupName(rawName)
+	exists, err := client.Group.Exists(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		d.SetId("")
+		return nil
+	}
+	raw, err := client.Group.Read(ctx, name)
+	if err != nil {
+		return err
+	}
+	members := make([]string, 0)
+	for _, e := range raw.GetMembers() {
+		members = append(members, e.String())
+	}
+	sort.Strings(members)
+	d.Set("name", name.String())
+	d.Set("comment", raw.GetComment())
+	d.Set("members", members)
+	return nil
+}
+
+func resourceGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if d.HasChange("comment") {
+		err = pconf.NewClient.Group.Update(ctx, pveSDK.ConfigGroup{
+			Name:    pveSDK.GroupName(name),
+			Comment: util.Pointer(d.Get("comment").(string)),
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return diag.FromErr(_resourceGroupRead(ctx, d, pconf.NewClient))
+}
+
+func resourceGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	_, err = pconf.NewClient.Group.Delete(ctx, pveSDK.GroupName(name))
+	return diag.FromErr(err)
+}
@@ -0,0 +1,32 @@
+package proxmox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResourceGroup_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceGroup()
+
+	d := testFakeCreate(t, r, meta, map[string]any{"name": "devs", "comment": "first"})
+	require.Equal(t, "group/devs", d.Id())
+	require.Empty(t, d.Get("members"))
+
+	testFakeCreate(t, resourceUser(), meta, map[string]any{"user_id": "bob@pve", "groups": []any{"devs"}})
+	d = testFakeUpdate(t, r, meta, d, map[string]any{"name": "devs", "comment": "second"})
+	comment, members, ok := fake.AccessGroup("devs")
+	require.True(t, ok)
+	require.Equal(t, "second", comment)
+	require.Equal(t, []string{"bob@pve"}, members)
+	require.Equal(t, []any{"bob@pve"}, d.Get("members"))
+
+	testFakeDelete(t, r, meta, d)
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "", d.Id())
+	user, _ := fake.AccessUser("bob@pve")
+	require.Equal(t, "", user["groups"])
+	testFakeUnknown(t, fake)
+}
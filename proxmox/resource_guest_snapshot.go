@@ -0,0 +1,258 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceVmQemuSnapshot() *schema.Resource {
+	return resourceGuestSnapshot(id.GuestQemu)
+}
+
+func resourceLxcSnapshot() *schema.Resource {
+	return resourceGuestSnapshot(id.GuestLxc)
+}
+
+// resourceGuestSnapshot returns the snapshot resource for the given guest type, qemu and lxc only differ in the RAM state.
+func resourceGuestSnapshot(guestType string) *schema.Resource {
+	r := &schema.Resource{
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+			return resourceGuestSnapshotCreate(ctx, d, meta, guestType)
+		},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+			return resourceGuestSnapshotReadWithLock(ctx, d, meta, guestType)
+		},
+		UpdateContext: func(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+			return resourceGuestSnapshotUpdate(ctx, d, meta, guestType)
+		},
+		DeleteContext: resourceGuestSnapshotDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"guest": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the guest in the format <node>/<type>/<vmid>.",
+			},
+			"name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: SnapshotNameValidator(),
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"rollback": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Changing this value to a new non empty value rolls the guest back to the snapshot.",
+			},
+			"rollback_start": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Start the guest after a rollback.",
+			},
+			"parent": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"time": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		Timeouts: resourceTimeouts(),
+	}
+	if guestType == id.GuestQemu {
+		r.Schema["vmstate"] = &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			ForceNew:    true,
+			Default:     false,
+			Description: "Include the RAM of the running VM in the snapshot.",
+		}
+	}
+	return r
+}
+
+// guestSnapshotParseGuest parses the guest the snapshot belongs to and checks it is of the expected type.
+func guestSnapshotParseGuest(rawID, guestType string) (id.Guest, error) {
+	var guestID id.Guest
+	if err := guestID.Parse(rawID); err != nil {
+		return guestID, err
+	}
+	if guestID.Type != guestType {
+		return guestID, fmt.Errorf("guest '%s' is not of type '%s'", rawID, guestType)
+	}
+	return guestID, nil
+}
+
+func resourceGuestSnapshotCreate(ctx context.Context, d *schema.ResourceData, meta any, guestType string) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	guestID, err := guestSnapshotParseGuest(d.Get("guest").(string), guestType)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	client := pconf.Client
+	vmr := pveSDK.NewVmRef(guestID.ID)
+	if err = client.CheckVmRef(ctx, vmr); err != nil {
+		return diag.FromErr(err)
+	}
+
+	name := pveSDK.SnapshotName(d.Get("name").(string))
+	description := d.Get("description").(string)
+	snapshot := pconf.NewClient.Snapshot
+	if guestType == id.GuestLxc {
+		err = snapshot.CreateLxc(ctx, *vmr, name, description)
+	} else {
+		vmState := d.Get("vmstate").(bool)
+		if vmState {
+			// PVE silently skips the RAM of a stopped VM, which would replace the snapshot on every apply.
+			state, err := client.GetVmState(ctx, vmr)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			if state["status"] != "running" {
+				return diag.Errorf("vmstate requires the guest %s to be running", guestID.String())
+			}
+		}
+		err = snapshot.CreateQemu(ctx, *vmr, name, description, vmState)
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(id.Snapshot{
+		Guest: id.Guest{
+			ID:   vmr.VmId(),
+			Node: vmr.Node(),
+			Type: guestType},
+		Name: name}.String())
+	return resourceGuestSnapshotRead(ctx, d, meta, guestType)
+}
+
+func resourceGuestSnapshotUpdate(ctx context.Context, d *schema.ResourceData, meta any, guestType string) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	var resourceID id.Snapshot
+	if err := resourceID.Parse(d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+	vmr := pveSDK.NewVmRef(resourceID.Guest.ID)
+	if err := pconf.Client.CheckVmRef(ctx, vmr); err != nil {
+		return diag.FromErr(err)
+	}
+
+	snapshot := pconf.NewClient.Snapshot
+	if d.HasChange("description") {
+		if err := snapshot.Update(ctx, *vmr, resourceID.Name, d.Get("description").(string)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if d.HasChange("rollback") && d.Get("rollback").(string) != "" {
+		if err := snapshot.Rollback(ctx, *vmr, resourceID.Name, d.Get("rollback_start").(bool)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return resourceGuestSnapshotRead(ctx, d, meta, guestType)
+}
+
+func resourceGuestSnapshotReadWithLock(ctx context.Context, d *schema.ResourceData, meta any, guestType string) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return resourceGuestSnapshotRead(ctx, d, meta, guestType)
+}
+
+func resourceGuestSnapshotRead(ctx context.Context, d *schema.ResourceData, meta any, guestType string) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	client := pconf.Client
+
+	var resourceID id.Snapshot
+	if err := resourceID.Parse(d.Id()); err != nil {
+		d.SetId("")
+		return diag.Diagnostics{{
+			Summary:  "unexpected error when trying to read and parse the resource: " + err.Error(),
+			Severity: diag.Error}}
+	}
+	if resourceID.Guest.Type != guestType {
+		return diag.Errorf("guest '%s' is not of type '%s'", resourceID.Guest.String(), guestType)
+	}
+
+	ok, err := resourceID.Guest.ID.Exists(ctx, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if !ok {
+		return diag.Diagnostics{resourceDriftDeletionDiagnostic(d)}
+	}
+
+	rawSnapshots, err := pconf.NewClient.Snapshot.List(ctx, *pveSDK.NewVmRef(resourceID.Guest.ID))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	rawSnapshot, ok := rawSnapshots.SelectSnapshot(resourceID.Name)
+	if !ok {
+		return diag.Diagnostics{resourceDriftDeletionDiagnostic(d)}
+	}
+	snapshot := rawSnapshot.Get()
+
+	// The guest may have been migrated, only set it when importing so the configured reference is kept.
+	if d.Get("guest").(string) == "" {
+		d.Set("guest", resourceID.Guest.String())
+	}
+	d.Set("name", snapshot.Name.String())
+	d.Set("description", snapshot.Description)
+	d.Set("parent", "")
+	if snapshot.Parent != nil {
+		d.Set("parent", snapshot.Parent.String())
+	}
+	d.Set("time", "")
+	if snapshot.Time != nil {
+		d.Set("time", snapshot.Time.UTC().Format(time.RFC3339))
+	}
+	if guestType == id.GuestQemu {
+		d.Set("vmstate", snapshot.VmState != nil && *snapshot.VmState)
+	}
+	return nil
+}
+
+func resourceGuestSnapshotDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	var resourceID id.Snapshot
+	if err := resourceID.Parse(d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+	ok, err := resourceID.Guest.ID.Exists(ctx, pconf.Client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if !ok { // the snapshot was removed together with the guest
+		return nil
+	}
+	if _, err = pconf.NewClient.Snapshot.Delete(ctx, *pveSDK.NewVmRef(resourceID.Guest.ID), resourceID.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func testAccExampleQemuSnapshot(description, rollback string) string {
+	return testAccExampleQemuFake("test-vm", 512) + fmt.Sprintf(`
+resource "proxmox_vm_qemu_snapshot" "test" {
+  guest       = proxmox_vm_qemu.test.id
+  name        = "pre_upgrade"
+  description = "%s"
+  rollback    = "%s"
+}
+`, description, rollback)
+}
+
+func TestAccProxmoxVmQemuSnapshot_Fake(t *testing.T) {
+	fake, provider := testAccFakeProvider(t)
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProxmoxProviderFactory(),
+		Steps: []resource.TestStep{
+			{
+				Config: provider + testAccExampleQemuSnapshot("first", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_vm_qemu_snapshot.test", "id", "pve/qemu/100/pre_upgrade"),
+					resource.TestCheckResourceAttr("proxmox_vm_qemu_snapshot.test", "description", "first"),
+				),
+			},
+			{
+				Config: provider + testAccExampleQemuSnapshot("second", "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_vm_qemu_snapshot.test", "description", "second"),
+					func(*terraform.State) error {
+						if fake.Rollbacks(100) != 1 {
+							return fmt.Errorf("expected the guest to be rolled back once, got %d", fake.Rollbacks(100))
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:            "proxmox_vm_qemu_snapshot.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"guest", "rollback", "rollback_start"},
+			},
+		},
+	})
+}
+
+func Test_ResourceVmQemuSnapshot_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	fake.AddGuest("pve", "qemu", 100, map[string]string{"name": "test-vm", "scsi0": "local-lvm:10"})
+	meta := testFakeMeta(t, fake)
+	r := resourceVmQemuSnapshot()
+
+	config := map[string]any{"guest": "pve/qemu/100", "name": "first", "description": "before upgrade"}
+	first := testFakeCreate(t, r, meta, config)
+	require.Equal(t, "pve/qemu/100/first", first.Id())
+	require.Equal(t, "before upgrade", first.Get("description"))
+	require.Equal(t, "", first.Get("parent"))
+	require.NotEmpty(t, first.Get("time"))
+
+	// A stopped VM has no RAM to save.
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]any{"guest": "pve/qemu/100", "name": "second", "vmstate": true})
+	require.True(t, r.CreateContext(context.Background(), d, meta).HasError())
+
+	fake.SetGuestStatus(100, "running")
+	second := testFakeCreate(t, r, meta, map[string]any{"guest": "pve/qemu/100", "name": "second", "vmstate": true})
+	require.Equal(t, "first", second.Get("parent"))
+	require.Equal(t, true, second.Get("vmstate"))
+
+	config["description"] = "changed"
+	first = testFakeUpdate(t, r, meta, first, config)
+	require.Equal(t, "changed", first.Get("description"))
+	require.Equal(t, 0, fake.Rollbacks(100))
+
+	config["rollback"] = "2026-10-17"
+	first = testFakeUpdate(t, r, meta, first, config)
+	require.Equal(t, 1, fake.Rollbacks(100))
+	first = testFakeUpdate(t, r, meta, first, config)
+	require.Equal(t, 1, fake.Rollbacks(100))
+
+	testFakeDelete(t, r, meta, first)
+	testFakeRead(t, r, meta, first)
+	require.Equal(t, "", first.Id())
+	// The parent of a removed snapshot is inherited.
+	testFakeRead(t, r, meta, second)
+	require.Equal(t, "", second.Get("parent"))
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceLxcSnapshot_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	fake.AddGuest("pve", "lxc", 100, map[string]string{"hostname": "test-ct", "rootfs": "local-lvm:8"})
+	meta := testFakeMeta(t, fake)
+	r := resourceLxcSnapshot()
+
+	// The snapshot must match the type of the guest.
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]any{"guest": "pve/qemu/100", "name": "first"})
+	require.True(t, r.CreateContext(context.Background(), d, meta).HasError())
+
+	d = testFakeCreate(t, r, meta, map[string]any{"guest": "pve/lxc/100", "name": "first"})
+	require.Equal(t, "pve/lxc/100/first", d.Id())
+
+	// Importing only knows the ID.
+	imported := r.Data(&terraform.InstanceState{ID: d.Id()})
+	testFakeRead(t, r, meta, imported)
+	require.Equal(t, "pve/lxc/100", imported.Get("guest"))
+	require.Equal(t, "first", imported.Get("name"))
+
+	testFakeDelete(t, r, meta, d)
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "", d.Id())
+	testFakeUnknown(t, fake)
+}
@@ -0,0 +1,178 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const haGroupResourceType = "ha_group"
+
+// rxHAID matches the IDs of HA groups and rules.
+var rxHAID = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9\-_.]*$`)
+
+func resourceHAGroup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceHAGroupCreate,
+		ReadContext:   resourceHAGroupRead,
+		UpdateContext: resourceHAGroupUpdate,
+		DeleteContext: resourceHAGroupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"group": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(rxHAID, "must start with a letter and only contain letters, digits, '-', '_' and '.'"),
+				Description:  "The name of the HA group.",
+			},
+			"nodes": {
+				Type:        schema.TypeMap,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt, ValidateFunc: validation.IntBetween(0, 1000)},
+				Description: "The nodes of the group and their priority, guests run on the available node with the highest priority.",
+			},
+			"restricted": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Only run the guests of the group on the nodes of the group.",
+			},
+			"nofailback": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Do not move guests back to a node with a higher priority when it becomes available.",
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+// haNodesParam converts a map of nodes and their priority to the `node[:priority],...` format of the API.
+func haNodesParam(nodes map[string]interface{}) string {
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := make([]string, len(names))
+	for i, name := range names {
+		list[i] = name
+		if priority := nodes[name].(int); priority > 0 {
+			list[i] += ":" + strconv.Itoa(priority)
+		}
+	}
+	return strings.Join(list, ",")
+}
+
+// haParseNodes parses the `node[:priority],...` format of the API, nodes without a priority get 0.
+func haParseNodes(raw string) map[string]interface{} {
+	nodes := map[string]interface{}{}
+	for _, e := range strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || r == ' ' }) {
+		name, priority, _ := strings.Cut(e, ":")
+		nodes[name], _ = strconv.Atoi(priority)
+	}
+	return nodes
+}
+
+func haGroupParams(d *schema.ResourceData, update bool) map[string]interface{} {
+	params := map[string]interface{}{
+		"nodes":      haNodesParam(d.Get("nodes").(map[string]interface{})),
+		"restricted": d.Get("restricted").(bool),
+		"nofailback": d.Get("nofailback").(bool),
+	}
+	if v := d.Get("comment").(string); v != "" {
+		params["comment"] = v
+	} else if update {
+		params["delete"] = "comment"
+	}
+	return params
+}
+
+func resourceHAGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	group := d.Get("group").(string)
+	params := haGroupParams(d, false)
+	params["group"] = group
+	params["type"] = "group"
+	if err := pconf.Client.Post(ctx, params, "/cluster/ha/groups"); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(clusterResourceId(haGroupResourceType, group))
+	return diag.FromErr(_resourceHAGroupRead(ctx, d, pconf.Client))
+}
+
+func resourceHAGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return diag.FromErr(_resourceHAGroupRead(ctx, d, pconf.Client))
+}
+
+func _resourceHAGroupRead(ctx context.Context, d *schema.ResourceData, client *pveSDK.Client) error {
+	_, group, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+	item, err := listItem(ctx, client, "/cluster/ha/groups", "group", group)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		d.SetId("")
+		return nil
+	}
+	d.Set("group", group)
+	d.Set("nodes", haParseNodes(itemValue(item, "nodes")))
+	d.Set("restricted", itemValue(item, "restricted") == "1")
+	d.Set("nofailback", itemValue(item, "nofailback") == "1")
+	d.Set("comment", itemValue(item, "comment"))
+	return nil
+}
+
+func resourceHAGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, group, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err = pconf.Client.Put(ctx, haGroupParams(d, true), "/cluster/ha/groups/"+group); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(_resourceHAGroupRead(ctx, d, pconf.Client))
+}
+
+func resourceHAGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, group, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(pconf.Client.Delete(ctx, "/cluster/ha/groups/"+group))
+}
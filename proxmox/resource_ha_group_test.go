@@ -0,0 +1,99 @@
+package proxmox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func testAccExampleHA(state string) string {
+	return `
+resource "proxmox_ha_group" "test" {
+  group      = "primary"
+  restricted = true
+  nodes = {
+    pve1 = 2
+    pve2 = 1
+  }
+}
+
+resource "proxmox_ha_resource" "test" {
+  guest_id = 100
+  group    = proxmox_ha_group.test.group
+  state    = "` + state + `"
+}
+`
+}
+
+func TestAccProxmoxHA_Fake(t *testing.T) {
+	fake, provider := testAccFakeProvider(t, "pve1", "pve2")
+	fake.AddGuest("pve1", "qemu", 100, map[string]string{"name": "test-vm"})
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProxmoxProviderFactory(),
+		Steps: []resource.TestStep{
+			{
+				Config: provider + testAccExampleHA("started"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_ha_group.test", "id", "ha_group/primary"),
+					resource.TestCheckResourceAttr("proxmox_ha_group.test", "nodes.pve1", "2"),
+					resource.TestCheckResourceAttr("proxmox_ha_resource.test", "sid", "vm:100"),
+					resource.TestCheckResourceAttr("proxmox_ha_resource.test", "group", "primary"),
+				),
+			},
+			{
+				Config: provider + testAccExampleHA("stopped"),
+				Check:  resource.TestCheckResourceAttr("proxmox_ha_resource.test", "state", "stopped"),
+			},
+			{
+				ResourceName:      "proxmox_ha_group.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "proxmox_ha_resource.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func Test_ResourceHAGroup_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t, "pve1", "pve2", "pve3")
+	meta := testFakeMeta(t, fake)
+	r := resourceHAGroup()
+
+	config := map[string]any{
+		"group":   "primary",
+		"nodes":   map[string]any{"pve2": 0, "pve1": 5},
+		"comment": "database nodes"}
+	d := testFakeCreate(t, r, meta, config)
+	require.Equal(t, "ha_group/primary", d.Id())
+	group, ok := fake.HAGroup("primary")
+	require.True(t, ok)
+	require.Equal(t, "pve1:5,pve2", group["nodes"])
+	require.Equal(t, "0", group["restricted"])
+	require.Equal(t, map[string]any{"pve1": 5, "pve2": 0}, d.Get("nodes"))
+
+	config = map[string]any{
+		"group":      "primary",
+		"nodes":      map[string]any{"pve3": 1},
+		"nofailback": true}
+	d = testFakeUpdate(t, r, meta, d, config)
+	group, _ = fake.HAGroup("primary")
+	require.Equal(t, "pve3:1", group["nodes"])
+	require.Equal(t, "1", group["nofailback"])
+	require.NotContains(t, group, "comment")
+
+	diff, err := r.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(config), meta)
+	require.NoError(t, err)
+	require.True(t, diff == nil || diff.Empty())
+
+	testFakeDelete(t, r, meta, d)
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "", d.Id())
+	testFakeUnknown(t, fake)
+}
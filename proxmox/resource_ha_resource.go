@@ -0,0 +1,240 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const haResourceResourceType = "ha_resource"
+
+// haResourceOptional are the API keys that are removed when they are no longer configured.
+var haResourceOptional = []string{"comment", "group"}
+
+func resourceHAResource() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceHAResourceCreate,
+		ReadContext:   resourceHAResourceRead,
+		UpdateContext: resourceHAResourceUpdate,
+		DeleteContext: resourceHAResourceDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"guest_id": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(100),
+				Description:  "The ID of the guest that is managed by HA.",
+			},
+			"sid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The HA ID of the guest, `vm:<guest_id>` or `ct:<guest_id>`.",
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "started",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+					"started",
+					"stopped",
+					"disabled",
+					"ignored",
+				}, false)),
+				Description: "The state HA keeps the guest in.",
+			},
+			"group": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The HA group of the guest. HA groups are replaced by node affinity rules in PVE 9.",
+			},
+			"max_restart": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "How often HA tries to restart the guest on the same node after it failed to start.",
+			},
+			"max_relocate": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "How often HA tries to move the guest to another node after it failed to start.",
+			},
+			"failback": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Move the guest back to the node with the highest priority in its node affinity rule when that node is available again.",
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+func haResourceParams(d *schema.ResourceData, update bool) map[string]interface{} {
+	params := map[string]interface{}{
+		"state":        d.Get("state").(string),
+		"max_restart":  d.Get("max_restart").(int),
+		"max_relocate": d.Get("max_relocate").(int),
+		"comment":      d.Get("comment").(string),
+		"group":        d.Get("group").(string),
+	}
+	deleteKeys := make([]string, 0)
+	for _, key := range haResourceOptional {
+		if params[key] == "" {
+			delete(params, key)
+			deleteKeys = append(deleteKeys, key)
+		}
+	}
+	// failback only exists since PVE 9, so it is only sent when it differs from the default.
+	if !d.Get("failback").(bool) {
+		params["failback"] = false
+	} else if d.HasChange("failback") {
+		deleteKeys = append(deleteKeys, "failback")
+	}
+	if update && len(deleteKeys) > 0 {
+		params["delete"] = strings.Join(deleteKeys, ",")
+	}
+	return params
+}
+
+// haSids returns the HA IDs of the guests, which are prefixed with the type of the guest.
+func haSids(ctx context.Context, client *pveSDK.Client, ids []int) ([]string, error) {
+	guests, err := client.GetResourceList(ctx, "vm")
+	if err != nil {
+		return nil, err
+	}
+	types := make(map[int]string, len(guests))
+	for _, e := range guests {
+		if guest, ok := e.(map[string]interface{}); ok {
+			id, _ := strconv.Atoi(itemValue(guest, "vmid"))
+			types[id] = itemValue(guest, "type")
+		}
+	}
+	sids := make([]string, len(ids))
+	for i, id := range ids {
+		switch types[id] {
+		case "lxc":
+			sids[i] = "ct:" + strconv.Itoa(id)
+		case "qemu":
+			sids[i] = "vm:" + strconv.Itoa(id)
+		default:
+			return nil, fmt.Errorf("guest with ID %d does not exist", id)
+		}
+	}
+	return sids, nil
+}
+
+func resourceHAResourceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client := pconf.Client
+	id := d.Get("guest_id").(int)
+	sids, err := haSids(ctx, client, []int{id})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	params := haResourceParams(d, false)
+	params["sid"] = sids[0]
+	if err = client.Post(ctx, params, "/cluster/ha/resources"); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(clusterResourceId(haResourceResourceType, strconv.Itoa(id)))
+	return diag.FromErr(_resourceHAResourceRead(ctx, d, client))
+}
+
+func resourceHAResourceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return diag.FromErr(_resourceHAResourceRead(ctx, d, pconf.Client))
+}
+
+func _resourceHAResourceRead(ctx context.Context, d *schema.ResourceData, client *pveSDK.Client) error {
+	_, rawID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+	list, err := client.GetItemListInterfaceArray(ctx, "/cluster/ha/resources")
+	if err != nil {
+		return err
+	}
+	var item map[string]interface{}
+	for _, e := range list {
+		res, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, sidID, _ := strings.Cut(itemValue(res, "sid"), ":"); sidID == rawID {
+			item = res
+			break
+		}
+	}
+	if item == nil {
+		d.SetId("")
+		return nil
+	}
+	d.Set("guest_id", id)
+	d.Set("sid", itemValue(item, "sid"))
+	d.Set("state", itemValue(item, "state"))
+	d.Set("group", itemValue(item, "group"))
+	d.Set("comment", itemValue(item, "comment"))
+	if v, err := strconv.Atoi(itemValue(item, "max_restart")); err == nil {
+		d.Set("max_restart", v)
+	}
+	if v, err := strconv.Atoi(itemValue(item, "max_relocate")); err == nil {
+		d.Set("max_relocate", v)
+	}
+	d.Set("failback", itemValue(item, "failback") != "0")
+	return nil
+}
+
+func resourceHAResourceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, id, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err = pconf.Client.Put(ctx, haResourceParams(d, true), "/cluster/ha/resources/"+id); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(_resourceHAResourceRead(ctx, d, pconf.Client))
+}
+
+func resourceHAResourceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, id, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(pconf.Client.Delete(ctx, "/cluster/ha/resources/"+id))
+}
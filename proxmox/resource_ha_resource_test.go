@@ -0,0 +1,65 @@
+package proxmox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResourceHAResource_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	fake.AddGuest("pve", "lxc", 101, map[string]string{"hostname": "test-ct"})
+	meta := testFakeMeta(t, fake)
+	g := testFakeCreate(t, resourceHAGroup(), meta, map[string]any{"group": "primary", "nodes": map[string]any{"pve": 0}})
+	r := resourceHAResource()
+
+	config := map[string]any{
+		"guest_id":    101,
+		"group":       "primary",
+		"max_restart": 3,
+		"comment":     "managed by terraform"}
+	d := testFakeCreate(t, r, meta, config)
+	require.Equal(t, "ha_resource/101", d.Id())
+	require.Equal(t, "ct:101", d.Get("sid"))
+	require.Equal(t, "started", d.Get("state"))
+	require.Equal(t, 3, d.Get("max_restart"))
+	require.Equal(t, 1, d.Get("max_relocate"))
+	require.True(t, d.Get("failback").(bool))
+	// PVE 8 does not know failback, the default is not sent.
+	res, ok := fake.HAResource(101)
+	require.True(t, ok)
+	require.NotContains(t, res, "failback")
+
+	config = map[string]any{
+		"guest_id":     101,
+		"state":        "ignored",
+		"max_relocate": 0,
+		"failback":     false}
+	d = testFakeUpdate(t, r, meta, d, config)
+	res, _ = fake.HAResource(101)
+	require.Equal(t, "ignored", res["state"])
+	require.NotContains(t, res, "group")
+	require.NotContains(t, res, "comment")
+	require.Equal(t, 0, d.Get("max_relocate"))
+	require.False(t, d.Get("failback").(bool))
+
+	// Going back to the default removes the option.
+	config["failback"] = true
+	d = testFakeUpdate(t, r, meta, d, config)
+	res, _ = fake.HAResource(101)
+	require.NotContains(t, res, "failback")
+	require.True(t, d.Get("failback").(bool))
+
+	testFakeDelete(t, r, meta, d)
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "", d.Id())
+	testFakeDelete(t, resourceHAGroup(), meta, g)
+
+	// The guest has to exist.
+	diags := r.CreateContext(context.Background(), schema.TestResourceDataRaw(t, r.Schema, map[string]any{"guest_id": 999}), meta)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[0].Summary, "guest with ID 999 does not exist")
+	testFakeUnknown(t, fake)
+}
@@ -0,0 +1,246 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	haRuleResourceType     = "ha_rule"
+	haRuleNodeAffinity     = "node-affinity"
+	haRuleResourceAffinity = "resource-affinity"
+)
+
+func resourceHARule() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceHARuleCreate,
+		ReadContext:   resourceHARuleRead,
+		UpdateContext: resourceHARuleUpdate,
+		DeleteContext: resourceHARuleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"rule": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(rxHAID, "must start with a letter and only contain letters, digits, '-', '_' and '.'"),
+				Description:  "The ID of the HA rule.",
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+					haRuleNodeAffinity,
+					haRuleResourceAffinity,
+				}, false)),
+				Description: "'node-affinity' binds the guests to nodes, 'resource-affinity' keeps the guests together or apart.",
+			},
+			"guests": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "The IDs of the guests the rule applies to, the guests must be HA resources.",
+			},
+			"nodes": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt, ValidateFunc: validation.IntBetween(0, 1000)},
+				Description: "Node affinity only: the nodes and their priority, guests run on the available node with the highest priority.",
+			},
+			"strict": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Node affinity only: only run the guests on the nodes of the rule.",
+			},
+			"affinity": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+					"positive",
+					"negative",
+				}, false)),
+				Description: "Resource affinity only: 'positive' keeps the guests on the same node, 'negative' keeps them on different nodes.",
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			nodes := len(d.Get("nodes").(map[string]interface{}))
+			affinity := d.Get("affinity").(string)
+			switch d.Get("type").(string) {
+			case haRuleNodeAffinity:
+				if nodes == 0 && d.NewValueKnown("nodes") {
+					return fmt.Errorf("nodes is required for a %s rule", haRuleNodeAffinity)
+				}
+				if affinity != "" {
+					return fmt.Errorf("affinity can only be used in a %s rule", haRuleResourceAffinity)
+				}
+			case haRuleResourceAffinity:
+				if affinity == "" {
+					return fmt.Errorf("affinity is required for a %s rule", haRuleResourceAffinity)
+				}
+				if nodes > 0 || d.Get("strict").(bool) {
+					return fmt.Errorf("nodes and strict can only be used in a %s rule", haRuleNodeAffinity)
+				}
+				if guests := d.Get("guests").(*schema.Set); guests.Len() < 2 && d.NewValueKnown("guests") {
+					return fmt.Errorf("a %s rule needs at least two guests", haRuleResourceAffinity)
+				}
+			}
+			return nil
+		},
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+func haRuleParams(ctx context.Context, client *pveSDK.Client, d *schema.ResourceData, update bool) (map[string]interface{}, error) {
+	ids := make([]int, 0)
+	for _, e := range d.Get("guests").(*schema.Set).List() {
+		ids = append(ids, e.(int))
+	}
+	sort.Ints(ids)
+	sids, err := haSids(ctx, client, ids)
+	if err != nil {
+		return nil, err
+	}
+	ruleType := d.Get("type").(string)
+	params := map[string]interface{}{
+		"type":      ruleType,
+		"resources": strings.Join(sids, ","),
+	}
+	if ruleType == haRuleNodeAffinity {
+		params["nodes"] = haNodesParam(d.Get("nodes").(map[string]interface{}))
+		params["strict"] = d.Get("strict").(bool)
+	} else {
+		params["affinity"] = d.Get("affinity").(string)
+	}
+	deleteKeys := make([]string, 0)
+	if d.Get("enabled").(bool) {
+		deleteKeys = append(deleteKeys, "disable")
+	} else {
+		params["disable"] = true
+	}
+	if v := d.Get("comment").(string); v != "" {
+		params["comment"] = v
+	} else {
+		deleteKeys = append(deleteKeys, "comment")
+	}
+	if update {
+		params["delete"] = strings.Join(deleteKeys, ",")
+	}
+	return params, nil
+}
+
+func resourceHARuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client := pconf.Client
+	params, err := haRuleParams(ctx, client, d, false)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	rule := d.Get("rule").(string)
+	params["rule"] = rule
+	if err = client.Post(ctx, params, "/cluster/ha/rules"); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(clusterResourceId(haRuleResourceType, rule))
+	return diag.FromErr(_resourceHARuleRead(ctx, d, client))
+}
+
+func resourceHARuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return diag.FromErr(_resourceHARuleRead(ctx, d, pconf.Client))
+}
+
+func _resourceHARuleRead(ctx context.Context, d *schema.ResourceData, client *pveSDK.Client) error {
+	_, rule, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+	item, err := listItem(ctx, client, "/cluster/ha/rules", "rule", rule)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		d.SetId("")
+		return nil
+	}
+	guests := make([]int, 0)
+	for _, sid := range strings.Split(itemValue(item, "resources"), ",") {
+		_, rawID, _ := strings.Cut(sid, ":")
+		if id, err := strconv.Atoi(rawID); err == nil {
+			guests = append(guests, id)
+		}
+	}
+	d.Set("rule", rule)
+	d.Set("type", itemValue(item, "type"))
+	d.Set("guests", guests)
+	if itemValue(item, "type") == haRuleNodeAffinity {
+		d.Set("nodes", haParseNodes(itemValue(item, "nodes")))
+	} else {
+		d.Set("nodes", nil)
+	}
+	d.Set("strict", itemValue(item, "strict") == "1")
+	d.Set("affinity", itemValue(item, "affinity"))
+	d.Set("enabled", itemValue(item, "disable") != "1")
+	d.Set("comment", itemValue(item, "comment"))
+	return nil
+}
+
+func resourceHARuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client := pconf.Client
+	_, rule, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	params, err := haRuleParams(ctx, client, d, true)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err = client.Put(ctx, params, "/cluster/ha/rules/"+rule); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(_resourceHARuleRead(ctx, d, client))
+}
+
+func resourceHARuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, rule, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(pconf.Client.Delete(ctx, "/cluster/ha/rules/"+rule))
+}
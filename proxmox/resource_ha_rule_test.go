@@ -0,0 +1,93 @@
+package proxmox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResourceHARule_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t, "pve1", "pve2")
+	fake.AddGuest("pve1", "qemu", 100, map[string]string{"name": "db-1"})
+	fake.AddGuest("pve2", "lxc", 101, map[string]string{"hostname": "db-2"})
+	meta := testFakeMeta(t, fake)
+	for _, id := range []int{100, 101} {
+		testFakeCreate(t, resourceHAResource(), meta, map[string]any{"guest_id": id})
+	}
+	r := resourceHARule()
+
+	config := map[string]any{
+		"rule":     "db-apart",
+		"type":     "resource-affinity",
+		"guests":   []any{101, 100},
+		"affinity": "negative",
+		"comment":  "keep the databases apart"}
+	d := testFakeCreate(t, r, meta, config)
+	require.Equal(t, "ha_rule/db-apart", d.Id())
+	rule, ok := fake.HARule("db-apart")
+	require.True(t, ok)
+	require.Equal(t, "vm:100,ct:101", rule["resources"])
+	require.Equal(t, "negative", rule["affinity"])
+
+	config = map[string]any{
+		"rule":     "db-apart",
+		"type":     "resource-affinity",
+		"guests":   []any{101, 100},
+		"affinity": "positive",
+		"enabled":  false}
+	d = testFakeUpdate(t, r, meta, d, config)
+	rule, _ = fake.HARule("db-apart")
+	require.Equal(t, "positive", rule["affinity"])
+	require.Equal(t, "1", rule["disable"])
+	require.NotContains(t, rule, "comment")
+	require.False(t, d.Get("enabled").(bool))
+	testFakeDelete(t, r, meta, d)
+
+	d = testFakeCreate(t, r, meta, map[string]any{
+		"rule":   "db-nodes",
+		"type":   "node-affinity",
+		"guests": []any{100},
+		"nodes":  map[string]any{"pve1": 2, "pve2": 1},
+		"strict": true})
+	rule, _ = fake.HARule("db-nodes")
+	require.Equal(t, "pve1:2,pve2:1", rule["nodes"])
+	require.Equal(t, "1", rule["strict"])
+	require.Equal(t, map[string]any{"pve1": 2, "pve2": 1}, d.Get("nodes"))
+	require.True(t, d.Get("strict").(bool))
+
+	testFakeDelete(t, r, meta, d)
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "", d.Id())
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceHARule_Validation(t *testing.T) {
+	r := resourceHARule()
+	tests := []struct {
+		name   string
+		config map[string]any
+	}{
+		{name: "node affinity without nodes", config: map[string]any{"rule": "a", "type": "node-affinity", "guests": []any{100}}},
+		{name: "node affinity with affinity", config: map[string]any{"rule": "a", "type": "node-affinity", "guests": []any{100},
+			"nodes": map[string]any{"pve": 1}, "affinity": "positive"}},
+		{name: "resource affinity without affinity", config: map[string]any{"rule": "a", "type": "resource-affinity", "guests": []any{100, 101}}},
+		{name: "resource affinity with nodes", config: map[string]any{"rule": "a", "type": "resource-affinity", "guests": []any{100, 101},
+			"affinity": "negative", "nodes": map[string]any{"pve": 1}}},
+		{name: "resource affinity with one guest", config: map[string]any{"rule": "a", "type": "resource-affinity", "guests": []any{100},
+			"affinity": "negative"}},
+		{name: "invalid rule", config: map[string]any{"rule": "1a", "type": "node-affinity", "guests": []any{100},
+			"nodes": map[string]any{"pve": 1}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := terraform.NewResourceConfigRaw(test.config)
+			diags := r.Validate(config)
+			if !diags.HasError() {
+				_, err := r.Diff(context.Background(), nil, config, nil)
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -513,7 +513,7 @@ func resourceLxcCreate(ctx context.Context, d *schema.ResourceData, meta interfa
 
 	setGuestID := d.Get(vmID.Root).(int)
 
-	targetNode, err := node.SdkCreate(d)
+	targetNode, err := node.SdkCreate(d, nil, nil)
 	if err != nil {
 		return diag.FromErr(err)
 	}
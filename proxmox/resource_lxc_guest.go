@@ -22,8 +22,10 @@ import (
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/lxc/ssh_public_keys"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/lxc/swap"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/lxc/template"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/migration"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/name"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/node"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/placementgroup"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/pool"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/powerstate"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/reboot"
@@ -50,6 +52,7 @@ func resourceLxcGuest() *schema.Resource {
 		CustomizeDiff: customdiff.All(
 			networks.CustomizeDiff(),
 			reboot.CustomizeDiff(),
+			placementGroupCustomizeDiff(),
 		),
 
 		Schema: map[string]*schema.Schema{
@@ -61,6 +64,7 @@ func resourceLxcGuest() *schema.Resource {
 			features.Root:                features.Schema(),
 			guestid.Root:                 guestid.Schema(),
 			memory.Root:                  memory.Schema(),
+			migration.Root:               migration.SchemaLxc(),
 			mounts.RootMount:             mounts.SchemaMount(),
 			mounts.RootMounts:            mounts.SchemaMounts(),
 			name.Root:                    name.Schema(),
@@ -68,8 +72,11 @@ func resourceLxcGuest() *schema.Resource {
 			networks.RootNetworks:        networks.SchemaNetworks(),
 			node.RootNode:                node.SchemaNode(schema.Schema{ConflictsWith: []string{node.RootNodes}}, "lxc"),
 			node.RootNodes:               node.SchemaNodes("lxc"),
+			node.RootStrategy:            node.SchemaStrategy("lxc"),
+			node.Computed:                node.SchemaComputed("lxc"),
 			operatingsystem.Root:         operatingsystem.Schema(),
 			password.Root:                password.Schema(),
+			placementgroup.Root:          placementgroup.Schema("lxc"),
 			pool.Root:                    pool.Schema(),
 			powerstate.Root:              powerstate.Schema(schema.Schema{Default: powerstate.Default}),
 			privilege.RootPrivileged:     privilege.SchemaPrivileged(),
@@ -117,12 +124,16 @@ func resourceLxcGuestCreate(ctx context.Context, d *schema.ResourceData, meta an
 
 	// Set the node for the LXC container
 	var targetNode pveSDK.NodeName
-	targetNode, err = node.SdkCreate(d)
+	targetNode, err = node.SdkCreate(d, nodePlacement(ctx, pconf, lxcStorage(config)), nodeGroupFilter(ctx, client, placementgroup.SDK(d), 0))
 	if err != nil {
 		return append(diags, diag.Diagnostic{
 			Summary:  err.Error(),
 			Severity: diag.Error})
 	}
+	diags = append(diags, networks.CheckBridges(d, config.Networks, guestBridges(ctx, client, targetNode))...)
+	if diags.HasError() {
+		return diags
+	}
 
 	var vmr *pveSDK.VmRef
 
@@ -217,12 +228,25 @@ func resourceLxcGuestUpdate(ctx context.Context, d *schema.ResourceData, meta an
 
 	// update the targetNode for the LXC container
 	var targetNode pveSDK.NodeName
-	targetNode, err = node.SdkUpdate(d, vmr.Node())
+	targetNode, err = node.SdkUpdate(d, vmr.Node(), nodePlacement(ctx, pConf, lxcStorage(config)), nodeGroupFilter(ctx, client, placementgroup.SDK(d), vmr.VmId()))
 	if err != nil {
 		return append(diags, diag.Diagnostic{
 			Summary:  err.Error(),
 			Severity: diag.Error})
 	}
+	diags = append(diags, networks.CheckBridges(d, config.Networks, guestBridges(ctx, client, targetNode))...)
+	if diags.HasError() {
+		return diags
+	}
+	if targetNode != vmr.Node() {
+		if err = guestMigrate(ctx, client, vmr, targetNode, migration.SDK(d)); err != nil {
+			// Keep the node the guest is on in the state.
+			node.Terraform(vmr.Node(), d)
+			return append(diags, diag.Diagnostic{
+				Summary:  err.Error(),
+				Severity: diag.Error})
+		}
+	}
 	config.Node = &targetNode
 	config.Pool = util.Pointer(pool.SDK(d))
 
@@ -300,6 +324,9 @@ func resourceLxcGuestRead(ctx context.Context, d *schema.ResourceData, vmr *pveS
 	}
 
 	config := raw.Get(poolPtr, pveSDK.PowerStateUnknown)
+	// The active config does not know where it was read from, so take the ID and node from the reference.
+	config.ID = util.Pointer(vmr.VmId())
+	config.Node = util.Pointer(vmr.Node())
 
 	architecture.Terraform(config.Architecture, d)
 	cpu.Terraform(config.CPU, d)
@@ -322,7 +349,7 @@ func resourceLxcGuestRead(ctx context.Context, d *schema.ResourceData, vmr *pveS
 	startatnodeboot.Terraform(*config.StartAtNodeBoot, d)
 	startupshutdown.Terraform(config.StartupShutdown, d)
 	swap.Terraform(config.Swap, d)
-	tags.Terraform(config.Tags, d)
+	tags.Terraform(placementgroup.Terraform(config.Tags, d), d)
 	return nil
 }
 
@@ -347,7 +374,7 @@ func lxcSDK(privilidged bool, version pveSDK.Version, d *schema.ResourceData) (p
 		StartupShutdown: startupshutdown.SDK(d),
 		State:           powerstate.SDK(powerstate.LegacyFalse, d),
 		Swap:            swap.SDK(d),
-		Tags:            tags.SDK(d),
+		Tags:            placementgroup.AddTag(tags.SDK(d), placementgroup.SDK(d)),
 	}
 	var diags, tmpDiags diag.Diagnostics
 	config.Networks, diags = networks.SDK(version.Encode(), d)
@@ -359,6 +386,14 @@ func lxcSDK(privilidged bool, version pveSDK.Version, d *schema.ResourceData) (p
 	return config, diags
 }
 
+// lxcStorage returns the storage of the root mount, which the storage placement strategy looks at.
+func lxcStorage(config pveSDK.ConfigLXC) string {
+	if config.BootMount != nil && config.BootMount.Storage != nil {
+		return *config.BootMount.Storage
+	}
+	return ""
+}
+
 func lxcGuestWarning() diag.Diagnostics {
 	return diag.Diagnostics{{
 		Detail:   "The LXC Guest resource is experimental. The schema and functionality may change in future releases without a major version bump.",
@@ -0,0 +1,152 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func testAccExampleLxcGuestFake(name string, memory int) string {
+	return fmt.Sprintf(`
+resource "proxmox_lxc_guest" "test" {
+  name         = "%s"
+  target_node  = "pve"
+  unprivileged = true
+  password     = "secret"
+  memory       = %d
+  template {
+    file    = "alpine.tar.xz"
+    storage = "local"
+  }
+  root_mount {
+    size    = "4G"
+    storage = "local-lvm"
+  }
+}
+`, name, memory)
+}
+
+// TestAccProxmoxLxcGuest_Fake runs a create and update cycle against the in-process fake API.
+func TestAccProxmoxLxcGuest_Fake(t *testing.T) {
+	fake, provider := testAccFakeProvider(t)
+	fake.PutFile("pve", "local", "vztmpl", "alpine.tar.xz", []byte("template"))
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProxmoxProviderFactory(),
+		Steps: []resource.TestStep{
+			{
+				Config: provider + testAccExampleLxcGuestFake("test-ct", 512),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_lxc_guest.test", "id", "pve/lxc/100"),
+					resource.TestCheckResourceAttr("proxmox_lxc_guest.test", "guest_id", "100"),
+					resource.TestCheckResourceAttr("proxmox_lxc_guest.test", "current_node", "pve"),
+				),
+			},
+			{
+				Config: provider + testAccExampleLxcGuestFake("test-ct", 1024),
+				Check:  resource.TestCheckResourceAttr("proxmox_lxc_guest.test", "memory", "1024"),
+			},
+		},
+	})
+}
+
+func Test_ResourceLxcGuest_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	fake.PutFile("pve", "local", "vztmpl", "alpine.tar.xz", []byte("template"))
+	meta := testFakeMeta(t, fake)
+	r := resourceLxcGuest()
+
+	d := testFakeCreate(t, r, meta, map[string]any{
+		"name":         "test-ct",
+		"target_node":  "pve",
+		"unprivileged": true,
+		"password":     "secret",
+		"template":     []any{map[string]any{"file": "alpine.tar.xz", "storage": "local"}},
+		"root_mount":   []any{map[string]any{"size": "4G", "storage": "local-lvm"}}})
+	require.Equal(t, "pve/lxc/100", d.Id())
+	require.Equal(t, 100, d.Get("guest_id"))
+	require.Equal(t, "pve", d.Get("current_node"))
+	require.Equal(t, "running", d.Get("power_state"))
+
+	testFakeDelete(t, r, meta, d)
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceLxcGuest_Migration_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t, "pve1", "pve2")
+	fake.PutFile("pve1", "local", "vztmpl", "alpine.tar.xz", []byte("template"))
+	meta := testFakeMeta(t, fake)
+	r := resourceLxcGuest()
+
+	config := map[string]any{
+		"name":         "test-ct",
+		"target_node":  "pve1",
+		"unprivileged": true,
+		"password":     "secret",
+		"template":     []any{map[string]any{"file": "alpine.tar.xz", "storage": "local"}},
+		"root_mount":   []any{map[string]any{"size": "4G", "storage": "local-lvm"}},
+		"migration":    []any{map[string]any{"restart": false}}}
+	d := testFakeCreate(t, r, meta, config)
+	require.Equal(t, "pve1/lxc/100", d.Id())
+
+	// A running container can only be moved in restart mode.
+	config["target_node"] = "pve2"
+	state := d.State()
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), meta)
+	require.NoError(t, err)
+	state, diags := r.Apply(context.Background(), state, diff, meta)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[len(diags)-1].Summary, "the guest is on node 'pve1'")
+	require.Equal(t, "pve1", r.Data(state).Get("target_node"))
+
+	config["migration"] = []any{map[string]any{"restart_timeout": 60, "target_storage": "local-lvm"}}
+	d = testFakeUpdate(t, r, meta, r.Data(state), config)
+	require.Equal(t, "pve2/lxc/100", d.Id())
+	require.Equal(t, "pve2", d.Get("current_node"))
+	require.Equal(t, "running", d.Get("power_state"))
+	require.Equal(t, map[string]string{
+		"target":         "pve2",
+		"restart":        "1",
+		"timeout":        "60",
+		"target-storage": "local-lvm"}, fake.Migrations(100)[0])
+
+	testFakeDelete(t, r, meta, d)
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceLxcGuest_PlacementStrategy_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t, "pve1", "pve2")
+	fake.PutFile("pve1", "local", "vztmpl", "alpine.tar.xz", []byte("template"))
+	meta := testFakeMeta(t, fake)
+	r := resourceLxcGuest()
+
+	ctConfig := func(name, size, strategy string) map[string]any {
+		return map[string]any{
+			"name":               name,
+			"target_nodes":       []any{"pve1", "pve2"},
+			"placement_strategy": strategy,
+			"unprivileged":       true,
+			"password":           "secret",
+			"template":           []any{map[string]any{"file": "alpine.tar.xz", "storage": "local"}},
+			"root_mount":         []any{map[string]any{"size": size, "storage": "local-lvm"}}}
+	}
+	big := ctConfig("big", "100G", "random")
+	delete(big, "target_nodes")
+	big["target_node"] = "pve1"
+	testFakeCreate(t, r, meta, big)
+
+	// local-lvm is not shared, pve2 has the most free space left.
+	d := testFakeCreate(t, r, meta, ctConfig("small", "4G", "storage"))
+	require.Equal(t, "pve2/lxc/101", d.Id())
+
+	// Round robin continues after pve2, which has the newest guest, and then takes turns.
+	d = testFakeCreate(t, r, meta, ctConfig("rr1", "4G", "round_robin"))
+	require.Equal(t, "pve1/lxc/102", d.Id())
+	d = testFakeCreate(t, r, meta, ctConfig("rr2", "4G", "round_robin"))
+	require.Equal(t, "pve2/lxc/103", d.Id())
+
+	testFakeUnknown(t, fake)
+}
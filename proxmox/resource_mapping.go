@@ -0,0 +1,263 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// mappingConfig describes how a PCI or USB mapping resource converts its device entries.
+type mappingConfig struct {
+	// kind is the path of the mappings below /cluster/mapping, "pci" or "usb".
+	kind string
+	// options maps the arguments to the API options, next to the device entries.
+	options map[string]string
+	// entries returns the device entries of the map argument in the format of the API, after checking them against the hardware of the nodes.
+	entries func(ctx context.Context, client *pveSDK.Client, d *schema.ResourceData) ([]string, error)
+	// entry converts a device entry of the API to a map block.
+	entry func(values map[string]string) map[string]interface{}
+	// schema is the schema of the resource, it is used to convert the options of the API.
+	schema map[string]*schema.Schema
+}
+
+func (config mappingConfig) resource(s map[string]*schema.Schema) *schema.Resource {
+	config.schema = s
+	return &schema.Resource{
+		CreateContext: config.create,
+		ReadContext:   config.readContext,
+		UpdateContext: config.update,
+		DeleteContext: config.delete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema:   config.schema,
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+// mappingNameSchema returns the schema of the name of a mapping, validate is the validation of the SDK for the mapping IDs of guests.
+func mappingNameSchema(validate func(string) error) *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+		ForceNew: true,
+		ValidateDiagFunc: func(i interface{}, path cty.Path) diag.Diagnostics {
+			if err := validate(i.(string)); err != nil {
+				return diag.Diagnostics{diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       "Invalid name",
+					Detail:        err.Error(),
+					AttributePath: path}}
+			}
+			return nil
+		},
+		Description: "The name of the mapping, guests refer to it as their `mapping_id`.",
+	}
+}
+
+// mappingCommentSchema returns the schema of the comment of a device entry, which cannot contain the separators of the entry.
+func mappingCommentSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validation.StringDoesNotContainAny(",;="),
+	}
+}
+
+// mappingFillID reports whether the ID in key of the i-th device entry has to be taken from the device:
+// when it is not set, or when the device in deviceKey changed while the ID stayed the same.
+func mappingFillID(d *schema.ResourceData, i int, key, deviceKey string) bool {
+	prefix := fmt.Sprintf("map.%d.", i)
+	return d.Get(prefix+key).(string) == "" || (d.HasChange(prefix+deviceKey) && !d.HasChange(prefix+key))
+}
+
+func (config mappingConfig) resourceType() string {
+	return "mapping_" + config.kind
+}
+
+func (config mappingConfig) url() string {
+	return "/cluster/mapping/" + config.kind
+}
+
+// mappingEntry returns a device entry in the `key=value,...` format of the API, empty values are left out.
+func mappingEntry(pairs ...string) string {
+	values := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			values = append(values, pairs[i]+"="+pairs[i+1])
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+func mappingParseEntry(raw string) map[string]string {
+	values := map[string]string{}
+	for _, kv := range strings.Split(raw, ",") {
+		k, v, _ := strings.Cut(kv, "=")
+		values[k] = v
+	}
+	return values
+}
+
+func (config mappingConfig) params(ctx context.Context, client *pveSDK.Client, d *schema.ResourceData, update bool) (map[string]interface{}, error) {
+	entries, err := config.entries(ctx, client, d)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]interface{}{}
+	optionParams(d, params, config.options, config.schema, update)
+	deleteEmptyParams(params, update)
+	params["map"] = entries
+	return params, nil
+}
+
+func (config mappingConfig) create(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	name := d.Get("name").(string)
+	params, err := config.params(ctx, pconf.Client, d, false)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	params["id"] = name
+	if err = pconf.Client.Post(ctx, params, config.url()); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(clusterResourceId(config.resourceType(), name))
+	return config.read(ctx, d, pconf.Client)
+}
+
+func (config mappingConfig) readContext(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return config.read(ctx, d, pconf.Client)
+}
+
+// read sets the mapping and returns the problems PVE finds when it checks the devices against the nodes as warnings.
+func (config mappingConfig) read(ctx context.Context, d *schema.ResourceData, client *pveSDK.Client) diag.Diagnostics {
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return diag.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+	item, err := listItem(ctx, client, config.url(), "id", name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if item == nil {
+		d.SetId("")
+		return nil
+	}
+	rawEntries, _ := item["map"].([]interface{})
+	entries := make([]interface{}, len(rawEntries))
+	nodes := map[string]struct{}{}
+	for i, raw := range rawEntries {
+		values := mappingParseEntry(fmt.Sprint(raw))
+		nodes[values["node"]] = struct{}{}
+		entries[i] = config.entry(values)
+	}
+	d.Set("name", name)
+	d.Set("map", entries)
+	optionRead(d, item, config.options, config.schema)
+
+	var diags diag.Diagnostics
+	for _, node := range sortedMapKeys(nodes) {
+		checked, err := listItem(ctx, client, config.url()+"?check-node="+node, "id", name)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("could not check %s mapping '%s' on node '%s'", strings.ToUpper(config.kind), name, node),
+				Detail:   err.Error(),
+			})
+			continue
+		}
+		if checked == nil {
+			continue
+		}
+		checks, _ := checked["checks"].([]interface{})
+		for _, e := range checks {
+			if check, ok := e.(map[string]interface{}); ok {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  fmt.Sprintf("%s mapping '%s' on node '%s': %s", strings.ToUpper(config.kind), name, node, itemValue(check, "message")),
+				})
+			}
+		}
+	}
+	return diags
+}
+
+func (config mappingConfig) update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	params, err := config.params(ctx, pconf.Client, d, true)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err = pconf.Client.Put(ctx, params, config.url()+"/"+name); err != nil {
+		return diag.FromErr(err)
+	}
+	return config.read(ctx, d, pconf.Client)
+}
+
+func (config mappingConfig) delete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(pconf.Client.Delete(ctx, config.url()+"/"+name))
+}
+
+// mappingHardware returns the devices of a hardware endpoint of a node, e.g. "pci" or "usb".
+func mappingHardware(ctx context.Context, client *pveSDK.Client, node, kind string) ([]map[string]interface{}, error) {
+	url := "/nodes/" + node + "/hardware/" + kind
+	if kind == "pci" {
+		// An empty blacklist also returns the devices PVE hides by default.
+		url += "?pci-class-blacklist="
+	}
+	list, err := client.GetItemListInterfaceArray(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	devices := make([]map[string]interface{}, 0, len(list))
+	for _, e := range list {
+		if device, ok := e.(map[string]interface{}); ok {
+			devices = append(devices, device)
+		}
+	}
+	return devices, nil
+}
+
+// mappingUSBPort returns the port of a USB device as `<bus>-<path>`, PVE lists the path of the port without the bus.
+func mappingUSBPort(device map[string]interface{}) string {
+	return itemValue(device, "busnum") + "-" + itemValue(device, "usbpath")
+}
+
+func sortedMapKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,163 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var (
+	// rxMappingPCIPath matches a PCI address, the domain and the function may be left out.
+	rxMappingPCIPath = regexp.MustCompile(`^([0-9a-fA-F]{4}:)?[0-9a-fA-F]{2}:[0-9a-fA-F]{2}(\.[0-7])?$`)
+	// rxMappingDeviceID matches a `<vendor>:<device>` ID.
+	rxMappingDeviceID = regexp.MustCompile(`^[0-9a-fA-F]{4}:[0-9a-fA-F]{4}$`)
+)
+
+func resourceMappingPCI() *schema.Resource {
+	config := mappingConfig{
+		kind: "pci",
+		options: map[string]string{
+			"description":      "description",
+			"mediated_devices": "mdev",
+		},
+		entries: mappingPCIEntries,
+		entry: func(values map[string]string) map[string]interface{} {
+			group, _ := strconv.Atoi(values["iommugroup"])
+			return map[string]interface{}{
+				"node":         values["node"],
+				"path":         values["path"],
+				"id":           values["id"],
+				"subsystem_id": values["subsystem-id"],
+				"iommu_group":  group,
+				"comment":      values["comment"],
+			}
+		},
+	}
+	return config.resource(map[string]*schema.Schema{
+		"name": mappingNameSchema(func(name string) error { return pveSDK.ResourceMappingPciID(name).Validate() }),
+		"description": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"mediated_devices": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Use the devices for mediated devices, like vGPUs, instead of passing them through as a whole.",
+		},
+		"map": {
+			Type:     schema.TypeList,
+			Required: true,
+			MinItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"node": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"path": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.StringMatch(rxMappingPCIPath, "must be a PCI address like 0000:01:00.0"),
+						Description:  "The PCI address of the device on the node, without a function all functions of the device are mapped.",
+					},
+					"id": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Computed:     true,
+						ValidateFunc: validation.StringMatch(rxMappingDeviceID, "must be a <vendor>:<device> ID like 10de:1eb8"),
+						Description:  "The `<vendor>:<device>` ID of the device, it is taken from the node when it is not set.",
+					},
+					"subsystem_id": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Computed:     true,
+						ValidateFunc: validation.StringMatch(rxMappingDeviceID, "must be a <vendor>:<device> ID like 10de:12a2"),
+						Description:  "The `<vendor>:<device>` ID of the subsystem of the device, it is taken from the node when it is not set.",
+					},
+					"iommu_group": {
+						Type:        schema.TypeInt,
+						Computed:    true,
+						Description: "The IOMMU group of the device when the mapping was written.",
+					},
+					"comment": mappingCommentSchema(),
+				},
+			},
+			Description: "The device of each node.",
+		},
+	})
+}
+
+// mappingPCIDevice returns the device at a PCI address, a path without a function matches function 0.
+func mappingPCIDevice(devices []map[string]interface{}, path string) map[string]interface{} {
+	path = strings.ToLower(path)
+	if !strings.Contains(path, ".") {
+		path += ".0"
+	}
+	if strings.Count(path, ":") == 1 {
+		path = "0000:" + path
+	}
+	for _, device := range devices {
+		if itemValue(device, "id") == path {
+			return device
+		}
+	}
+	return nil
+}
+
+// mappingPCIID returns the `<vendor>:<device>` ID of a device from the `0x` prefixed keys of the hardware API.
+func mappingPCIID(device map[string]interface{}, vendor, deviceKey string) string {
+	if itemValue(device, vendor) == "" {
+		return ""
+	}
+	return strings.TrimPrefix(itemValue(device, vendor), "0x") + ":" + strings.TrimPrefix(itemValue(device, deviceKey), "0x")
+}
+
+// mappingPCIEntries checks the devices of the map argument against the nodes and fills in the IDs and IOMMU groups.
+func mappingPCIEntries(ctx context.Context, client *pveSDK.Client, d *schema.ResourceData) ([]string, error) {
+	blocks := d.Get("map").([]interface{})
+	hardware := map[string][]map[string]interface{}{}
+	entries := make([]string, len(blocks))
+	for i, b := range blocks {
+		block := b.(map[string]interface{})
+		node, path := block["node"].(string), block["path"].(string)
+		if _, ok := hardware[node]; !ok {
+			devices, err := mappingHardware(ctx, client, node, "pci")
+			if err != nil {
+				return nil, err
+			}
+			hardware[node] = devices
+		}
+		device := mappingPCIDevice(hardware[node], path)
+		if device == nil {
+			return nil, fmt.Errorf("node '%s' has no PCI device at '%s'", node, path)
+		}
+		id, subsystemID := mappingPCIID(device, "vendor", "device"), mappingPCIID(device, "subsystem_vendor", "subsystem_device")
+		if !mappingFillID(d, i, "id", "path") && !strings.EqualFold(block["id"].(string), id) {
+			return nil, fmt.Errorf("PCI device '%s' of node '%s' has ID '%s' instead of '%s'", path, node, id, block["id"])
+		}
+		if !mappingFillID(d, i, "subsystem_id", "path") && !strings.EqualFold(block["subsystem_id"].(string), subsystemID) {
+			return nil, fmt.Errorf("PCI device '%s' of node '%s' has subsystem ID '%s' instead of '%s'", path, node, subsystemID, block["subsystem_id"])
+		}
+		if itemValue(device, "iommugroup") == "-1" {
+			return nil, fmt.Errorf("PCI device '%s' of node '%s' has no IOMMU group, enable IOMMU on the node to pass it through", path, node)
+		}
+		if d.Get("mediated_devices").(bool) && itemValue(device, "mdev") != "1" && itemValue(device, "mdev") != "true" {
+			return nil, fmt.Errorf("PCI device '%s' of node '%s' does not support mediated devices", path, node)
+		}
+		entries[i] = mappingEntry(
+			"node", node,
+			"path", path,
+			"id", id,
+			"subsystem-id", subsystemID,
+			"iommugroup", itemValue(device, "iommugroup"),
+			"comment", block["comment"].(string))
+	}
+	return entries, nil
+}
@@ -0,0 +1,162 @@
+package proxmox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResourceMappingPCI_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t, "pve1", "pve2")
+	meta := testFakeMeta(t, fake)
+	r := resourceMappingPCI()
+
+	config := map[string]any{"name": "tesla-t4", "description": "GPU", "map": []any{
+		map[string]any{"node": "pve1", "path": "0000:01:00.0"},
+		map[string]any{"node": "pve2", "path": "01:00.0", "id": "10de:1eb8", "comment": "slot 2"},
+	}}
+	d := testFakeCreate(t, r, meta, config)
+	require.Equal(t, "mapping_pci/tesla-t4", d.Id())
+	options, entries, ok := fake.Mapping("pci", "tesla-t4")
+	require.True(t, ok)
+	require.Equal(t, "GPU", options["description"])
+	require.Equal(t, []string{
+		"node=pve1,path=0000:01:00.0,id=10de:1eb8,subsystem-id=10de:12a2,iommugroup=1",
+		"node=pve2,path=01:00.0,id=10de:1eb8,subsystem-id=10de:12a2,iommugroup=1,comment=slot 2",
+	}, entries)
+	require.Equal(t, "10de:1eb8", d.Get("map.0.id"))
+	require.Equal(t, 1, d.Get("map.0.iommu_group"))
+
+	// A changed IOMMU group is reported as a warning, writing the mapping again records the new group.
+	fake.SetIOMMUGroup("pve2", "0000:01:00.0", 7)
+	diags := r.ReadContext(context.Background(), d, meta)
+	require.False(t, diags.HasError())
+	require.Len(t, diags, 1)
+	require.Equal(t, diag.Warning, diags[0].Severity)
+	require.Contains(t, diags[0].Summary, "IOMMU group")
+
+	// A node that can't be checked is reported as a warning as well.
+	fake.SetNodeOnline("pve1", false)
+	diags = r.ReadContext(context.Background(), d, meta)
+	require.False(t, diags.HasError())
+	require.Len(t, diags, 2)
+	require.Equal(t, diag.Warning, diags[0].Severity)
+	require.Contains(t, diags[0].Summary, "on node 'pve1'")
+	require.Contains(t, diags[0].Detail, "no route to host")
+	fake.SetNodeOnline("pve1", true)
+
+	config["description"] = ""
+	config["map"] = []any{
+		map[string]any{"node": "pve1", "path": "0000:01:00.0"},
+		map[string]any{"node": "pve2", "path": "01:00.0", "id": "10de:1eb8"},
+	}
+	d = testFakeUpdate(t, r, meta, d, config)
+	options, entries, _ = fake.Mapping("pci", "tesla-t4")
+	require.NotContains(t, options, "description")
+	require.Equal(t, "node=pve2,path=01:00.0,id=10de:1eb8,subsystem-id=10de:12a2,iommugroup=7", entries[1])
+	require.Empty(t, r.ReadContext(context.Background(), d, meta))
+
+	// Moving the mapping to another device takes the ID of the new device.
+	config["map"] = []any{map[string]any{"node": "pve1", "path": "0000:02:00.0"}}
+	d = testFakeUpdate(t, r, meta, d, config)
+	_, entries, _ = fake.Mapping("pci", "tesla-t4")
+	require.Equal(t, []string{"node=pve1,path=0000:02:00.0,id=8086:1533,iommugroup=2"}, entries)
+
+	testFakeDelete(t, r, meta, d)
+	_, _, ok = fake.Mapping("pci", "tesla-t4")
+	require.False(t, ok)
+
+	// Devices that do not exist, do not match, have no IOMMU group or do not support mediated devices are rejected.
+	fake.SetIOMMUGroup("pve1", "0000:02:00.0", -1)
+	for _, config := range []map[string]any{
+		{"name": "missing", "map": []any{map[string]any{"node": "pve1", "path": "0000:03:00.0"}}},
+		{"name": "mismatch", "map": []any{map[string]any{"node": "pve1", "path": "0000:01:00.0", "id": "10de:1eb9"}}},
+		{"name": "no-iommu", "map": []any{map[string]any{"node": "pve1", "path": "0000:02:00.0"}}},
+		{"name": "mdev", "mediated_devices": true, "map": []any{map[string]any{"node": "pve1", "path": "0000:01:00.0"}}},
+	} {
+		d := schema.TestResourceDataRaw(t, r.Schema, config)
+		require.True(t, r.CreateContext(context.Background(), d, meta).HasError(), config["name"])
+	}
+	d = testFakeCreate(t, r, meta, map[string]any{"name": "igpu", "mediated_devices": true, "map": []any{
+		map[string]any{"node": "pve1", "path": "00:02.0"}}})
+	options, _, _ = fake.Mapping("pci", "igpu")
+	require.Equal(t, "1", options["mdev"])
+	require.True(t, d.Get("mediated_devices").(bool))
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceMappingUSB_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t, "pve1", "pve2")
+	meta := testFakeMeta(t, fake)
+	r := resourceMappingUSB()
+
+	config := map[string]any{"name": "receiver", "map": []any{
+		map[string]any{"node": "pve1", "path": "1-1"},
+		map[string]any{"node": "pve2", "id": "046d:c52b", "comment": "any port"},
+	}}
+	d := testFakeCreate(t, r, meta, config)
+	require.Equal(t, "mapping_usb/receiver", d.Id())
+	_, entries, ok := fake.Mapping("usb", "receiver")
+	require.True(t, ok)
+	require.Equal(t, []string{"node=pve1,id=046d:c52b,path=1-1", "node=pve2,id=046d:c52b,comment=any port"}, entries)
+	require.Equal(t, "046d:c52b", d.Get("map.0.id"))
+
+	config["description"] = "keyboard and mouse"
+	config["map"] = []any{map[string]any{"node": "pve1", "path": "2-2"}}
+	d = testFakeUpdate(t, r, meta, d, config)
+	options, entries, _ := fake.Mapping("usb", "receiver")
+	require.Equal(t, "keyboard and mouse", options["description"])
+	require.Equal(t, []string{"node=pve1,id=0951:1666,path=2-2"}, entries)
+
+	// Empty ports cannot be mapped without an ID, devices that do not match the port are rejected.
+	for _, config := range []map[string]any{
+		{"name": "empty", "map": []any{map[string]any{"node": "pve1", "path": "3-1"}}},
+		{"name": "mismatch", "map": []any{map[string]any{"node": "pve1", "path": "1-1", "id": "0951:1666"}}},
+		{"name": "twice", "map": []any{map[string]any{"node": "pve1", "id": "0951:1666"}, map[string]any{"node": "pve1", "id": "046d:c52b"}}},
+		{"name": "nothing", "map": []any{map[string]any{"node": "pve1"}}},
+	} {
+		d := schema.TestResourceDataRaw(t, r.Schema, config)
+		require.True(t, r.CreateContext(context.Background(), d, meta).HasError(), config["name"])
+	}
+
+	testFakeDelete(t, r, meta, d)
+	_, _, ok = fake.Mapping("usb", "receiver")
+	require.False(t, ok)
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceMapping_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource *schema.Resource
+		config   map[string]any
+	}{
+		{name: "pci name", resource: resourceMappingPCI(), config: map[string]any{"name": "1gpu",
+			"map": []any{map[string]any{"node": "pve", "path": "0000:01:00.0"}}}},
+		{name: "pci path", resource: resourceMappingPCI(), config: map[string]any{"name": "gpu",
+			"map": []any{map[string]any{"node": "pve", "path": "01:00:0"}}}},
+		{name: "pci id", resource: resourceMappingPCI(), config: map[string]any{"name": "gpu",
+			"map": []any{map[string]any{"node": "pve", "path": "01:00.0", "id": "0x10de:0x1eb8"}}}},
+		{name: "pci comment", resource: resourceMappingPCI(), config: map[string]any{"name": "gpu",
+			"map": []any{map[string]any{"node": "pve", "path": "01:00.0", "comment": "a,b"}}}},
+		{name: "pci without map", resource: resourceMappingPCI(), config: map[string]any{"name": "gpu"}},
+		{name: "usb name", resource: resourceMappingUSB(), config: map[string]any{"name": "a",
+			"map": []any{map[string]any{"node": "pve", "id": "046d:c52b"}}}},
+		{name: "usb path", resource: resourceMappingUSB(), config: map[string]any{"name": "receiver",
+			"map": []any{map[string]any{"node": "pve", "path": "usb1"}}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := terraform.NewResourceConfigRaw(test.config)
+			diags := test.resource.Validate(config)
+			if !diags.HasError() {
+				_, err := test.resource.Diff(context.Background(), nil, config, nil)
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// rxMappingUSBPath matches a USB port like `1-2` or `1-2.3`.
+var rxMappingUSBPath = regexp.MustCompile(`^\d+-\d+(\.\d+)*$`)
+
+func resourceMappingUSB() *schema.Resource {
+	config := mappingConfig{
+		kind: "usb",
+		options: map[string]string{
+			"description": "description",
+		},
+		entries: mappingUSBEntries,
+		entry: func(values map[string]string) map[string]interface{} {
+			return map[string]interface{}{
+				"node":    values["node"],
+				"id":      values["id"],
+				"path":    values["path"],
+				"comment": values["comment"],
+			}
+		},
+	}
+	return config.resource(map[string]*schema.Schema{
+		"name": mappingNameSchema(func(name string) error { return pveSDK.ResourceMappingUsbID(name).Validate() }),
+		"description": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"map": {
+			Type:     schema.TypeList,
+			Required: true,
+			MinItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"node": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"id": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Computed:     true,
+						ValidateFunc: validation.StringMatch(rxMappingDeviceID, "must be a <vendor>:<product> ID like 046d:c52b"),
+						Description:  "The `<vendor>:<product>` ID of the device. Without a `path` the device is mapped wherever it is plugged in, with a `path` it is taken from the device on the port when it is not set.",
+					},
+					"path": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						ValidateFunc: validation.StringMatch(rxMappingUSBPath, "must be a USB port like 1-2 or 1-2.3"),
+						Description:  "The USB port of the node, any device plugged into it is mapped.",
+					},
+					"comment": mappingCommentSchema(),
+				},
+			},
+			Description: "The device of each node, a node can only be mapped once.",
+		},
+	})
+}
+
+// mappingUSBEntries checks the devices of the map argument against the nodes and fills in the IDs of the devices on the ports.
+func mappingUSBEntries(ctx context.Context, client *pveSDK.Client, d *schema.ResourceData) ([]string, error) {
+	blocks := d.Get("map").([]interface{})
+	entries := make([]string, len(blocks))
+	nodes := map[string]struct{}{}
+	for i, b := range blocks {
+		block := b.(map[string]interface{})
+		node, id, path := block["node"].(string), block["id"].(string), block["path"].(string)
+		if _, ok := nodes[node]; ok {
+			return nil, fmt.Errorf("node '%s' is mapped more than once", node)
+		}
+		nodes[node] = struct{}{}
+		if path != "" {
+			devices, err := mappingHardware(ctx, client, node, "usb")
+			if err != nil {
+				return nil, err
+			}
+			var device map[string]interface{}
+			for _, e := range devices {
+				if mappingUSBPort(e) == path {
+					device = e
+					break
+				}
+			}
+			var portID string
+			if device != nil {
+				portID = itemValue(device, "vendid") + ":" + itemValue(device, "prodid")
+			}
+			switch {
+			case mappingFillID(d, i, "id", "path"):
+				if device == nil {
+					return nil, fmt.Errorf("no USB device is plugged into port '%s' of node '%s', set the id of the device", path, node)
+				}
+				id = portID
+			case device != nil && !strings.EqualFold(id, portID):
+				return nil, fmt.Errorf("the USB device on port '%s' of node '%s' has ID '%s' instead of '%s'", path, node, portID, id)
+			}
+		}
+		if id == "" {
+			return nil, fmt.Errorf("the device of node '%s' needs an id or a path", node)
+		}
+		entries[i] = mappingEntry(
+			"node", node,
+			"id", id,
+			"path", path,
+			"comment", block["comment"].(string))
+	}
+	return entries, nil
+}
@@ -0,0 +1,253 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// networkConfig describes how the arguments of a network interface resource map to the options of the API.
+type networkConfig struct {
+	// ifaceType is the type of the interface in the API, e.g. "bridge".
+	ifaceType string
+	// name matches the names PVE allows for the interface type.
+	name *regexp.Regexp
+	// nameFormat describes the names that match name.
+	nameFormat string
+	// options maps the arguments to the API options.
+	options map[string]string
+	// lists maps the set arguments to the API options that hold a space separated list.
+	lists map[string]string
+	// schema is the schema of the resource, it is used to convert the options of the API.
+	schema map[string]*schema.Schema
+}
+
+// resource returns the resource of the interface type, typeSchema holds the arguments of the interface type.
+func (config networkConfig) resource(typeSchema map[string]*schema.Schema) *schema.Resource {
+	config.schema = networkSchema(config, typeSchema)
+	return &schema.Resource{
+		CreateContext: config.create,
+		ReadContext:   config.readContext,
+		UpdateContext: config.update,
+		DeleteContext: networkDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema:   config.schema,
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+// networkSchema returns the arguments every network interface has, merged with the arguments of the interface type.
+func networkSchema(config networkConfig, typeSchema map[string]*schema.Schema) map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"node": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The node the interface is created on.",
+		},
+		"name": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringMatch(config.name, "must be "+config.nameFormat),
+			Description:  "The name of the interface, e.g. `" + config.nameFormat + "`.",
+		},
+		"autostart": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Bring the interface up when the node boots.",
+		},
+		"address": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.IsCIDR,
+			Description:  "The IPv4 address of the interface in CIDR notation.",
+		},
+		"gateway": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.IsIPv4Address,
+			Description:  "The IPv4 default gateway.",
+		},
+		"address6": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.IsCIDR,
+			Description:  "The IPv6 address of the interface in CIDR notation.",
+		},
+		"gateway6": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.IsIPv6Address,
+			Description:  "The IPv6 default gateway.",
+		},
+		"mtu": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntBetween(1280, 65520),
+			Description:  "The MTU of the interface.",
+		},
+		"comment": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+	}
+	for k, v := range typeSchema {
+		s[k] = v
+	}
+	return s
+}
+
+var networkOptions = map[string]string{
+	"address":  "cidr",
+	"gateway":  "gateway",
+	"address6": "cidr6",
+	"gateway6": "gateway6",
+	"mtu":      "mtu",
+	"comment":  "comments",
+}
+
+func networkSetList(set *schema.Set) string {
+	list := make([]string, 0, set.Len())
+	for _, e := range set.List() {
+		list = append(list, e.(string))
+	}
+	sort.Strings(list)
+	return strings.Join(list, " ")
+}
+
+// params returns the parameters of the create or update request, unset options are removed on update.
+func (config networkConfig) params(d *schema.ResourceData, update bool) map[string]interface{} {
+	params := map[string]interface{}{
+		"type":      config.ifaceType,
+		"autostart": d.Get("autostart").(bool),
+	}
+	optionParams(d, params, networkOptions, config.schema, update)
+	optionParams(d, params, config.options, config.schema, update)
+	for key, option := range config.lists {
+		params[option] = networkSetList(d.Get(key).(*schema.Set))
+	}
+	deleteEmptyParams(params, update)
+	return params
+}
+
+// networkApply applies the pending network changes of the node.
+// When applying fails the pending changes are reverted, so the node keeps its working network and the next apply does not pick them up.
+func networkApply(ctx context.Context, client *pveSDK.Client, node string) error {
+	_, err := client.ApplyNetwork(ctx, node)
+	if err == nil {
+		return nil
+	}
+	if _, revertErr := client.RevertNetwork(ctx, node); revertErr != nil {
+		return fmt.Errorf("applying the network of node '%s' failed: %v, reverting the pending changes failed: %v", node, err, revertErr)
+	}
+	return fmt.Errorf("applying the network of node '%s' failed, the pending changes are reverted: %v", node, err)
+}
+
+func (config networkConfig) create(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	pconf.NetworkMutex.Lock()
+	defer pconf.NetworkMutex.Unlock()
+
+	iface := id.NetworkInterface{Node: pveSDK.NodeName(d.Get("node").(string)), Name: d.Get("name").(string)}
+	params := config.params(d, false)
+	params["iface"] = iface.Name
+	if err := pconf.Client.Post(ctx, params, "/nodes/"+iface.Node.String()+"/network"); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := networkApply(ctx, pconf.Client, iface.Node.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(iface.String())
+	return diag.FromErr(config.read(ctx, d, pconf.Client))
+}
+
+func (config networkConfig) readContext(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return diag.FromErr(config.read(ctx, d, pconf.Client))
+}
+
+func (config networkConfig) read(ctx context.Context, d *schema.ResourceData, client *pveSDK.Client) error {
+	var iface id.NetworkInterface
+	if err := iface.Parse(d.Id()); err != nil {
+		d.SetId("")
+		return fmt.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+	item, err := listItem(ctx, client, "/nodes/"+iface.Node.String()+"/network", "iface", iface.Name)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		d.SetId("")
+		return nil
+	}
+	if ifaceType := itemValue(item, "type"); ifaceType != config.ifaceType {
+		return fmt.Errorf("network interface '%s' is of type '%s' instead of '%s'", iface.Name, ifaceType, config.ifaceType)
+	}
+	d.Set("node", iface.Node.String())
+	d.Set("name", iface.Name)
+	// PVE leaves out autostart when it is disabled.
+	d.Set("autostart", itemValue(item, "autostart") == "1")
+	optionRead(d, item, networkOptions, config.schema)
+	optionRead(d, item, config.options, config.schema)
+	for key, option := range config.lists {
+		list := make([]interface{}, 0)
+		for _, e := range strings.Fields(itemValue(item, option)) {
+			list = append(list, e)
+		}
+		d.Set(key, list)
+	}
+	return nil
+}
+
+func (config networkConfig) update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	pconf.NetworkMutex.Lock()
+	defer pconf.NetworkMutex.Unlock()
+
+	var iface id.NetworkInterface
+	if err := iface.Parse(d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := pconf.Client.Put(ctx, config.params(d, true), "/nodes/"+iface.Node.String()+"/network/"+iface.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := networkApply(ctx, pconf.Client, iface.Node.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(config.read(ctx, d, pconf.Client))
+}
+
+func networkDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	pconf.NetworkMutex.Lock()
+	defer pconf.NetworkMutex.Unlock()
+
+	var iface id.NetworkInterface
+	if err := iface.Parse(d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := pconf.Client.Delete(ctx, "/nodes/"+iface.Node.String()+"/network/"+iface.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(networkApply(ctx, pconf.Client, iface.Node.String()))
+}
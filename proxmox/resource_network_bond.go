@@ -0,0 +1,56 @@
+package proxmox
+
+import (
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceNetworkBond() *schema.Resource {
+	config := networkConfig{
+		ifaceType:  "bond",
+		name:       regexp.MustCompile(`^bond\d{1,4}$`),
+		nameFormat: "bond<N>",
+		options: map[string]string{
+			"mode":         "bond_mode",
+			"hash_policy":  "bond_xmit_hash_policy",
+			"bond_primary": "bond-primary",
+		},
+		lists: map[string]string{"slaves": "slaves"},
+	}
+	return config.resource(map[string]*schema.Schema{
+		"slaves": {
+			Type:        schema.TypeSet,
+			Required:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "The physical interfaces that are bonded.",
+		},
+		"mode": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  "balance-rr",
+			ValidateFunc: validation.StringInSlice([]string{
+				"balance-rr",
+				"active-backup",
+				"balance-xor",
+				"broadcast",
+				"802.3ad",
+				"balance-tlb",
+				"balance-alb",
+			}, false),
+			Description: "The bonding mode.",
+		},
+		"hash_policy": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice([]string{"layer2", "layer2+3", "layer3+4"}, false),
+			Description:  "The transmit hash policy of the `balance-xor` and `802.3ad` modes.",
+		},
+		"bond_primary": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The slave that is used while it is up in the `active-backup` mode.",
+		},
+	})
+}
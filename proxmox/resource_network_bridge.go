@@ -0,0 +1,41 @@
+package proxmox
+
+import (
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceNetworkBridge() *schema.Resource {
+	config := networkConfig{
+		ifaceType:  "bridge",
+		name:       regexp.MustCompile(`^vmbr\d{1,4}$`),
+		nameFormat: "vmbr<N>",
+		options: map[string]string{
+			"vlan_aware": "bridge_vlan_aware",
+			"vids":       "bridge_vids",
+		},
+		lists: map[string]string{"ports": "bridge_ports"},
+	}
+	return config.resource(map[string]*schema.Schema{
+		"ports": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "The interfaces that are bridged, e.g. a physical interface or a bond.",
+		},
+		"vlan_aware": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Let the bridge handle VLAN tags, so guests can use the `tag` of their network device.",
+		},
+		"vids": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringMatch(regexp.MustCompile(`^\d+(-\d+)?([ ,;]\d+(-\d+)?)*$`), "must be a list of VLAN IDs and ranges, e.g. `2-4094`"),
+			Description:  "The VLAN IDs the bridge allows when `vlan_aware` is set, PVE uses `2-4094` when not set.",
+		},
+	})
+}
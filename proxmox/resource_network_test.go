@@ -0,0 +1,156 @@
+package proxmox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResourceNetworkBridge_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceNetworkBridge()
+
+	config := map[string]any{
+		"node":       "pve",
+		"name":       "vmbr1",
+		"ports":      []any{"eno2"},
+		"vlan_aware": true,
+		"address":    "10.0.0.1/24",
+		"comment":    "guests"}
+	d := testFakeCreate(t, r, meta, config)
+	require.Equal(t, "pve/network/vmbr1", d.Id())
+	iface, ok := fake.NetworkInterface("pve", "vmbr1")
+	require.True(t, ok)
+	require.Equal(t, "bridge", iface["type"])
+	require.Equal(t, "eno2", iface["bridge_ports"])
+	require.Equal(t, "1", iface["bridge_vlan_aware"])
+	require.Equal(t, "10.0.0.1/24", iface["cidr"])
+	require.False(t, fake.NetworkPending("pve"))
+	require.True(t, d.Get("autostart").(bool))
+	require.Equal(t, "guests", d.Get("comment"))
+
+	config["mtu"] = 9000
+	config["autostart"] = false
+	delete(config, "address")
+	d = testFakeUpdate(t, r, meta, d, config)
+	iface, _ = fake.NetworkInterface("pve", "vmbr1")
+	require.Equal(t, "9000", iface["mtu"])
+	require.NotContains(t, iface, "cidr")
+	require.False(t, d.Get("autostart").(bool))
+	require.Equal(t, "", d.Get("address"))
+
+	// An apply that finishes with warnings is applied and not reverted.
+	fake.FailTask("srvreload", "WARNINGS: 1")
+	config["comment"] = "guest bridge"
+	d = testFakeUpdate(t, r, meta, d, config)
+	iface, _ = fake.NetworkInterface("pve", "vmbr1")
+	require.Equal(t, "guest bridge", iface["comments"])
+	require.False(t, fake.NetworkPending("pve"))
+
+	// A failed apply reverts the pending changes, so the applied network stays as it was.
+	fake.FailTask("srvreload", "ifreload failed")
+	config["mtu"] = 1500
+	state := d.State()
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), meta)
+	require.NoError(t, err)
+	d, err = schema.InternalMap(r.Schema).Data(state, diff)
+	require.NoError(t, err)
+	require.True(t, r.UpdateContext(context.Background(), d, meta).HasError())
+	iface, _ = fake.NetworkInterface("pve", "vmbr1")
+	require.Equal(t, "9000", iface["mtu"])
+	require.False(t, fake.NetworkPending("pve"))
+
+	// A bond is not read as a bridge.
+	d.SetId("pve/network/eno1")
+	require.True(t, r.ReadContext(context.Background(), d, meta).HasError())
+	d.SetId("pve/network/vmbr1")
+
+	testFakeDelete(t, r, meta, d)
+	_, ok = fake.NetworkInterface("pve", "vmbr1")
+	require.False(t, ok)
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "", d.Id())
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceNetworkBond_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceNetworkBond()
+
+	config := map[string]any{
+		"node":        "pve",
+		"name":        "bond0",
+		"slaves":      []any{"eno2", "eno1"},
+		"mode":        "802.3ad",
+		"hash_policy": "layer3+4"}
+	d := testFakeCreate(t, r, meta, config)
+	iface, _ := fake.NetworkInterface("pve", "bond0")
+	require.Equal(t, "eno1 eno2", iface["slaves"])
+	require.Equal(t, "802.3ad", iface["bond_mode"])
+	require.Equal(t, "layer3+4", iface["bond_xmit_hash_policy"])
+	require.Equal(t, 2, d.Get("slaves").(*schema.Set).Len())
+
+	// A bridge on the bond is created in the same run.
+	bridge := testFakeCreate(t, resourceNetworkBridge(), meta, map[string]any{"node": "pve", "name": "vmbr1", "ports": []any{"bond0"}})
+	iface, _ = fake.NetworkInterface("pve", "vmbr1")
+	require.Equal(t, "bond0", iface["bridge_ports"])
+
+	// The bond is still in use by the bridge.
+	require.True(t, r.DeleteContext(context.Background(), d, meta).HasError())
+	testFakeDelete(t, resourceNetworkBridge(), meta, bridge)
+	testFakeDelete(t, r, meta, d)
+	_, ok := fake.NetworkInterface("pve", "bond0")
+	require.False(t, ok)
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceNetworkVlan_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceNetworkVlan()
+
+	d := testFakeCreate(t, r, meta, map[string]any{"node": "pve", "name": "eno1.20"})
+	require.Equal(t, 20, d.Get("vlan_id"))
+	require.Equal(t, "eno1", d.Get("raw_device"))
+
+	d = testFakeCreate(t, r, meta, map[string]any{"node": "pve", "name": "vlan30", "vlan_id": 30, "raw_device": "vmbr0"})
+	iface, _ := fake.NetworkInterface("pve", "vlan30")
+	require.Equal(t, "30", iface["vlan-id"])
+	require.Equal(t, "vmbr0", iface["vlan-raw-device"])
+	testFakeDelete(t, r, meta, d)
+
+	// PVE needs the device of a vlan<N> interface.
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]any{"node": "pve", "name": "vlan40", "vlan_id": 40})
+	require.True(t, r.CreateContext(context.Background(), d, meta).HasError())
+	require.False(t, fake.NetworkPending("pve"))
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceNetwork_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource *schema.Resource
+		config   map[string]any
+	}{
+		{name: "bridge name", resource: resourceNetworkBridge(), config: map[string]any{"node": "pve", "name": "br0"}},
+		{name: "bond name", resource: resourceNetworkBond(), config: map[string]any{"node": "pve", "name": "vmbr1", "slaves": []any{"eno1"}}},
+		{name: "bond mode", resource: resourceNetworkBond(), config: map[string]any{"node": "pve", "name": "bond0", "slaves": []any{"eno1"}, "mode": "lacp"}},
+		{name: "invalid address", resource: resourceNetworkBridge(), config: map[string]any{"node": "pve", "name": "vmbr1", "address": "10.0.0.1"}},
+		{name: "vlan id", resource: resourceNetworkVlan(), config: map[string]any{"node": "pve", "name": "vlan10", "vlan_id": 4095, "raw_device": "eno1"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := terraform.NewResourceConfigRaw(test.config)
+			diags := test.resource.Validate(config)
+			if !diags.HasError() {
+				_, err := test.resource.Diff(context.Background(), nil, config, nil)
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package proxmox
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceNetworkVlan() *schema.Resource {
+	config := networkConfig{
+		ifaceType:  "vlan",
+		name:       regexp.MustCompile(`^(?:vlan\d+|[a-zA-Z][a-zA-Z0-9_]*\.\d+)$`),
+		nameFormat: "<device>.<vlan_id> or vlan<N>",
+		options: map[string]string{
+			"vlan_id":    "vlan-id",
+			"raw_device": "vlan-raw-device",
+		},
+	}
+	r := config.resource(map[string]*schema.Schema{
+		"vlan_id": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.IntBetween(1, 4094),
+			Description:  "The VLAN tag, it is taken from the name when the name is `<device>.<vlan_id>`.",
+		},
+		"raw_device": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+			Description: "The interface the VLAN is on, it is taken from the name when the name is `<device>.<vlan_id>`.",
+		},
+	})
+	r.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		name := d.Get("name").(string)
+		config := d.GetRawConfig()
+		if !d.NewValueKnown("name") || !strings.HasPrefix(name, "vlan") || config.IsNull() {
+			return nil
+		}
+		// Both are computed, so only the config tells whether they are set.
+		for _, key := range []string{"vlan_id", "raw_device"} {
+			if config.GetAttr(key).IsNull() {
+				return errors.New(key + " is required when the name is vlan<N>")
+			}
+		}
+		return nil
+	}
+	return r
+}
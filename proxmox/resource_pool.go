@@ -35,6 +35,28 @@ func resourcePool() *schema.Resource {
 				Default:  defaultDescription,
 				Optional: true,
 			},
+			"members": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The guests and storages that are currently in the pool.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the member, `guest` or `storage`.",
+						},
+						"guest_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"storage": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
 		},
 		Timeouts: resourceTimeouts(),
 	}
@@ -91,12 +113,25 @@ func _resourcePoolRead(ctx context.Context, d *schema.ResourceData, meta interfa
 
 	d.SetId(clusterResourceId("pools", poolID))
 	d.Set("comment", rawPool.GetComment())
+	d.Set("members", poolMembers(rawPool.GetMembers()))
 
 	// DEBUG print the read result
 	logger.Debug().Str("poolid", poolID).Msgf("Finished pool read resulting in data: '%+v'", rawPool.Get())
 	return nil
 }
 
+func poolMembers(raw pveSDK.RawPoolMembers) []map[string]interface{} {
+	members := make([]map[string]interface{}, 0, raw.Len())
+	for _, e := range raw.AsArray() {
+		if guest, ok := e.AsGuest(); ok {
+			members = append(members, map[string]interface{}{"type": "guest", "guest_id": int(guest.GetID())})
+		} else if storage, ok := e.AsStorage(); ok {
+			members = append(members, map[string]interface{}{"type": "storage", "storage": string(storage.GetName())})
+		}
+	}
+	return members
+}
+
 func resourcePoolUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	pconf := meta.(*providerConfiguration)
 	lock := pmParallelBegin(pconf)
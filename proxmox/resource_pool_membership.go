@@ -0,0 +1,178 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const poolMembershipResourceType = "pool_membership"
+
+func resourcePoolMembership() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourcePoolMembershipCreate,
+		ReadContext:   resourcePoolMembershipRead,
+		UpdateContext: resourcePoolMembershipUpdate,
+		DeleteContext: resourcePoolMembershipDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"pool": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the pool.",
+			},
+			"guests": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt, ValidateFunc: validation.IntAtLeast(100)},
+				Description: "The IDs of the guests in the pool, guests that are not listed are removed from the pool.",
+			},
+			"storages": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The storages in the pool, storages that are not listed are removed from the pool.",
+			},
+		},
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+func poolMembershipSDK(d *schema.ResourceData) ([]pveSDK.GuestID, []pveSDK.StorageName) {
+	rawGuests := d.Get("guests").(*schema.Set).List()
+	guests := make([]pveSDK.GuestID, len(rawGuests))
+	for i, e := range rawGuests {
+		guests[i] = pveSDK.GuestID(e.(int))
+	}
+	sort.Slice(guests, func(i, j int) bool { return guests[i] < guests[j] })
+	rawStorages := d.Get("storages").(*schema.Set).List()
+	storages := make([]pveSDK.StorageName, len(rawStorages))
+	for i, e := range rawStorages {
+		storages[i] = pveSDK.StorageName(e.(string))
+	}
+	sort.Slice(storages, func(i, j int) bool { return storages[i] < storages[j] })
+	return guests, storages
+}
+
+// poolMembershipSet makes the members of the pool match the configuration, guests are moved from the pool they are in.
+func poolMembershipSet(ctx context.Context, d *schema.ResourceData, client *pveSDK.ClientNew, pool pveSDK.PoolName) error {
+	guests, storages := poolMembershipSDK(d)
+	return client.Pool.Update(ctx, pveSDK.ConfigPool{
+		Name:     pool,
+		Guests:   &guests,
+		Storages: &storages,
+	})
+}
+
+func resourcePoolMembershipCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	pool := d.Get("pool").(string)
+	if err := poolMembershipSet(ctx, d, pconf.NewClient, pveSDK.PoolName(pool)); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(clusterResourceId(poolMembershipResourceType, pool))
+	return diag.FromErr(_resourcePoolMembershipRead(ctx, d, pconf.NewClient))
+}
+
+func resourcePoolMembershipRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return diag.FromErr(_resourcePoolMembershipRead(ctx, d, pconf.NewClient))
+}
+
+func _resourcePoolMembershipRead(ctx context.Context, d *schema.ResourceData, client *pveSDK.ClientNew) error {
+	_, pool, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+	exists, err := client.Pool.Exists(ctx, pveSDK.PoolName(pool))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		d.SetId("")
+		return nil
+	}
+	raw, err := client.Pool.Read(ctx, pveSDK.PoolName(pool))
+	if err != nil {
+		return err
+	}
+	rawGuests, rawStorages := raw.GetMembers().AsArrays()
+	guests := make([]interface{}, len(rawGuests))
+	for i := range rawGuests {
+		guests[i] = int(rawGuests[i].GetID())
+	}
+	storages := make([]interface{}, len(rawStorages))
+	for i := range rawStorages {
+		storages[i] = string(rawStorages[i].GetName())
+	}
+	d.Set("pool", pool)
+	d.Set("guests", schema.NewSet(schema.HashInt, guests))
+	d.Set("storages", schema.NewSet(schema.HashString, storages))
+	return nil
+}
+
+func resourcePoolMembershipUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, pool, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err = poolMembershipSet(ctx, d, pconf.NewClient, pveSDK.PoolName(pool)); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(_resourcePoolMembershipRead(ctx, d, pconf.NewClient))
+}
+
+// resourcePoolMembershipDelete removes the members of the configuration from the pool, the pool itself is kept.
+func resourcePoolMembershipDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client := pconf.NewClient
+	_, pool, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	exists, err := client.Pool.Exists(ctx, pveSDK.PoolName(pool))
+	if err != nil || !exists {
+		return diag.FromErr(err)
+	}
+	raw, err := client.Pool.Read(ctx, pveSDK.PoolName(pool))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	currentGuests, currentStorages := raw.GetMembers().AsMaps()
+	guests, storages := poolMembershipSDK(d)
+	removeGuests := make([]pveSDK.GuestID, 0, len(guests))
+	for _, e := range guests {
+		if _, ok := currentGuests[e]; ok {
+			removeGuests = append(removeGuests, e)
+		}
+	}
+	removeStorages := make([]pveSDK.StorageName, 0, len(storages))
+	for _, e := range storages {
+		if _, ok := currentStorages[e]; ok {
+			removeStorages = append(removeStorages, e)
+		}
+	}
+	return diag.FromErr(client.Pool.RemoveMembers(ctx, pveSDK.PoolName(pool), removeGuests, removeStorages))
+}
@@ -0,0 +1,66 @@
+package proxmox
+
+import (
+	"context"
+	"testing"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResourcePoolMembership_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourcePoolMembership()
+
+	fake.AddGuest("pve", "qemu", 100, map[string]string{"name": "vm-100"})
+	fake.AddGuest("pve", "qemu", 101, map[string]string{"name": "vm-101"})
+	fake.AddGuest("pve", "lxc", 102, map[string]string{"hostname": "ct-102"})
+	testFakeCreate(t, resourcePool(), meta, map[string]any{"poolid": "old"})
+	pool := testFakeCreate(t, resourcePool(), meta, map[string]any{"poolid": "test-pool"})
+	old := testFakeCreate(t, r, meta, map[string]any{"pool": "old", "guests": []any{101}})
+
+	// Guests are moved from the pool they are in.
+	config := map[string]any{
+		"pool":     "test-pool",
+		"guests":   []any{100, 101},
+		"storages": []any{"local"}}
+	d := testFakeCreate(t, r, meta, config)
+	require.Equal(t, "pool_membership/test-pool", d.Id())
+	guests, storages, _ := fake.PoolMembers("test-pool")
+	require.Equal(t, []int{100, 101}, guests)
+	require.Equal(t, []string{"local"}, storages)
+	guests, _, _ = fake.PoolMembers("old")
+	require.Empty(t, guests)
+
+	testFakeRead(t, r, meta, old)
+	require.Empty(t, old.Get("guests").(*schema.Set).List())
+
+	// Members that are added outside of the configuration are detected.
+	require.NoError(t, meta.NewClient.Pool.AddMembers(context.Background(), "test-pool", []pveSDK.GuestID{102}, nil))
+	testFakeRead(t, r, meta, d)
+	require.ElementsMatch(t, []any{100, 101, 102}, d.Get("guests").(*schema.Set).List())
+
+	// The membership is authoritative, members that are not in the configuration are removed.
+	d = testFakeUpdate(t, r, meta, d, map[string]any{
+		"pool":     "test-pool",
+		"guests":   []any{101, 102},
+		"storages": []any{"local", "local-lvm"}})
+	guests, storages, _ = fake.PoolMembers("test-pool")
+	require.Equal(t, []int{101, 102}, guests)
+	require.Equal(t, []string{"local", "local-lvm"}, storages)
+
+	testFakeDelete(t, r, meta, d)
+	guests, storages, _ = fake.PoolMembers("test-pool")
+	require.Empty(t, guests)
+	require.Empty(t, storages)
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "pool_membership/test-pool", d.Id())
+
+	// Deleting the pool removes the membership from the state.
+	testFakeDelete(t, resourcePool(), meta, pool)
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "", d.Id())
+	testFakeUnknown(t, fake)
+}
@@ -0,0 +1,71 @@
+package proxmox
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func testAccExamplePool(poolID, comment string) string {
+	return fmt.Sprintf(`
+resource "proxmox_pool" "test" {
+  poolid  = "%s"
+  comment = "%s"
+}
+`, poolID, comment)
+}
+
+func TestAccProxmoxPool_Fake(t *testing.T) {
+	_, provider := testAccFakeProvider(t)
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProxmoxProviderFactory(),
+		Steps: []resource.TestStep{
+			{
+				Config: provider + testAccExamplePool("test-pool", "first"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_pool.test", "id", "pools/test-pool"),
+					resource.TestCheckResourceAttr("proxmox_pool.test", "comment", "first"),
+				),
+			},
+			{
+				Config: provider + testAccExamplePool("test-pool", "second"),
+				Check:  resource.TestCheckResourceAttr("proxmox_pool.test", "comment", "second"),
+			},
+			{
+				ResourceName:      "proxmox_pool.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func Test_ResourcePool_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourcePool()
+
+	d := testFakeCreate(t, r, meta, map[string]any{"poolid": "test-pool", "comment": "first"})
+	require.Equal(t, "pools/test-pool", d.Id())
+	require.Equal(t, "first", d.Get("comment"))
+	require.Empty(t, d.Get("members"))
+
+	fake.AddGuest("pve", "qemu", 100, map[string]string{"name": "test-vm"})
+	m := testFakeCreate(t, resourcePoolMembership(), meta, map[string]any{
+		"pool":     "test-pool",
+		"guests":   []any{100},
+		"storages": []any{"local"}})
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, []any{
+		map[string]any{"type": "guest", "guest_id": 100, "storage": ""},
+		map[string]any{"type": "storage", "guest_id": 0, "storage": "local"},
+	}, d.Get("members"))
+	testFakeDelete(t, resourcePoolMembership(), meta, m)
+
+	testFakeDelete(t, r, meta, d)
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "", d.Id())
+	testFakeUnknown(t, fake)
+}
@@ -0,0 +1,401 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const realmResourceType = "realm"
+
+var rxRealmID = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9.\-_]{1,31}$`)
+
+// realmConfig describes how the arguments of a realm resource map to the options of the API.
+type realmConfig struct {
+	// realmType is the type of the realm in the API, e.g. "ldap".
+	realmType string
+	// options maps the arguments to the API options.
+	options map[string]string
+	// secrets maps the sensitive arguments to the API options, PVE never returns them.
+	secrets map[string]string
+	// sync is set for the realms that can sync users and groups.
+	sync bool
+	// schema is the schema of the resource, it is used to convert the options of the API.
+	schema map[string]*schema.Schema
+}
+
+// resource returns the resource of the realm type, typeSchema holds the arguments of the realm type.
+func (config realmConfig) resource(typeSchema map[string]*schema.Schema) *schema.Resource {
+	config.schema = realmSchema(config, typeSchema)
+	return &schema.Resource{
+		CreateContext: config.create,
+		ReadContext:   config.readContext,
+		UpdateContext: config.update,
+		DeleteContext: realmDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema:   config.schema,
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+// realmSchema returns the arguments every realm has, merged with the arguments of the realm type.
+func realmSchema(config realmConfig, typeSchema map[string]*schema.Schema) map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"realm": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringMatch(rxRealmID, "must start with a letter, be 2 to 32 characters long and only contain letters, digits, '.', '-' and '_'"),
+			Description:  "The ID of the realm, users of the realm log in as `name@realm`.",
+		},
+		"default": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Use the realm as the default realm of the login dialog.",
+		},
+		"comment": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+	}
+	for k, v := range typeSchema {
+		s[k] = v
+	}
+	if config.sync {
+		s["sync"] = realmSyncSchema()
+		s["sync_trigger"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Any value, the users and groups of the realm are synced when the realm is created and every time the value changes.",
+		}
+	}
+	return s
+}
+
+// realmDirectorySchema returns the arguments the LDAP and AD realms share.
+func realmDirectorySchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"server": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The address of the server.",
+		},
+		"fallback_server": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The address of the server that is used when `server` is not reachable.",
+		},
+		"port": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IsPortNumberOrZero,
+			Description:  "The port of the server, 0 uses the default port of the mode.",
+		},
+		"mode": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  "ldap",
+			ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+				"ldap",
+				"ldaps",
+				"ldap+starttls",
+			}, false)),
+		},
+		"verify": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Verify the certificate of the server.",
+		},
+		"bind_dn": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The user that is used to search the directory.",
+		},
+		"bind_password": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "The password of `bind_dn`.",
+		},
+		"filter": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The LDAP filter for users.",
+		},
+		"user_classes": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"group_dn": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The base DN of the groups.",
+		},
+		"group_filter": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The LDAP filter for groups.",
+		},
+		"group_name_attribute": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"group_classes": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"sync_attributes": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Which LDAP attributes are synced to which user properties, e.g. `email=mail`.",
+		},
+		"case_sensitive": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+	}
+}
+
+// realmDirectoryOptions maps the arguments of realmDirectorySchema to the API options.
+var realmDirectoryOptions = map[string]string{
+	"server":               "server1",
+	"fallback_server":      "server2",
+	"port":                 "port",
+	"mode":                 "mode",
+	"verify":               "verify",
+	"bind_dn":              "bind_dn",
+	"filter":               "filter",
+	"user_classes":         "user_classes",
+	"group_dn":             "group_dn",
+	"group_filter":         "group_filter",
+	"group_name_attribute": "group_name_attr",
+	"group_classes":        "group_classes",
+	"sync_attributes":      "sync_attributes",
+	"case_sensitive":       "case-sensitive",
+}
+
+func realmSyncSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "The default options of a sync.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"scope": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Default:  "both",
+					ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+						"users",
+						"groups",
+						"both",
+					}, false)),
+				},
+				"enable_new": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     true,
+					Description: "Enable the users that are created by the sync.",
+				},
+				"remove_vanished": {
+					Type:        schema.TypeSet,
+					Optional:    true,
+					Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringInSlice([]string{"acl", "entry", "properties"}, false)},
+					Description: "What is removed when a user or group is no longer in the directory.",
+				},
+			},
+		},
+	}
+}
+
+// realmSyncOptions returns the sync options of the sync block, PVE uses the defaults of the realm when the block is not set.
+func realmSyncOptions(d *schema.ResourceData) map[string]interface{} {
+	options := map[string]interface{}{"scope": "both", "enable-new": true}
+	list, ok := d.Get("sync").([]interface{})
+	if !ok || len(list) == 0 || list[0] == nil {
+		return options
+	}
+	block := list[0].(map[string]interface{})
+	options["scope"] = block["scope"].(string)
+	options["enable-new"] = block["enable_new"].(bool)
+	vanished := make([]string, 0)
+	for _, e := range block["remove_vanished"].(*schema.Set).List() {
+		vanished = append(vanished, e.(string))
+	}
+	if len(vanished) > 0 {
+		sort.Strings(vanished)
+		options["remove-vanished"] = strings.Join(vanished, ";")
+	}
+	return options
+}
+
+// realmSyncDefaults returns the `sync-defaults-options` of the sync block.
+func realmSyncDefaults(d *schema.ResourceData) string {
+	if list, ok := d.Get("sync").([]interface{}); !ok || len(list) == 0 || list[0] == nil {
+		return ""
+	}
+	options := realmSyncOptions(d)
+	list := []string{"scope=" + options["scope"].(string)}
+	if options["enable-new"].(bool) {
+		list = append(list, "enable-new=1")
+	} else {
+		list = append(list, "enable-new=0")
+	}
+	if v, ok := options["remove-vanished"]; ok {
+		list = append(list, "remove-vanished="+v.(string))
+	}
+	return strings.Join(list, ",")
+}
+
+// realmParseSyncDefaults converts the `sync-defaults-options` of the API to the sync block.
+func realmParseSyncDefaults(raw string) []interface{} {
+	if raw == "" {
+		return nil
+	}
+	block := map[string]interface{}{"scope": "both", "enable_new": true, "remove_vanished": []interface{}{}}
+	for _, e := range strings.Split(raw, ",") {
+		key, value, _ := strings.Cut(e, "=")
+		switch key {
+		case "scope":
+			block["scope"] = value
+		case "enable-new":
+			block["enable_new"] = value != "0"
+		case "remove-vanished":
+			vanished := make([]interface{}, 0)
+			for _, v := range strings.Split(value, ";") {
+				vanished = append(vanished, v)
+			}
+			block["remove_vanished"] = vanished
+		}
+	}
+	return []interface{}{block}
+}
+
+// params returns the parameters of the create or update request, unset options are removed on update.
+func (config realmConfig) params(d *schema.ResourceData, update bool) map[string]interface{} {
+	params := map[string]interface{}{
+		"comment": d.Get("comment").(string),
+		"default": d.Get("default").(bool),
+	}
+	optionParams(d, params, config.options, config.schema, update)
+	for key, option := range config.secrets {
+		if !update || d.HasChange(key) {
+			params[option] = d.Get(key).(string)
+		}
+	}
+	if config.sync {
+		params["sync-defaults-options"] = realmSyncDefaults(d)
+	}
+	deleteEmptyParams(params, update)
+	return params
+}
+
+func (config realmConfig) create(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	realm := d.Get("realm").(string)
+	params := config.params(d, false)
+	params["realm"] = realm
+	params["type"] = config.realmType
+	if err := pconf.Client.Post(ctx, params, "/access/domains"); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(clusterResourceId(realmResourceType, realm))
+	if config.sync && d.Get("sync_trigger").(string) != "" {
+		if err := realmSync(ctx, pconf.Client, d, realm); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return diag.FromErr(config.read(ctx, d, pconf.Client))
+}
+
+func (config realmConfig) readContext(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return diag.FromErr(config.read(ctx, d, pconf.Client))
+}
+
+func (config realmConfig) read(ctx context.Context, d *schema.ResourceData, client *pveSDK.Client) error {
+	_, realm, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+	item, err := listItem(ctx, client, "/access/domains", "realm", realm)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		d.SetId("")
+		return nil
+	}
+	if realmType := itemValue(item, "type"); realmType != config.realmType {
+		return fmt.Errorf("realm '%s' is of type '%s' instead of '%s'", realm, realmType, config.realmType)
+	}
+	item, err = client.GetItemConfigMapStringInterface(ctx, "/access/domains/"+realm, "realm", "CONFIG")
+	if err != nil {
+		return err
+	}
+	d.Set("realm", realm)
+	d.Set("comment", itemValue(item, "comment"))
+	d.Set("default", itemValue(item, "default") == "1")
+	optionRead(d, item, config.options, config.schema)
+	if config.sync {
+		d.Set("sync", realmParseSyncDefaults(itemValue(item, "sync-defaults-options")))
+	}
+	return nil
+}
+
+func (config realmConfig) update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, realm, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err = pconf.Client.Put(ctx, config.params(d, true), "/access/domains/"+realm); err != nil {
+		return diag.FromErr(err)
+	}
+	if config.sync && d.HasChange("sync_trigger") && d.Get("sync_trigger").(string) != "" {
+		if err = realmSync(ctx, pconf.Client, d, realm); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return diag.FromErr(config.read(ctx, d, pconf.Client))
+}
+
+func realmDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, realm, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(pconf.Client.Delete(ctx, "/access/domains/"+realm))
+}
+
+// realmSync syncs the users and groups of the realm with the directory and waits for the sync to finish.
+func realmSync(ctx context.Context, client *pveSDK.Client, d *schema.ResourceData, realm string) error {
+	_, err := client.PostWithTask(ctx, realmSyncOptions(d), "/access/domains/"+realm+"/sync")
+	return err
+}
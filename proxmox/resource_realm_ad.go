@@ -0,0 +1,32 @@
+package proxmox
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceRealmAd() *schema.Resource {
+	config := realmConfig{
+		realmType: "ad",
+		options: map[string]string{
+			"domain":  "domain",
+			"base_dn": "base_dn",
+		},
+		secrets: map[string]string{"bind_password": "password"},
+		sync:    true,
+	}
+	for k, v := range realmDirectoryOptions {
+		config.options[k] = v
+	}
+	typeSchema := realmDirectorySchema()
+	typeSchema["domain"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		Description: "The AD domain, e.g. `example.com`.",
+	}
+	typeSchema["base_dn"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "The base DN of the users, only needed to sync users and groups.",
+	}
+	return config.resource(typeSchema)
+}
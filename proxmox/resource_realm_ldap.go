@@ -0,0 +1,33 @@
+package proxmox
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceRealmLdap() *schema.Resource {
+	config := realmConfig{
+		realmType: "ldap",
+		options: map[string]string{
+			"base_dn":        "base_dn",
+			"user_attribute": "user_attr",
+		},
+		secrets: map[string]string{"bind_password": "password"},
+		sync:    true,
+	}
+	for k, v := range realmDirectoryOptions {
+		config.options[k] = v
+	}
+	typeSchema := realmDirectorySchema()
+	typeSchema["base_dn"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		Description: "The base DN of the users, e.g. `ou=people,dc=example,dc=com`.",
+	}
+	typeSchema["user_attribute"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Default:     "uid",
+		Description: "The LDAP attribute that holds the user name.",
+	}
+	return config.resource(typeSchema)
+}
@@ -0,0 +1,84 @@
+package proxmox
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceRealmOpenID() *schema.Resource {
+	config := realmConfig{
+		realmType: "openid",
+		options: map[string]string{
+			"issuer_url":        "issuer-url",
+			"client_id":         "client-id",
+			"username_claim":    "username-claim",
+			"autocreate":        "autocreate",
+			"scopes":            "scopes",
+			"prompt":            "prompt",
+			"acr_values":        "acr-values",
+			"groups_claim":      "groups-claim",
+			"groups_autocreate": "groups-autocreate",
+			"groups_overwrite":  "groups-overwrite",
+		},
+		secrets: map[string]string{"client_key": "client-key"},
+	}
+	return config.resource(map[string]*schema.Schema{
+		"issuer_url": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+			Description:  "The URL of the OpenID Connect provider.",
+		},
+		"client_id": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"client_key": {
+			Type:      schema.TypeString,
+			Optional:  true,
+			Sensitive: true,
+		},
+		"username_claim": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			ForceNew:    true,
+			Description: "The claim the user name is taken from, e.g. `email`. Defaults to `subject` and can not be changed after the realm is created.",
+		},
+		"autocreate": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Create users that do not exist yet when they log in.",
+		},
+		"scopes": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  "email profile",
+		},
+		"prompt": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"acr_values": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"groups_claim": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The claim the groups of the user are taken from.",
+		},
+		"groups_autocreate": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Create groups that do not exist yet when a user logs in.",
+		},
+		"groups_overwrite": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Replace the groups of the user with the groups of the claim on every login.",
+		},
+	})
+}
@@ -0,0 +1,116 @@
+package proxmox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResourceRealmLdap_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceRealmLdap()
+
+	config := map[string]any{
+		"realm":         "corp",
+		"server":        "ldap.example.com",
+		"base_dn":       "ou=people,dc=example,dc=com",
+		"bind_dn":       "cn=pve,dc=example,dc=com",
+		"bind_password": "secret",
+		"mode":          "ldaps",
+		"sync_trigger":  "1",
+		"sync":          []any{map[string]any{"scope": "groups", "remove_vanished": []any{"entry", "acl"}}}}
+	d := testFakeCreate(t, r, meta, config)
+	require.Equal(t, "realm/corp", d.Id())
+	realm, ok := fake.Realm("corp")
+	require.True(t, ok)
+	require.Equal(t, "ldap", realm["type"])
+	require.Equal(t, "secret", realm["password"])
+	require.Equal(t, "ldap.example.com", realm["server1"])
+	require.Equal(t, "scope=groups,enable-new=1,remove-vanished=acl;entry", realm["sync-defaults-options"])
+	require.Equal(t, []string{"corp:scope=groups,enable-new=1,remove-vanished=acl;entry"}, fake.RealmSyncs())
+	require.Equal(t, "uid", d.Get("user_attribute"))
+	require.True(t, d.Get("case_sensitive").(bool))
+	require.Equal(t, "groups", d.Get("sync.0.scope"))
+	require.Equal(t, "secret", d.Get("bind_password"))
+
+	// Only a change of the trigger starts a sync.
+	config["fallback_server"] = "ldap2.example.com"
+	delete(config, "bind_dn")
+	d = testFakeUpdate(t, r, meta, d, config)
+	realm, _ = fake.Realm("corp")
+	require.Equal(t, "ldap2.example.com", realm["server2"])
+	require.NotContains(t, realm, "bind_dn")
+	require.Equal(t, "secret", realm["password"])
+	require.Len(t, fake.RealmSyncs(), 1)
+
+	// A sync that skipped some entries finishes with warnings, which is not an error.
+	fake.FailTask("auth-realm-sync", "WARNINGS: 2")
+	config["sync_trigger"] = "2"
+	config["bind_password"] = "rotated"
+	d = testFakeUpdate(t, r, meta, d, config)
+	realm, _ = fake.Realm("corp")
+	require.Equal(t, "rotated", realm["password"])
+	require.Len(t, fake.RealmSyncs(), 2)
+
+	testFakeDelete(t, r, meta, d)
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "", d.Id())
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceRealmAd_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceRealmAd()
+
+	d := testFakeCreate(t, r, meta, map[string]any{
+		"realm":   "ad",
+		"server":  "dc1.example.com",
+		"domain":  "example.com",
+		"default": true})
+	realm, _ := fake.Realm("ad")
+	require.Equal(t, "ad", realm["type"])
+	require.Equal(t, "1", realm["default"])
+	require.Empty(t, fake.RealmSyncs())
+	require.Empty(t, d.Get("sync"))
+
+	// A realm of another type is not read as an AD realm.
+	d.SetId(clusterResourceId(realmResourceType, "pve"))
+	require.True(t, r.ReadContext(context.Background(), d, meta).HasError())
+	d.SetId(clusterResourceId(realmResourceType, "ad"))
+
+	testFakeDelete(t, r, meta, d)
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceRealmOpenID_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceRealmOpenID()
+
+	config := map[string]any{
+		"realm":          "sso",
+		"issuer_url":     "https://auth.example.com/realms/pve",
+		"client_id":      "proxmox",
+		"client_key":     "client-secret",
+		"username_claim": "email",
+		"autocreate":     true}
+	d := testFakeCreate(t, r, meta, config)
+	realm, _ := fake.Realm("sso")
+	require.Equal(t, "openid", realm["type"])
+	require.Equal(t, "client-secret", realm["client-key"])
+	require.Equal(t, "email", realm["username-claim"])
+	require.Equal(t, "email profile", d.Get("scopes"))
+	require.True(t, d.Get("autocreate").(bool))
+
+	config["groups_claim"] = "groups"
+	d = testFakeUpdate(t, r, meta, d, config)
+	realm, _ = fake.Realm("sso")
+	require.Equal(t, "groups", realm["groups-claim"])
+	require.Equal(t, "groups", d.Get("groups_claim"))
+
+	testFakeDelete(t, r, meta, d)
+	testFakeUnknown(t, fake)
+}
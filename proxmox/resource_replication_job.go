@@ -0,0 +1,264 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const replicationJobResourceType = "replication_job"
+
+var replicationJobOptions = map[string]string{
+	"schedule": "schedule",
+	"comment":  "comment",
+}
+
+func resourceReplicationJob() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceReplicationJobCreate,
+		ReadContext:   resourceReplicationJobRead,
+		UpdateContext: resourceReplicationJobUpdate,
+		DeleteContext: resourceReplicationJobDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"guest": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the guest in the format <node>/<type>/<vmid>.",
+			},
+			"target": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the disks of the guest are replicated to.",
+			},
+			"job_number": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The number of the job of the guest, the lowest free number is used when it is not set.",
+			},
+			"schedule": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "*/15",
+				Description: "When the job runs, in the calendar event format of systemd.",
+			},
+			"rate": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				ValidateFunc: validation.FloatAtLeast(1),
+				Description:  "The bandwidth limit in MB/s, the bandwidth is not limited when it is not set.",
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"last_sync": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the last successful sync started, empty when the job never synced.",
+			},
+			"next_sync": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the job runs next.",
+			},
+			"fail_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of runs that failed since the last successful sync.",
+			},
+			"error": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The error of the last failed run.",
+			},
+		},
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+// replicationJobParams returns the parameters of the create or update request, unset options are removed on update.
+func replicationJobParams(d *schema.ResourceData, update bool) map[string]interface{} {
+	params := map[string]interface{}{"rate": ""}
+	optionParams(d, params, replicationJobOptions, resourceReplicationJob().Schema, update)
+	if rate := d.Get("rate").(float64); rate > 0 {
+		params["rate"] = rate
+	}
+	deleteEmptyParams(params, update)
+	return params
+}
+
+// replicationGuest returns the guest with the VMID on the node it is on now, ok is false when the guest no longer exists.
+func replicationGuest(ctx context.Context, client *pveSDK.Client, vmid pveSDK.GuestID) (guest id.Guest, ok bool, err error) {
+	if ok, err = vmid.Exists(ctx, client); err != nil || !ok {
+		return
+	}
+	vmr := pveSDK.NewVmRef(vmid)
+	if err = client.CheckVmRef(ctx, vmr); err != nil {
+		return
+	}
+	return id.Guest{ID: vmid, Node: vmr.Node(), Type: vmr.GetVmType().String()}, true, nil
+}
+
+// replicationJobNumber returns the lowest job number that the guest does not use yet.
+func replicationJobNumber(ctx context.Context, client *pveSDK.Client, vmid pveSDK.GuestID) (int, error) {
+	list, err := client.GetItemListInterfaceArray(ctx, "/cluster/replication")
+	if err != nil {
+		return 0, err
+	}
+	used := map[string]struct{}{}
+	for _, e := range list {
+		if item, ok := e.(map[string]interface{}); ok && itemValue(item, "guest") == vmid.String() {
+			used[itemValue(item, "jobnum")] = struct{}{}
+		}
+	}
+	number := 0
+	for {
+		if _, ok := used[strconv.Itoa(number)]; !ok {
+			return number, nil
+		}
+		number++
+	}
+}
+
+// replicationTime formats a unix timestamp of the replication status, zero means never.
+func replicationTime(item map[string]interface{}, key string) string {
+	seconds, _ := strconv.ParseInt(itemValue(item, key), 10, 64)
+	if seconds <= 0 {
+		return ""
+	}
+	return time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+}
+
+func resourceReplicationJobCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	var guestID id.Guest
+	if err := guestID.Parse(d.Get("guest").(string)); err != nil {
+		return diag.FromErr(err)
+	}
+	guest, ok, err := replicationGuest(ctx, pconf.Client, guestID.ID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if !ok || guest.Type != guestID.Type {
+		return diag.Errorf("guest '%s' does not exist", guestID.String())
+	}
+	number := d.Get("job_number").(int)
+	// GetOk can't tell job number 0 from an unset job number, only the config can.
+	if config := d.GetRawConfig(); config.IsNull() || config.GetAttr("job_number").IsNull() {
+		if number, err = replicationJobNumber(ctx, pconf.Client, guestID.ID); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	jobID := fmt.Sprintf("%s-%d", guestID.ID.String(), number)
+	params := replicationJobParams(d, false)
+	params["id"] = jobID
+	params["type"] = "local"
+	params["target"] = d.Get("target").(string)
+	if err = pconf.Client.Post(ctx, params, "/cluster/replication"); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(clusterResourceId(replicationJobResourceType, jobID))
+	return _resourceReplicationJobRead(ctx, d, pconf.Client)
+}
+
+func resourceReplicationJobRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceReplicationJobRead(ctx, d, pconf.Client)
+}
+
+func _resourceReplicationJobRead(ctx context.Context, d *schema.ResourceData, client *pveSDK.Client) diag.Diagnostics {
+	_, jobID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return diag.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+	item, err := listItem(ctx, client, "/cluster/replication", "id", jobID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if item == nil || itemValue(item, "remove_job") != "" {
+		return diag.Diagnostics{resourceDriftDeletionDiagnostic(d)}
+	}
+	vmid, err := strconv.Atoi(itemValue(item, "guest"))
+	if err != nil {
+		return diag.Errorf("replication job '%s' has no guest", jobID)
+	}
+	jobNumber, _ := strconv.Atoi(itemValue(item, "jobnum"))
+	rate, _ := strconv.ParseFloat(itemValue(item, "rate"), 64)
+	d.Set("target", itemValue(item, "target"))
+	d.Set("job_number", jobNumber)
+	d.Set("rate", rate)
+	optionRead(d, item, replicationJobOptions, resourceReplicationJob().Schema)
+
+	// The status is kept by the node the guest is on, which changes when the guest is migrated.
+	var status map[string]interface{}
+	guest, ok, err := replicationGuest(ctx, client, pveSDK.GuestID(vmid))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if ok {
+		if d.Get("guest").(string) == "" {
+			d.Set("guest", guest.String())
+		}
+		if status, err = listItem(ctx, client, "/nodes/"+guest.Node.String()+"/replication", "id", jobID); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if status == nil {
+		status = map[string]interface{}{}
+	}
+	failCount, _ := strconv.Atoi(itemValue(status, "fail_count"))
+	d.Set("last_sync", replicationTime(status, "last_sync"))
+	d.Set("next_sync", replicationTime(status, "next_sync"))
+	d.Set("fail_count", failCount)
+	d.Set("error", itemValue(status, "error"))
+	return nil
+}
+
+func resourceReplicationJobUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, jobID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err = pconf.Client.Put(ctx, replicationJobParams(d, true), "/cluster/replication/"+jobID); err != nil {
+		return diag.FromErr(err)
+	}
+	return _resourceReplicationJobRead(ctx, d, pconf.Client)
+}
+
+func resourceReplicationJobDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, jobID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// PVE marks the job for removal, the node of the guest removes the replicated volumes from the target.
+	return diag.FromErr(pconf.Client.Delete(ctx, "/cluster/replication/"+jobID))
+}
@@ -0,0 +1,111 @@
+package proxmox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResourceReplicationJob_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t, "pve1", "pve2", "pve3", "pve4")
+	fake.AddStorage("local-zfs", "zfspool", false, "images", "rootdir")
+	fake.AddGuest("pve1", "qemu", 100, map[string]string{"name": "db", "scsi0": "local-zfs:10", "ide2": "local:iso/debian.iso,media=cdrom"})
+	fake.AddGuest("pve1", "lxc", 101, map[string]string{"hostname": "web", "rootfs": "local-lvm:8"})
+	meta := testFakeMeta(t, fake)
+	r := resourceReplicationJob()
+
+	config := map[string]any{"guest": "pve1/qemu/100", "target": "pve2", "rate": 50.0, "comment": "DR"}
+	d := testFakeCreate(t, r, meta, config)
+	require.Equal(t, "replication_job/100-0", d.Id())
+	job, ok := fake.ReplicationJob("100-0")
+	require.True(t, ok)
+	require.Equal(t, "pve2", job["target"])
+	require.Equal(t, "*/15", job["schedule"])
+	require.Equal(t, "50", job["rate"])
+	require.Equal(t, 0, d.Get("job_number"))
+	require.Equal(t, "", d.Get("last_sync"))
+
+	// The status of the job is read from the node of the guest.
+	fake.RunReplication("100-0", "")
+	testFakeRead(t, r, meta, d)
+	require.NotEmpty(t, d.Get("last_sync"))
+	require.Equal(t, 0, d.Get("fail_count"))
+	fake.RunReplication("100-0", "no space left on device")
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, 1, d.Get("fail_count"))
+	require.Equal(t, "no space left on device", d.Get("error"))
+
+	config["schedule"] = "*/5"
+	delete(config, "rate")
+	delete(config, "comment")
+	d = testFakeUpdate(t, r, meta, d, config)
+	job, _ = fake.ReplicationJob("100-0")
+	require.Equal(t, "*/5", job["schedule"])
+	require.NotContains(t, job, "rate")
+	require.NotContains(t, job, "comment")
+
+	// A second job of the guest gets the next free number.
+	second := testFakeCreate(t, r, meta, map[string]any{"guest": "pve1/qemu/100", "target": "pve3"})
+	require.Equal(t, "replication_job/100-1", second.Id())
+
+	// Job number 0 set in the config is not replaced by the next free number.
+	_, diags := testFakeCreateRaw(t, r, meta, map[string]any{"guest": "pve1/qemu/100", "target": "pve4", "job_number": 0})
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[0].Summary, "100-0")
+	third, diags := testFakeCreateRaw(t, r, meta, map[string]any{"guest": "pve1/qemu/100", "target": "pve4"})
+	require.False(t, diags.HasError(), diags)
+	require.Equal(t, "replication_job/100-2", third.Id())
+	testFakeDelete(t, r, meta, third)
+
+	// Guests with disks that are not on ZFS cannot be replicated, nor can a guest be replicated to its own node.
+	for _, config := range []map[string]any{
+		{"guest": "pve1/lxc/101", "target": "pve2"},
+		{"guest": "pve1/qemu/100", "target": "pve1"},
+		{"guest": "pve1/qemu/100", "target": "pve2", "job_number": 5},
+		{"guest": "pve1/qemu/102", "target": "pve2"},
+	} {
+		d := schema.TestResourceDataRaw(t, r.Schema, config)
+		require.True(t, r.CreateContext(context.Background(), d, meta).HasError(), config)
+	}
+
+	// Imported jobs take the guest from the API.
+	imported := schema.TestResourceDataRaw(t, r.Schema, map[string]any{})
+	imported.SetId("replication_job/100-1")
+	testFakeRead(t, r, meta, imported)
+	require.Equal(t, "pve1/qemu/100", imported.Get("guest"))
+	require.Equal(t, "pve3", imported.Get("target"))
+
+	testFakeDelete(t, r, meta, second)
+	testFakeDelete(t, r, meta, d)
+	_, ok = fake.ReplicationJob("100-0")
+	require.False(t, ok)
+	diags = r.ReadContext(context.Background(), d, meta)
+	require.False(t, diags.HasError())
+	require.Equal(t, "", d.Id())
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceReplicationJob_Validation(t *testing.T) {
+	r := resourceReplicationJob()
+	tests := []struct {
+		name   string
+		config map[string]any
+	}{
+		{name: "rate", config: map[string]any{"guest": "pve/qemu/100", "target": "pve2", "rate": 0.5}},
+		{name: "job number", config: map[string]any{"guest": "pve/qemu/100", "target": "pve2", "job_number": -1}},
+		{name: "without target", config: map[string]any{"guest": "pve/qemu/100"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := terraform.NewResourceConfigRaw(test.config)
+			diags := r.Validate(config)
+			if !diags.HasError() {
+				_, err := r.Diff(context.Background(), nil, config, nil)
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const roleResourceType = "role"
+
+var rxRoleID = regexp.MustCompile(`^[A-Za-z0-9.\-_]+$`)
+
+func resourceRole() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceRoleCreate,
+		ReadContext:   resourceRoleRead,
+		UpdateContext: resourceRoleUpdate,
+		DeleteContext: resourceRoleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(rxRoleID, "may only contain letters, digits, '.', '-' and '_'"),
+				Description:  "The name of the role.",
+			},
+			"privileges": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The privileges of the role, e.g. `VM.Audit`.",
+			},
+		},
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+func rolePrivileges(d *schema.ResourceData) string {
+	privs := make([]string, 0)
+	for _, e := range d.Get("privileges").(*schema.Set).List() {
+		privs = append(privs, e.(string))
+	}
+	sort.Strings(privs)
+	return strings.Join(privs, ",")
+}
+
+func resourceRoleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	name := d.Get("name").(string)
+	params := map[string]interface{}{
+		"roleid": name,
+		"privs":  rolePrivileges(d),
+	}
+	if err := pconf.Client.Post(ctx, params, "/access/roles"); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(clusterResourceId(roleResourceType, name))
+	return diag.FromErr(_resourceRoleRead(ctx, d, pconf.Client))
+}
+
+func resourceRoleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return diag.FromErr(_resourceRoleRead(ctx, d, pconf.Client))
+}
+
+func _resourceRoleRead(ctx context.Context, d *schema.ResourceData, client *pveSDK.Client) error {
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+	item, err := listItem(ctx, client, "/access/roles", "roleid", name)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		d.SetId("")
+		return nil
+	}
+	d.Set("name", name)
+	d.Set("privileges", splitPrivileges(itemValue(item, "privs")))
+	return nil
+}
+
+// splitPrivileges splits the privilege list of the API, which may be separated by commas, semicolons or spaces.
+func splitPrivileges(raw string) []string {
+	return strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || r == ';' || r == ' ' })
+}
+
+func resourceRoleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err = pconf.Client.Put(ctx, map[string]interface{}{"privs": rolePrivileges(d)}, "/access/roles/"+name); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(_resourceRoleRead(ctx, d, pconf.Client))
+}
+
+func resourceRoleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(pconf.Client.Delete(ctx, "/access/roles/"+name))
+}
@@ -0,0 +1,29 @@
+package proxmox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResourceRole_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceRole()
+
+	d := testFakeCreate(t, r, meta, map[string]any{"name": "Deploy", "privileges": []any{"VM.PowerMgmt", "VM.Audit"}})
+	require.Equal(t, "role/Deploy", d.Id())
+	privs, ok := fake.AccessRole("Deploy")
+	require.True(t, ok)
+	require.Equal(t, []string{"VM.Audit", "VM.PowerMgmt"}, privs)
+
+	d = testFakeUpdate(t, r, meta, d, map[string]any{"name": "Deploy", "privileges": []any{"VM.Audit", "VM.Console"}})
+	privs, _ = fake.AccessRole("Deploy")
+	require.Equal(t, []string{"VM.Audit", "VM.Console"}, privs)
+	require.Equal(t, 2, d.Get("privileges.#"))
+
+	testFakeDelete(t, r, meta, d)
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "", d.Id())
+	testFakeUnknown(t, fake)
+}
@@ -0,0 +1,154 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// rxSDNID matches the IDs of SDN zones, VNets and controllers.
+var rxSDNID = regexp.MustCompile(`^[a-z][a-z0-9]*[a-z0-9]$`)
+
+// sdnConfig describes how the arguments of an SDN zone, VNet or controller resource map to the options of the API.
+// Changes are only written to the SDN config, the proxmox_sdn_apply resource applies them to the nodes.
+type sdnConfig struct {
+	// kind is the path of the objects below /cluster/sdn, e.g. "zones".
+	kind string
+	// idKey is the argument and the API parameter that holds the ID of the object, e.g. "zone".
+	idKey string
+	// sdnType is the type of the object in the API, e.g. "vlan". VNets do not have a type.
+	sdnType string
+	// options maps the arguments to the API options.
+	options map[string]string
+	// lists maps the set arguments to the API options that hold a comma separated list.
+	lists map[string]string
+	// schema is the schema of the resource, it is used to convert the options of the API.
+	schema map[string]*schema.Schema
+}
+
+// resource returns the resource of the SDN object, s holds all arguments including the ID.
+func (config sdnConfig) resource(s map[string]*schema.Schema) *schema.Resource {
+	config.schema = s
+	return &schema.Resource{
+		CreateContext: config.create,
+		ReadContext:   config.readContext,
+		UpdateContext: config.update,
+		DeleteContext: config.delete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema:   config.schema,
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+// sdnIDSchema returns the schema of the ID of a zone or VNet, which PVE limits to 8 characters.
+func sdnIDSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+		ForceNew: true,
+		ValidateFunc: validation.All(
+			validation.StringLenBetween(2, 8),
+			validation.StringMatch(rxSDNID, "must start with a letter and only contain lowercase letters and digits"),
+		),
+		Description: description,
+	}
+}
+
+func (config sdnConfig) resourceType() string {
+	return "sdn_" + config.idKey
+}
+
+// params returns the parameters of the create or update request, unset options are removed on update.
+func (config sdnConfig) params(d *schema.ResourceData, update bool) map[string]interface{} {
+	params := map[string]interface{}{}
+	optionParams(d, params, config.options, config.schema, update)
+	for key, option := range config.lists {
+		params[option] = storageSetList(d.Get(key).(*schema.Set))
+	}
+	deleteEmptyParams(params, update)
+	return params
+}
+
+func (config sdnConfig) create(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	id := d.Get(config.idKey).(string)
+	params := config.params(d, false)
+	params[config.idKey] = id
+	if config.sdnType != "" {
+		params["type"] = config.sdnType
+	}
+	if err := pconf.Client.Post(ctx, params, "/cluster/sdn/"+config.kind); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(clusterResourceId(config.resourceType(), id))
+	return diag.FromErr(config.read(ctx, d, pconf.Client))
+}
+
+func (config sdnConfig) readContext(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return diag.FromErr(config.read(ctx, d, pconf.Client))
+}
+
+func (config sdnConfig) read(ctx context.Context, d *schema.ResourceData, client *pveSDK.Client) error {
+	_, id, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+	item, err := listItem(ctx, client, "/cluster/sdn/"+config.kind, config.idKey, id)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		d.SetId("")
+		return nil
+	}
+	if sdnType := itemValue(item, "type"); config.sdnType != "" && sdnType != config.sdnType {
+		return fmt.Errorf("%s '%s' is of type '%s' instead of '%s'", config.idKey, id, sdnType, config.sdnType)
+	}
+	d.Set(config.idKey, id)
+	optionRead(d, item, config.options, config.schema)
+	for key, option := range config.lists {
+		d.Set(key, storageParseList(itemValue(item, option)))
+	}
+	return nil
+}
+
+func (config sdnConfig) update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, id, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err = pconf.Client.Put(ctx, config.params(d, true), "/cluster/sdn/"+config.kind+"/"+id); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(config.read(ctx, d, pconf.Client))
+}
+
+func (config sdnConfig) delete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, id, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(pconf.Client.Delete(ctx, "/cluster/sdn/"+config.kind+"/"+id))
+}
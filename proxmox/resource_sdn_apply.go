@@ -0,0 +1,94 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const sdnApplyResourceType = "sdn_apply"
+
+func resourceSDNApply() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSDNApplyCreate,
+		ReadContext:   resourceSDNApplyRead,
+		DeleteContext: resourceSDNApplyDelete,
+
+		Schema: map[string]*schema.Schema{
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Applies the SDN config again when any of the values change.",
+			},
+		},
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+func resourceSDNApplyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	exitStatus, err := pconf.Client.ApplySDN(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(clusterResourceId(sdnApplyResourceType, "cluster"))
+	return taskWarnings(exitStatus, "applied the SDN config with warnings")
+}
+
+// resourceSDNApplyRead removes the resource from the state when the SDN config has pending changes, so the next plan applies them.
+func resourceSDNApplyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	pending, err := sdnPending(ctx, pconf.Client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if pending {
+		d.SetId("")
+	}
+	return nil
+}
+
+// resourceSDNApplyDelete only removes the resource from the state, the applied SDN config stays as it is.
+func resourceSDNApplyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
+
+// sdnPending reports whether any zone, VNet, subnet or controller has changes that are not applied.
+func sdnPending(ctx context.Context, client *pveSDK.Client) (bool, error) {
+	urls := []string{"/cluster/sdn/zones", "/cluster/sdn/controllers"}
+	vnets, err := client.GetItemListInterfaceArray(ctx, "/cluster/sdn/vnets?pending=1")
+	if err != nil {
+		return false, err
+	}
+	for _, e := range vnets {
+		if item, ok := e.(map[string]interface{}); ok {
+			if itemValue(item, "state") != "" {
+				return true, nil
+			}
+			urls = append(urls, fmt.Sprintf("/cluster/sdn/vnets/%s/subnets", itemValue(item, "vnet")))
+		}
+	}
+	for _, url := range urls {
+		list, err := client.GetItemListInterfaceArray(ctx, url+"?pending=1")
+		if err != nil {
+			return false, err
+		}
+		for _, e := range list {
+			if item, ok := e.(map[string]interface{}); ok && itemValue(item, "state") != "" {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,142 @@
+package proxmox
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// sdnController returns the resource of a controller type, typeSchema holds the arguments of the controller type.
+func sdnController(config sdnConfig, typeSchema map[string]*schema.Schema) *schema.Resource {
+	config.kind = "controllers"
+	config.idKey = "controller"
+	s := map[string]*schema.Schema{
+		"controller": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringMatch(rxSDNID, "must start with a letter and only contain lowercase letters and digits"),
+			Description:  "The ID of the controller.",
+		},
+	}
+	for k, v := range typeSchema {
+		s[k] = v
+	}
+	return config.resource(s)
+}
+
+func sdnControllerASNSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeInt,
+		Required:     true,
+		ValidateFunc: validation.IntAtLeast(1),
+		Description:  "The autonomous system number of the nodes.",
+	}
+}
+
+func sdnControllerPeersSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Required:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.IsIPAddress},
+		Description: description,
+	}
+}
+
+func resourceSDNControllerEvpn() *schema.Resource {
+	return sdnController(sdnConfig{
+		sdnType: "evpn",
+		options: map[string]string{"asn": "asn"},
+		lists:   map[string]string{"peers": "peers"},
+	}, map[string]*schema.Schema{
+		"asn":   sdnControllerASNSchema(),
+		"peers": sdnControllerPeersSchema("The addresses of the nodes that exchange the EVPN routes."),
+	})
+}
+
+func resourceSDNControllerBgp() *schema.Resource {
+	return sdnController(sdnConfig{
+		sdnType: "bgp",
+		options: map[string]string{
+			"node":                        "node",
+			"asn":                         "asn",
+			"ebgp":                        "ebgp",
+			"ebgp_multihop":               "ebgp-multihop",
+			"loopback":                    "loopback",
+			"bgp_multipath_as_path_relax": "bgp-multipath-as-path-relax",
+		},
+		lists: map[string]string{"peers": "peers"},
+	}, map[string]*schema.Schema{
+		"node": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The node the BGP controller runs on.",
+		},
+		"asn":   sdnControllerASNSchema(),
+		"peers": sdnControllerPeersSchema("The addresses of the BGP peers of the node."),
+		"ebgp": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Peer with routers of another autonomous system.",
+		},
+		"ebgp_multihop": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntBetween(1, 255),
+			Description:  "The number of hops to the external BGP peers.",
+		},
+		"loopback": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The interface whose address is used as the source of the BGP sessions.",
+		},
+		"bgp_multipath_as_path_relax": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Balance the traffic over routes from different autonomous systems.",
+		},
+	})
+}
+
+func resourceSDNControllerIsis() *schema.Resource {
+	return sdnController(sdnConfig{
+		sdnType: "isis",
+		options: map[string]string{
+			"node":        "node",
+			"isis_domain": "isis-domain",
+			"isis_net":    "isis-net",
+			"loopback":    "loopback",
+		},
+		lists: map[string]string{"isis_interfaces": "isis-ifaces"},
+	}, map[string]*schema.Schema{
+		"node": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The node the IS-IS controller runs on.",
+		},
+		"isis_domain": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The IS-IS domain.",
+		},
+		"isis_net": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The network entity title of the node, e.g. `49.0001.1921.6800.2001.00`.",
+		},
+		"isis_interfaces": {
+			Type:        schema.TypeSet,
+			Required:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "The interfaces IS-IS runs on.",
+		},
+		"loopback": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The interface whose address is used as the source of the IS-IS sessions.",
+		},
+	})
+}
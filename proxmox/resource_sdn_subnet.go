@@ -0,0 +1,238 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var sdnSubnetOptions = map[string]string{
+	"gateway":         "gateway",
+	"snat":            "snat",
+	"dns_zone_prefix": "dnszoneprefix",
+	"dhcp_dns_server": "dhcp-dns-server",
+}
+
+func resourceSDNSubnet() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSDNSubnetCreate,
+		ReadContext:   resourceSDNSubnetRead,
+		UpdateContext: resourceSDNSubnetUpdate,
+		DeleteContext: resourceSDNSubnetDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema:   sdnSubnetSchema(),
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+func sdnSubnetSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"vnet": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The VNet of the subnet.",
+		},
+		"cidr": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.IsCIDRNetwork(0, 128),
+			Description:  "The network of the subnet in CIDR notation.",
+		},
+		"subnet": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The ID PVE gives the subnet, `<zone>-<ip>-<mask>`.",
+		},
+		"zone": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The zone of the VNet.",
+		},
+		"gateway": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.IsIPAddress,
+			Description:  "The address of the gateway of the subnet, it is configured on the VNet in simple and EVPN zones.",
+		},
+		"snat": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Masquerade the traffic of the subnet that leaves the node, in simple and EVPN zones.",
+		},
+		"dns_zone_prefix": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The prefix of the DNS domain of the zone, the records of the guests get `<hostname>.<prefix>.<domain>`.",
+		},
+		"dhcp_dns_server": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.IsIPAddress,
+			Description:  "The DNS server the DHCP server hands out.",
+		},
+		"dhcp_range": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"start_address": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.IsIPAddress,
+					},
+					"end_address": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.IsIPAddress,
+					},
+				},
+			},
+			Description: "The ranges the DHCP server of the zone hands out addresses from.",
+		},
+	}
+}
+
+// sdnSubnetParams returns the parameters of the create or update request, unset options are removed on update.
+func sdnSubnetParams(d *schema.ResourceData, update bool) map[string]interface{} {
+	params := map[string]interface{}{}
+	optionParams(d, params, sdnSubnetOptions, sdnSubnetSchema(), update)
+	deleteEmptyParams(params, update)
+	ranges := make([]string, 0)
+	for _, e := range d.Get("dhcp_range").([]interface{}) {
+		if r, ok := e.(map[string]interface{}); ok {
+			ranges = append(ranges, "start-address="+r["start_address"].(string)+",end-address="+r["end_address"].(string))
+		}
+	}
+	if len(ranges) > 0 {
+		params["dhcp-range"] = ranges
+	} else if deleteKeys, ok := params["delete"].(string); ok {
+		params["delete"] = deleteKeys + ",dhcp-range"
+	} else if update {
+		params["delete"] = "dhcp-range"
+	}
+	return params
+}
+
+// sdnSubnetParseRanges parses the DHCP ranges of the API, which are returned as objects or in the `start-address=...,end-address=...` format.
+func sdnSubnetParseRanges(raw interface{}) []interface{} {
+	list, _ := raw.([]interface{})
+	ranges := make([]interface{}, 0, len(list))
+	for _, e := range list {
+		r, ok := e.(map[string]interface{})
+		if !ok {
+			r = map[string]interface{}{}
+			for _, kv := range strings.Split(fmt.Sprint(e), ",") {
+				k, v, _ := strings.Cut(kv, "=")
+				r[k] = v
+			}
+		}
+		ranges = append(ranges, map[string]interface{}{
+			"start_address": itemValue(r, "start-address"),
+			"end_address":   itemValue(r, "end-address"),
+		})
+	}
+	return ranges
+}
+
+func resourceSDNSubnetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	vnet := d.Get("vnet").(string)
+	cidr := d.Get("cidr").(string)
+	params := sdnSubnetParams(d, false)
+	params["subnet"] = cidr
+	params["type"] = "subnet"
+	url := "/cluster/sdn/vnets/" + vnet + "/subnets"
+	if err := pconf.Client.Post(ctx, params, url); err != nil {
+		return diag.FromErr(err)
+	}
+	// PVE derives the ID of the subnet from the zone of the VNet.
+	item, err := listItem(ctx, pconf.Client, url, "cidr", cidr)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if item == nil {
+		return diag.Errorf("subnet '%s' of vnet '%s' was not found after creating it", cidr, vnet)
+	}
+	d.SetId(id.SDNSubnet{VNet: vnet, Subnet: itemValue(item, "subnet")}.String())
+	return diag.FromErr(_resourceSDNSubnetRead(ctx, d, pconf.Client))
+}
+
+func resourceSDNSubnetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return diag.FromErr(_resourceSDNSubnetRead(ctx, d, pconf.Client))
+}
+
+func _resourceSDNSubnetRead(ctx context.Context, d *schema.ResourceData, client *pveSDK.Client) error {
+	var subnet id.SDNSubnet
+	if err := subnet.Parse(d.Id()); err != nil {
+		d.SetId("")
+		return fmt.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+	// Listing the subnets of a VNet that no longer exists fails.
+	vnet, err := listItem(ctx, client, "/cluster/sdn/vnets", "vnet", subnet.VNet)
+	if err != nil {
+		return err
+	}
+	if vnet == nil {
+		d.SetId("")
+		return nil
+	}
+	item, err := listItem(ctx, client, "/cluster/sdn/vnets/"+subnet.VNet+"/subnets", "subnet", subnet.Subnet)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		d.SetId("")
+		return nil
+	}
+	d.Set("vnet", subnet.VNet)
+	d.Set("subnet", subnet.Subnet)
+	d.Set("cidr", itemValue(item, "cidr"))
+	d.Set("zone", itemValue(item, "zone"))
+	d.Set("dhcp_range", sdnSubnetParseRanges(item["dhcp-range"]))
+	optionRead(d, item, sdnSubnetOptions, sdnSubnetSchema())
+	return nil
+}
+
+func resourceSDNSubnetUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	var subnet id.SDNSubnet
+	if err := subnet.Parse(d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := pconf.Client.Put(ctx, sdnSubnetParams(d, true), "/cluster/sdn/vnets/"+subnet.VNet+"/subnets/"+subnet.Subnet); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(_resourceSDNSubnetRead(ctx, d, pconf.Client))
+}
+
+func resourceSDNSubnetDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	var subnet id.SDNSubnet
+	if err := subnet.Parse(d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(pconf.Client.Delete(ctx, "/cluster/sdn/vnets/"+subnet.VNet+"/subnets/"+subnet.Subnet))
+}
@@ -0,0 +1,146 @@
+package proxmox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResourceSDN_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+
+	zone := testFakeCreate(t, resourceSDNZoneSimple(), meta, map[string]any{
+		"zone":  "tenants",
+		"nodes": []any{"pve"},
+		"dhcp":  "dnsmasq"})
+	require.Equal(t, "sdn_zone/tenants", zone.Id())
+	require.Equal(t, "pve", zone.Get("ipam"))
+	vnetConfig := map[string]any{"vnet": "tenant1", "zone": "tenants", "alias": "Tenant 1"}
+	vnet := testFakeCreate(t, resourceSDNVNet(), meta, vnetConfig)
+	require.Equal(t, "sdn_vnet/tenant1", vnet.Id())
+
+	subnetConfig := map[string]any{
+		"vnet":       "tenant1",
+		"cidr":       "10.1.0.0/24",
+		"gateway":    "10.1.0.1",
+		"snat":       true,
+		"dhcp_range": []any{map[string]any{"start_address": "10.1.0.100", "end_address": "10.1.0.200"}}}
+	r := resourceSDNSubnet()
+	subnet := testFakeCreate(t, r, meta, subnetConfig)
+	require.Equal(t, "sdn_subnet/tenant1/tenants-10.1.0.0-24", subnet.Id())
+	require.Equal(t, "tenants", subnet.Get("zone"))
+	require.Equal(t, "10.1.0.100", subnet.Get("dhcp_range.0.start_address"))
+	require.True(t, subnet.Get("snat").(bool))
+
+	// Nothing is applied until the SDN config is applied.
+	require.True(t, fake.SDNPending())
+	apply := testFakeCreate(t, resourceSDNApply(), meta, map[string]any{})
+	require.False(t, fake.SDNPending())
+	testFakeRead(t, resourceSDNApply(), meta, apply)
+	require.Equal(t, "sdn_apply/cluster", apply.Id())
+
+	delete(subnetConfig, "dhcp_range")
+	delete(subnetConfig, "gateway")
+	subnet = testFakeUpdate(t, r, meta, subnet, subnetConfig)
+	config, _ := fake.SDN("subnets", "tenants-10.1.0.0-24")
+	require.NotContains(t, config, "dhcp-range")
+	require.NotContains(t, config, "gateway")
+	require.Empty(t, subnet.Get("dhcp_range"))
+
+	// Pending changes remove the apply resource from the state, so the next plan applies them.
+	testFakeRead(t, resourceSDNApply(), meta, apply)
+	require.Equal(t, "", apply.Id())
+
+	// A reload that finishes with warnings still applied the config.
+	fake.FailTask("reloadnetworkall", "WARNINGS: 1")
+	diags := resourceSDNApply().CreateContext(context.Background(), apply, meta)
+	require.False(t, diags.HasError(), diags)
+	require.Len(t, diags, 1)
+	require.Equal(t, "sdn_apply/cluster", apply.Id())
+	require.False(t, fake.SDNPending())
+
+	// A VNet that still has subnets can not be deleted.
+	require.True(t, resourceSDNVNet().DeleteContext(context.Background(), vnet, meta).HasError())
+	testFakeDelete(t, r, meta, subnet)
+	testFakeDelete(t, resourceSDNVNet(), meta, vnet)
+	testFakeRead(t, r, meta, subnet)
+	require.Equal(t, "", subnet.Id())
+
+	// A zone of another type is not read as a VLAN zone.
+	require.True(t, resourceSDNZoneVlan().ReadContext(context.Background(), zone, meta).HasError())
+	testFakeDelete(t, resourceSDNZoneSimple(), meta, zone)
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceSDNZoneEvpn_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+
+	controller := testFakeCreate(t, resourceSDNControllerEvpn(), meta, map[string]any{
+		"controller": "evpn1",
+		"asn":        65000,
+		"peers":      []any{"10.0.0.2", "10.0.0.1"}})
+	config, _ := fake.SDN("controllers", "evpn1")
+	require.Equal(t, "10.0.0.1,10.0.0.2", config["peers"])
+
+	r := resourceSDNZoneEvpn()
+	zoneConfig := map[string]any{
+		"zone":       "evpn",
+		"controller": "evpn1",
+		"vrf_vxlan":  10000,
+		"exit_nodes": []any{"pve"}}
+	zone := testFakeCreate(t, r, meta, zoneConfig)
+	config, _ = fake.SDN("zones", "evpn")
+	require.Equal(t, "evpn", config["type"])
+	require.Equal(t, "10000", config["vrf-vxlan"])
+	require.Equal(t, "pve", config["exitnodes"])
+	require.False(t, zone.Get("advertise_subnets").(bool))
+
+	zoneConfig["advertise_subnets"] = true
+	delete(zoneConfig, "exit_nodes")
+	zone = testFakeUpdate(t, r, meta, zone, zoneConfig)
+	config, _ = fake.SDN("zones", "evpn")
+	require.Equal(t, "1", config["advertise-subnets"])
+	require.NotContains(t, config, "exitnodes")
+
+	vnet := testFakeCreate(t, resourceSDNVNet(), meta, map[string]any{"vnet": "web", "zone": "evpn", "tag": 11000})
+	require.Equal(t, 11000, vnet.Get("tag"))
+
+	// The controller is still used by the zone.
+	require.True(t, resourceSDNControllerEvpn().DeleteContext(context.Background(), controller, meta).HasError())
+	testFakeDelete(t, resourceSDNVNet(), meta, vnet)
+	testFakeDelete(t, r, meta, zone)
+	testFakeDelete(t, resourceSDNControllerEvpn(), meta, controller)
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceSDN_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource *schema.Resource
+		config   map[string]any
+	}{
+		{name: "zone id too long", resource: resourceSDNZoneSimple(), config: map[string]any{"zone": "tenantzone"}},
+		{name: "zone id uppercase", resource: resourceSDNZoneSimple(), config: map[string]any{"zone": "Tenants"}},
+		{name: "vlan zone without bridge", resource: resourceSDNZoneVlan(), config: map[string]any{"zone": "vlan"}},
+		{name: "qinq protocol", resource: resourceSDNZoneQinQ(), config: map[string]any{"zone": "qinq", "bridge": "vmbr0", "service_vlan": 10, "vlan_protocol": "802.1x"}},
+		{name: "vxlan peer", resource: resourceSDNZoneVxlan(), config: map[string]any{"zone": "vxlan", "peers": []any{"node1"}}},
+		{name: "subnet host address", resource: resourceSDNSubnet(), config: map[string]any{"vnet": "tenant1", "cidr": "10.1.0.1/24"}},
+		{name: "dhcp range address", resource: resourceSDNSubnet(), config: map[string]any{"vnet": "tenant1", "cidr": "10.1.0.0/24",
+			"dhcp_range": []any{map[string]any{"start_address": "10.1.0.100", "end_address": "last"}}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := terraform.NewResourceConfigRaw(test.config)
+			diags := test.resource.Validate(config)
+			if !diags.HasError() {
+				_, err := test.resource.Diff(context.Background(), nil, config, nil)
+				require.Error(t, err)
+			}
+		})
+	}
+}
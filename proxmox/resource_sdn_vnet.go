@@ -0,0 +1,51 @@
+package proxmox
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceSDNVNet() *schema.Resource {
+	config := sdnConfig{
+		kind:  "vnets",
+		idKey: "vnet",
+		options: map[string]string{
+			"zone":          "zone",
+			"alias":         "alias",
+			"tag":           "tag",
+			"vlan_aware":    "vlanaware",
+			"isolate_ports": "isolate-ports",
+		},
+	}
+	return config.resource(map[string]*schema.Schema{
+		"vnet": sdnIDSchema("The ID of the VNet, it is the name of the bridge guests connect their network devices to."),
+		"zone": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The zone of the VNet.",
+		},
+		"alias": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "A description of the VNet.",
+		},
+		"tag": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntBetween(1, 16777215),
+			Description:  "The VLAN tag or VXLAN ID of the VNet, required in all zones but simple zones.",
+		},
+		"vlan_aware": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Let guests tag their traffic inside the VNet.",
+		},
+		"isolate_ports": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Only let the guests of the VNet talk to the outside network, not to each other.",
+		},
+	})
+}
@@ -0,0 +1,210 @@
+package proxmox
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// sdnZoneOptions are the options every zone has.
+var sdnZoneOptions = map[string]string{
+	"mtu":         "mtu",
+	"ipam":        "ipam",
+	"dns":         "dns",
+	"reverse_dns": "reversedns",
+	"dns_zone":    "dnszone",
+}
+
+// sdnZone returns the resource of a zone type, typeSchema holds the arguments of the zone type.
+func sdnZone(config sdnConfig, typeSchema map[string]*schema.Schema) *schema.Resource {
+	config.kind = "zones"
+	config.idKey = "zone"
+	for k, v := range sdnZoneOptions {
+		config.options[k] = v
+	}
+	if config.lists == nil {
+		config.lists = map[string]string{}
+	}
+	config.lists["nodes"] = "nodes"
+	s := map[string]*schema.Schema{
+		"zone": sdnIDSchema("The ID of the zone."),
+		"nodes": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "The nodes the zone is available on, all nodes when not set.",
+		},
+		"mtu": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntBetween(576, 65520),
+			Description:  "The MTU of the VNets of the zone.",
+		},
+		"ipam": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "pve",
+			Description: "The IPAM the zone uses to assign the addresses of the subnets.",
+		},
+		"dns": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The DNS plugin that registers the guests of the zone.",
+		},
+		"reverse_dns": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The DNS plugin that registers the reverse records of the guests of the zone.",
+		},
+		"dns_zone": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The DNS domain of the zone.",
+		},
+	}
+	for k, v := range typeSchema {
+		s[k] = v
+	}
+	return config.resource(s)
+}
+
+func resourceSDNZoneSimple() *schema.Resource {
+	return sdnZone(sdnConfig{
+		sdnType: "simple",
+		options: map[string]string{"dhcp": "dhcp"},
+	}, map[string]*schema.Schema{
+		"dhcp": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice([]string{"dnsmasq"}, false),
+			Description:  "The DHCP server that serves the DHCP ranges of the subnets.",
+		},
+	})
+}
+
+func resourceSDNZoneVlan() *schema.Resource {
+	return sdnZone(sdnConfig{
+		sdnType: "vlan",
+		options: map[string]string{"bridge": "bridge"},
+	}, map[string]*schema.Schema{
+		"bridge": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The VLAN aware bridge of the nodes the VNets are tagged on.",
+		},
+	})
+}
+
+func resourceSDNZoneQinQ() *schema.Resource {
+	return sdnZone(sdnConfig{
+		sdnType: "qinq",
+		options: map[string]string{
+			"bridge":        "bridge",
+			"service_vlan":  "tag",
+			"vlan_protocol": "vlan-protocol",
+		},
+	}, map[string]*schema.Schema{
+		"bridge": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The bridge of the nodes the VNets are tagged on.",
+		},
+		"service_vlan": {
+			Type:         schema.TypeInt,
+			Required:     true,
+			ValidateFunc: validation.IntBetween(1, 4094),
+			Description:  "The outer VLAN tag of the zone.",
+		},
+		"vlan_protocol": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "802.1q",
+			ValidateFunc: validation.StringInSlice([]string{"802.1q", "802.1ad"}, false),
+			Description:  "The protocol of the outer VLAN tag.",
+		},
+	})
+}
+
+func resourceSDNZoneVxlan() *schema.Resource {
+	return sdnZone(sdnConfig{
+		sdnType: "vxlan",
+		options: map[string]string{},
+		lists:   map[string]string{"peers": "peers"},
+	}, map[string]*schema.Schema{
+		"peers": {
+			Type:        schema.TypeSet,
+			Required:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.IsIPAddress},
+			Description: "The addresses of the nodes that form the VXLAN overlay.",
+		},
+	})
+}
+
+func resourceSDNZoneEvpn() *schema.Resource {
+	return sdnZone(sdnConfig{
+		sdnType: "evpn",
+		options: map[string]string{
+			"controller":                 "controller",
+			"vrf_vxlan":                  "vrf-vxlan",
+			"mac":                        "mac",
+			"exit_nodes_local_routing":   "exitnodes-local-routing",
+			"primary_exit_node":          "exitnodes-primary",
+			"advertise_subnets":          "advertise-subnets",
+			"disable_arp_nd_suppression": "disable-arp-nd-suppression",
+			"rt_import":                  "rt-import",
+		},
+		lists: map[string]string{"exit_nodes": "exitnodes"},
+	}, map[string]*schema.Schema{
+		"controller": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The EVPN controller of the zone.",
+		},
+		"vrf_vxlan": {
+			Type:         schema.TypeInt,
+			Required:     true,
+			ValidateFunc: validation.IntBetween(1, 16777215),
+			Description:  "The VXLAN ID of the VRF of the zone.",
+		},
+		"mac": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validation.IsMACAddress,
+			Description:  "The anycast MAC address of the gateways of the VNets, PVE generates one when not set.",
+		},
+		"exit_nodes": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "The nodes that route the traffic of the zone to the outside network.",
+		},
+		"primary_exit_node": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The exit node that is preferred over the other exit nodes.",
+		},
+		"exit_nodes_local_routing": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Let the exit nodes themselves reach the guests of the zone.",
+		},
+		"advertise_subnets": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Announce the whole subnets in the EVPN network, for guests that are silent.",
+		},
+		"disable_arp_nd_suppression": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Do not suppress ARP and ND packets, for guests that move their address between each other.",
+		},
+		"rt_import": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The route targets that are imported from other EVPN networks, comma separated.",
+		},
+	})
+}
@@ -0,0 +1,222 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const storageResourceType = "storage"
+
+var rxStorageID = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9\-_.]*[a-zA-Z0-9]$`)
+
+// storageConfig describes how the arguments of a storage resource map to the options of the API.
+type storageConfig struct {
+	// storageType is the type of the storage in the API, e.g. "nfs".
+	storageType string
+	// content are the content types the storage type supports.
+	content []string
+	// options maps the arguments to the API options.
+	options map[string]string
+	// secrets maps the sensitive arguments to the API options, PVE never returns them.
+	secrets map[string]string
+	// schema is the schema of the resource, it is used to convert the options of the API.
+	schema map[string]*schema.Schema
+}
+
+// resource returns the resource of the storage type, typeSchema holds the arguments of the storage type.
+func (config storageConfig) resource(typeSchema map[string]*schema.Schema) *schema.Resource {
+	config.schema = storageSchema(config, typeSchema)
+	r := &schema.Resource{
+		CreateContext: config.create,
+		ReadContext:   config.readContext,
+		UpdateContext: config.update,
+		DeleteContext: storageDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema:   config.schema,
+		Timeouts: resourceTimeouts(),
+	}
+	if config.backup() {
+		r.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			return backupRetentionValidate(d)
+		}
+	}
+	return r
+}
+
+// backup reports whether the storage type can hold backups, only those have a retention.
+func (config storageConfig) backup() bool {
+	for _, e := range config.content {
+		if e == "backup" {
+			return true
+		}
+	}
+	return false
+}
+
+// storageSchema returns the arguments every storage has, merged with the arguments of the storage type.
+func storageSchema(config storageConfig, typeSchema map[string]*schema.Schema) map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"storage": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringMatch(rxStorageID, "must start with a letter, end with a letter or digit and only contain letters, digits, '-', '_' and '.'"),
+			Description:  "The ID of the storage, it is used to reference the storage in disks and mount points.",
+		},
+		"content": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Computed:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringInSlice(config.content, false)},
+			Description: "The content types the storage holds, PVE picks a default when not set.",
+		},
+		"nodes": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "The nodes the storage is available on, all nodes when not set.",
+		},
+		"enabled": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+	}
+	if config.backup() {
+		s["retention"] = backupRetentionSchema("How many backups are kept on the storage, backup jobs can override it.")
+	}
+	for k, v := range typeSchema {
+		s[k] = v
+	}
+	return s
+}
+
+func storageSetList(set *schema.Set) string {
+	list := make([]string, 0, set.Len())
+	for _, e := range set.List() {
+		list = append(list, e.(string))
+	}
+	sort.Strings(list)
+	return strings.Join(list, ",")
+}
+
+func storageParseList(raw string) []interface{} {
+	list := make([]interface{}, 0)
+	for _, e := range strings.Split(raw, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			list = append(list, e)
+		}
+	}
+	return list
+}
+
+// params returns the parameters of the create or update request, unset options are removed on update.
+func (config storageConfig) params(d *schema.ResourceData, update bool) map[string]interface{} {
+	params := map[string]interface{}{
+		"content": storageSetList(d.Get("content").(*schema.Set)),
+		"nodes":   storageSetList(d.Get("nodes").(*schema.Set)),
+		"disable": !d.Get("enabled").(bool),
+	}
+	if config.backup() {
+		params["prune-backups"] = ""
+		if v, ok := d.GetOk("retention"); ok && v.([]interface{})[0] != nil {
+			params["prune-backups"] = backupJobPruneBackups(v.([]interface{})[0].(map[string]interface{}))
+		}
+	}
+	optionParams(d, params, config.options, config.schema, update)
+	for key, option := range config.secrets {
+		if !update || d.HasChange(key) {
+			params[option] = d.Get(key).(string)
+		}
+	}
+	deleteEmptyParams(params, update)
+	return params
+}
+
+func (config storageConfig) create(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	storage := d.Get("storage").(string)
+	params := config.params(d, false)
+	params["storage"] = storage
+	params["type"] = config.storageType
+	if err := pconf.Client.Post(ctx, params, "/storage"); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(clusterResourceId(storageResourceType, storage))
+	return diag.FromErr(config.read(ctx, d, pconf.Client))
+}
+
+func (config storageConfig) readContext(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return diag.FromErr(config.read(ctx, d, pconf.Client))
+}
+
+func (config storageConfig) read(ctx context.Context, d *schema.ResourceData, client *pveSDK.Client) error {
+	_, storage, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+	item, err := listItem(ctx, client, "/storage", "storage", storage)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		d.SetId("")
+		return nil
+	}
+	if storageType := itemValue(item, "type"); storageType != config.storageType {
+		return fmt.Errorf("storage '%s' is of type '%s' instead of '%s'", storage, storageType, config.storageType)
+	}
+	d.Set("storage", storage)
+	d.Set("content", storageParseList(itemValue(item, "content")))
+	d.Set("nodes", storageParseList(itemValue(item, "nodes")))
+	d.Set("enabled", itemValue(item, "disable") != "1")
+	if config.backup() {
+		d.Set("retention", backupJobParseRetention(itemValue(item, "prune-backups")))
+	}
+	optionRead(d, item, config.options, config.schema)
+	return nil
+}
+
+func (config storageConfig) update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, storage, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err = pconf.Client.Put(ctx, config.params(d, true), "/storage/"+storage); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(config.read(ctx, d, pconf.Client))
+}
+
+func storageDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, storage, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(pconf.Client.Delete(ctx, "/storage/"+storage))
+}
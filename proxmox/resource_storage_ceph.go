@@ -0,0 +1,95 @@
+package proxmox
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// storageCephSchema returns the arguments to connect to a Ceph cluster, which are only needed for external clusters.
+func storageCephSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"monhost": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The monitors of an external Ceph cluster, separated by spaces. Not needed for the Ceph cluster of PVE.",
+		},
+		"username": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The Ceph user of an external Ceph cluster.",
+		},
+		"keyring": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "The keyring or secret of `username`.",
+		},
+	}
+}
+
+func resourceStorageRbd() *schema.Resource {
+	config := storageConfig{
+		storageType: "rbd",
+		content:     storageGuestContent,
+		options: map[string]string{
+			"monhost":   "monhost",
+			"username":  "username",
+			"pool":      "pool",
+			"data_pool": "data-pool",
+			"namespace": "namespace",
+			"krbd":      "krbd",
+		},
+		secrets: map[string]string{"keyring": "keyring"},
+	}
+	typeSchema := storageCephSchema()
+	typeSchema["pool"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Default:     "rbd",
+		Description: "The Ceph pool.",
+	}
+	typeSchema["data_pool"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "The pool the data is stored in, e.g. an erasure coded pool. The metadata is stored in `pool`.",
+	}
+	typeSchema["namespace"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+	}
+	typeSchema["krbd"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: "Access the disks with the kernel RBD module instead of librbd.",
+	}
+	return config.resource(typeSchema)
+}
+
+func resourceStorageCephFS() *schema.Resource {
+	config := storageConfig{
+		storageType: "cephfs",
+		content:     []string{"vztmpl", "iso", "backup", "snippets", "import"},
+		options: map[string]string{
+			"monhost":  "monhost",
+			"username": "username",
+			"fs_name":  "fs-name",
+			"subdir":   "subdir",
+			"path":     "path",
+		},
+		secrets: map[string]string{"keyring": "keyring"},
+	}
+	typeSchema := storageCephSchema()
+	typeSchema["fs_name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		ForceNew:    true,
+		Description: "The Ceph file system, the default file system when not set.",
+	}
+	typeSchema["subdir"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "The directory of the file system that is mounted.",
+	}
+	typeSchema["path"] = storageMountPathSchema()
+	return config.resource(typeSchema)
+}
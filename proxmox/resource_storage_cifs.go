@@ -0,0 +1,69 @@
+package proxmox
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceStorageCifs() *schema.Resource {
+	config := storageConfig{
+		storageType: "cifs",
+		content:     storageFileContent,
+		options: map[string]string{
+			"server":      "server",
+			"share":       "share",
+			"subdir":      "subdir",
+			"path":        "path",
+			"username":    "username",
+			"domain":      "domain",
+			"smb_version": "smbversion",
+		},
+		secrets: map[string]string{"password": "password"},
+	}
+	return config.resource(map[string]*schema.Schema{
+		"server": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The address of the SMB/CIFS server.",
+		},
+		"share": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The name of the share.",
+		},
+		"subdir": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The directory of the share that is used.",
+		},
+		"path": storageMountPathSchema(),
+		"username": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"password": {
+			Type:      schema.TypeString,
+			Optional:  true,
+			Sensitive: true,
+		},
+		"domain": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"smb_version": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  "default",
+			ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+				"default",
+				"2.0",
+				"2.1",
+				"3",
+				"3.0",
+				"3.11",
+			}, false)),
+		},
+	})
+}
@@ -0,0 +1,50 @@
+package proxmox
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// storageFileContent are the content types of the storage types that store files.
+var storageFileContent = []string{"images", "rootdir", "vztmpl", "iso", "backup", "snippets", "import"}
+
+// storageMountPathSchema returns the mount point argument of the network file systems.
+func storageMountPathSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Computed:    true,
+		ForceNew:    true,
+		Description: "Where the share is mounted on the nodes, `/mnt/pve/<storage>` when not set.",
+	}
+}
+
+func resourceStorageDir() *schema.Resource {
+	config := storageConfig{
+		storageType: "dir",
+		content:     storageFileContent,
+		options: map[string]string{
+			"path":          "path",
+			"shared":        "shared",
+			"is_mountpoint": "is_mountpoint",
+		},
+	}
+	return config.resource(map[string]*schema.Schema{
+		"path": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The directory on the nodes.",
+		},
+		"shared": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "The directory has the same content on every node, e.g. because it is a mounted network share.",
+		},
+		"is_mountpoint": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "`yes` when `path` is a mount point, or the path of the mount point. The storage is only used when the mount point is mounted.",
+		},
+	})
+}
@@ -0,0 +1,322 @@
+package proxmox
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	storageFileContentImport   = "import"
+	storageFileContentTemplate = "vztmpl"
+)
+
+var storageFileSources = []string{"source_file", "content", "url"}
+
+func resourceStorageFile() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceStorageFileCreate,
+		ReadContext:   resourceStorageFileRead,
+		DeleteContext: resourceStorageFileDelete,
+		CustomizeDiff: resourceStorageFileCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"pve_node": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"storage": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"content_type": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+					storageFileContentImport,
+					isoContentType,
+					storageFileContentTemplate,
+				}, false)),
+				Description: "The content type of the file, one of 'import', 'iso' or 'vztmpl'.",
+			},
+			"filename": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The name of the file on the storage, defaults to the name of the source file or the last element of the URL.",
+			},
+			"source_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: storageFileSources,
+				Description:  "Path to a local file to upload.",
+			},
+			"content": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: storageFileSources,
+				Description:  "Inline content of the file.",
+			},
+			"url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: storageFileSources,
+				Description:  "URL the file is downloaded from.",
+			},
+			"download_mode": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          storageIsoDownloadLocal,
+				ValidateDiagFunc: DownloadModeValidator(),
+				Description:      "Where a file from 'url' is downloaded, 'local' downloads it on the machine running Terraform and uploads it, 'server' lets the node download it.",
+			},
+			"checksum": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"checksum_algorithm", "url"},
+			},
+			"checksum_algorithm": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				RequiredWith:     []string{"checksum"},
+				ValidateDiagFunc: ChecksumAlgorithmValidator(),
+			},
+			"content_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The sha256 checksum of the file, changes of the local source cause the file to be replaced.",
+			},
+			"size": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The human readable size of the file on the storage.",
+			},
+			"size_bytes": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size of the file on the storage in bytes.",
+			},
+		},
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+// resourceStorageFileCustomizeDiff hashes the local source during planning, so edits to it replace the file.
+func resourceStorageFileCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Get("download_mode").(string) == storageIsoDownloadServer {
+		if _, ok := d.GetOk("url"); !ok && d.NewValueKnown("url") {
+			return fmt.Errorf("download_mode '%s' requires url to be set", storageIsoDownloadServer)
+		}
+	}
+	if _, ok := d.GetOk("content"); ok && d.Get("filename").(string) == "" && d.NewValueKnown("filename") {
+		return fmt.Errorf("filename is required when content is used")
+	}
+
+	if !d.NewValueKnown("source_file") || !d.NewValueKnown("content") {
+		return d.SetNewComputed("content_hash")
+	}
+	var sum string
+	if v, ok := d.GetOk("source_file"); ok {
+		file, err := os.Open(v.(string))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		fileHash := sha256.New()
+		if _, err = io.Copy(fileHash, file); err != nil {
+			return err
+		}
+		sum = hex.EncodeToString(fileHash.Sum(nil))
+	} else if v, ok := d.GetOk("content"); ok {
+		tmp := sha256.Sum256([]byte(v.(string)))
+		sum = hex.EncodeToString(tmp[:])
+	} else {
+		return nil
+	}
+	if d.Get("content_hash").(string) == sum {
+		return nil
+	}
+	if err := d.SetNew("content_hash", sum); err != nil {
+		return err
+	}
+	if d.Id() != "" {
+		return d.ForceNew("content_hash")
+	}
+	return nil
+}
+
+// storageFileName returns the configured file name, or derives it from the source.
+func storageFileName(d *schema.ResourceData) string {
+	if v := d.Get("filename").(string); v != "" {
+		return v
+	}
+	if v := d.Get("source_file").(string); v != "" {
+		return filepath.Base(v)
+	}
+	if v, err := url.Parse(d.Get("url").(string)); err == nil {
+		return path.Base(v.Path)
+	}
+	return ""
+}
+
+func resourceStorageFileCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client := pconf.Client
+	node := d.Get("pve_node").(string)
+	storage := d.Get("storage").(string)
+	contentType := d.Get("content_type").(string)
+	fileName := storageFileName(d)
+	sourceURL := d.Get("url").(string)
+	checksum := d.Get("checksum").(string)
+	volID := fmt.Sprintf("%s:%s/%s", storage, contentType, fileName)
+
+	var contentHash string
+	var diags diag.Diagnostics
+	if sourceURL != "" && d.Get("download_mode").(string) == storageIsoDownloadServer {
+		params := map[string]interface{}{
+			"content":  contentType,
+			"filename": fileName,
+			"url":      sourceURL,
+		}
+		if checksum != "" {
+			params["checksum"] = checksum
+			params["checksum-algorithm"] = d.Get("checksum_algorithm").(string)
+		}
+		exitStatus, err := client.PostWithTask(ctx, params, fmt.Sprintf("/nodes/%s/storage/%s/download-url", node, storage))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		diags = taskWarnings(exitStatus, fmt.Sprintf("node %s downloaded %s to %s with warnings", node, sourceURL, volID))
+		if d.Get("checksum_algorithm").(string) == "sha256" {
+			contentHash = strings.ToLower(checksum)
+		}
+	} else {
+		file, err := os.CreateTemp(os.TempDir(), fileName)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		defer os.Remove(file.Name())
+		defer file.Close()
+		switch {
+		case sourceURL != "":
+			err = _downloadFile(sourceURL, file, nil)
+		case d.Get("source_file").(string) != "":
+			err = copyFile(d.Get("source_file").(string), file)
+		default:
+			_, err = file.WriteString(d.Get("content").(string))
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		fileHash := sha256.New()
+		hashes := []io.Writer{fileHash}
+		var checksumFileHash hash.Hash
+		if checksum != "" {
+			checksumFileHash = checksumHash(d.Get("checksum_algorithm").(string))
+			hashes = append(hashes, checksumFileHash)
+		}
+		file.Seek(0, 0)
+		if _, err = io.Copy(io.MultiWriter(hashes...), file); err != nil {
+			return diag.FromErr(err)
+		}
+		if checksumFileHash != nil {
+			if err = verifyChecksum(checksumFileHash, checksum); err != nil {
+				return diag.Errorf("verifying %s: %v", sourceURL, err)
+			}
+		}
+		contentHash = hex.EncodeToString(fileHash.Sum(nil))
+
+		file.Seek(0, 0)
+		if err = client.Upload(ctx, node, storage, contentType, fileName, file); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(volID)
+	d.Set("filename", fileName)
+	d.Set("content_hash", contentHash)
+	return append(diags, resourceStorageFileRead(ctx, d, meta)...)
+}
+
+func copyFile(source string, dst io.Writer) error {
+	file, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(dst, file)
+	return err
+}
+
+func resourceStorageFileRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	client := pconf.Client
+
+	volumes, err := storageContent(ctx, client, d.Get("storage").(string), pveSDK.NodeName(d.Get("pve_node").(string)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var diags diag.Diagnostics
+	for _, contentMap := range volumes {
+		if contentMap["volid"].(string) != d.Id() {
+			continue
+		}
+		size := int64(contentMap["size"].(float64))
+		// PVE does not expose file checksums, a different size means the file was changed outside of Terraform.
+		if recorded, ok := d.GetOk("size_bytes"); ok && int64(recorded.(int)) != size {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "File changed outside of Terraform",
+				Detail:   fmt.Sprintf("the size of %s changed from %d to %d bytes, it will be replaced", d.Id(), recorded, size)})
+			d.Set("content_hash", "")
+			if d.Get("checksum").(string) != "" {
+				d.Set("checksum", "")
+			}
+		}
+		d.Set("size", ByteCountIEC(size))
+		d.Set("size_bytes", size)
+		return diags
+	}
+	d.SetId("")
+	return nil
+}
+
+func resourceStorageFileDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	client := pconf.Client
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	exitStatus, err := client.DeleteWithTask(ctx, fmt.Sprintf("/nodes/%s/storage/%s/content/%s", d.Get("pve_node").(string), d.Get("storage").(string), d.Id()))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return taskWarnings(exitStatus, fmt.Sprintf("deleted %s with warnings", d.Id()))
+}
@@ -0,0 +1,168 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func testAccExampleStorageFileTemplate(content string) string {
+	return fmt.Sprintf(`
+resource "proxmox_storage_file" "test" {
+  pve_node     = "pve"
+  storage      = "local"
+  content_type = "vztmpl"
+  filename     = "alpine.tar.xz"
+  content      = "%s"
+}
+`, content)
+}
+
+func TestAccProxmoxStorageFile_Fake(t *testing.T) {
+	_, provider := testAccFakeProvider(t)
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProxmoxProviderFactory(),
+		Steps: []resource.TestStep{
+			{
+				Config: provider + testAccExampleStorageFileTemplate("#cloud-config"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_storage_file.test", "id", "local:vztmpl/alpine.tar.xz"),
+					resource.TestCheckResourceAttr("proxmox_storage_file.test", "content_hash", testSha256("#cloud-config")),
+				),
+			},
+			{
+				Config: provider + testAccExampleStorageFileTemplate("#cloud-config\\nhostname: test"),
+				Check:  resource.TestCheckResourceAttr("proxmox_storage_file.test", "size_bytes", "27"),
+			},
+		},
+	})
+}
+
+func Test_ResourceStorageFile_Fake(t *testing.T) {
+	const content = "#cloud-config"
+	source := filepath.Join(t.TempDir(), "debian.tar.zst")
+	require.NoError(t, os.WriteFile(source, []byte(content), 0o600))
+
+	tests := []struct {
+		name   string
+		config map[string]any
+		id     string
+	}{
+		{name: "inline content",
+			config: map[string]any{"content_type": "vztmpl", "filename": "inline.tar.xz", "content": content},
+			id:     "local:vztmpl/inline.tar.xz"},
+		{name: "local file",
+			config: map[string]any{"content_type": "vztmpl", "source_file": source},
+			id:     "local:vztmpl/debian.tar.zst"},
+		{name: "url uploaded",
+			config: map[string]any{"content_type": "vztmpl", "url": testIsoServer(t, content) + "/alpine.tar.xz", "checksum": testSha256(content), "checksum_algorithm": "sha256"},
+			id:     "local:vztmpl/alpine.tar.xz"},
+		{name: "url downloaded by node",
+			config: map[string]any{"content_type": "import", "filename": "disk.qcow2", "url": testIsoServer(t, content), "download_mode": storageIsoDownloadServer},
+			id:     "local:import/disk.qcow2"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake, _ := testAccFakeProvider(t)
+			meta := testFakeMeta(t, fake)
+			r := resourceStorageFile()
+			test.config["pve_node"] = "pve"
+			test.config["storage"] = "local"
+
+			d := testFakeCreate(t, r, meta, test.config)
+			require.Equal(t, test.id, d.Id())
+			require.Equal(t, len(content), d.Get("size_bytes"))
+
+			testFakeDelete(t, r, meta, d)
+			testFakeRead(t, r, meta, d)
+			require.Equal(t, "", d.Id())
+			testFakeUnknown(t, fake)
+		})
+	}
+}
+
+func Test_ResourceStorageFile_ContentHash(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "debian.tar.zst")
+	require.NoError(t, os.WriteFile(source, []byte("#cloud-config"), 0o600))
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceStorageFile()
+	config := map[string]any{
+		"pve_node":     "pve",
+		"storage":      "local",
+		"content_type": "vztmpl",
+		"source_file":  source}
+
+	d := testFakeCreate(t, r, meta, config)
+	require.Equal(t, testSha256("#cloud-config"), d.Get("content_hash"))
+
+	diff, err := r.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(config), meta)
+	require.NoError(t, err)
+	require.True(t, diff == nil || diff.Empty())
+
+	require.NoError(t, os.WriteFile(source, []byte("#cloud-config\nhostname: test"), 0o600))
+	diff, err = r.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(config), meta)
+	require.NoError(t, err)
+	require.True(t, diff.RequiresNew())
+	require.Equal(t, testSha256("#cloud-config\nhostname: test"), diff.Attributes["content_hash"].New)
+}
+
+func Test_ResourceStorageFile_Drift(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceStorageFile()
+	config := map[string]any{
+		"pve_node":     "pve",
+		"storage":      "local",
+		"content_type": "vztmpl",
+		"filename":     "alpine.tar.xz",
+		"content":      "#cloud-config"}
+
+	d := testFakeCreate(t, r, meta, config)
+	fake.PutFile("pve", "local", "vztmpl", "alpine.tar.xz", []byte("edited on the node"))
+	diags := r.ReadContext(context.Background(), d, meta)
+	require.False(t, diags.HasError())
+	require.Len(t, diags, 1)
+
+	diff, err := r.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(config), meta)
+	require.NoError(t, err)
+	require.True(t, diff.RequiresNew())
+}
+
+func Test_ResourceStorageFile_Errors(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceStorageFile()
+	url := testIsoServer(t, "#cloud-config")
+
+	// PVE refuses uploads and downloads of snippets, so they are not a valid content type.
+	require.True(t, r.Validate(terraform.NewResourceConfigRaw(map[string]any{
+		"pve_node":     "pve",
+		"storage":      "local",
+		"content_type": "snippets",
+		"url":          url})).HasError())
+	require.Error(t, meta.Client.Upload(context.Background(), "pve", "local", "snippets", "user-data.yaml", strings.NewReader("#cloud-config")))
+
+	for _, mode := range []string{storageIsoDownloadLocal, storageIsoDownloadServer} {
+		d := schema.TestResourceDataRaw(t, r.Schema, map[string]any{
+			"pve_node":           "pve",
+			"storage":            "local",
+			"content_type":       "vztmpl",
+			"filename":           "alpine.tar.xz",
+			"url":                url,
+			"download_mode":      mode,
+			"checksum":           testSha256("something else"),
+			"checksum_algorithm": "sha256"})
+		require.True(t, r.CreateContext(context.Background(), d, meta).HasError(), mode)
+		require.Equal(t, "", d.Id())
+	}
+	testFakeUnknown(t, fake)
+}
@@ -2,7 +2,13 @@ package proxmox
 
 import (
 	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
 	"fmt"
+	"hash"
 	"io"
 	"net/http"
 	"os"
@@ -13,6 +19,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+const (
+	storageIsoDownloadLocal  = "local"
+	storageIsoDownloadServer = "server"
+)
+
 func resourceStorageIso() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceStorageIsoCreate,
@@ -25,14 +36,25 @@ func resourceStorageIso() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"checksum_algorithm": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				RequiredWith:     []string{"checksum"},
+				ValidateDiagFunc: ChecksumAlgorithmValidator(),
 			},
 			"checksum": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"checksum_algorithm"},
+			},
+			"download_mode": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          storageIsoDownloadLocal,
+				ValidateDiagFunc: DownloadModeValidator(),
+				Description:      "Where the file is downloaded, 'local' downloads it on the machine running Terraform and uploads it, 'server' lets the node download it.",
 			},
 			"filename": {
 				Type:     schema.TypeString,
@@ -54,6 +76,16 @@ func resourceStorageIso() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+			"size": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The human readable size of the file on the storage.",
+			},
+			"size_bytes": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size of the file on the storage in bytes.",
+			},
 		},
 		Timeouts: resourceTimeouts(),
 	}
@@ -69,29 +101,61 @@ func resourceStorageIsoCreate(ctx context.Context, d *schema.ResourceData, meta
 	fileName := d.Get("filename").(string)
 	storage := d.Get("storage").(string)
 	node := d.Get("pve_node").(string)
+	checksum := d.Get("checksum").(string)
+	algorithm := d.Get("checksum_algorithm").(string)
 
 	client := pconf.Client
-	file, err := os.CreateTemp(os.TempDir(), fileName)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	err = _downloadFile(url, file)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	file.Seek(0, 0)
-	defer file.Close()
-	err = client.Upload(ctx, node, storage, isoContentType, fileName, file)
-	if err != nil {
-		return diag.FromErr(err)
-	}
 	volId := fmt.Sprintf("%s:%s/%s", storage, isoContentType, fileName)
+
+	var diags diag.Diagnostics
+	if d.Get("download_mode").(string) == storageIsoDownloadServer {
+		params := map[string]interface{}{
+			"content":  isoContentType,
+			"filename": fileName,
+			"url":      url,
+		}
+		if checksum != "" {
+			params["checksum"] = checksum
+			params["checksum-algorithm"] = algorithm
+		}
+		exitStatus, err := client.PostWithTask(ctx, params, fmt.Sprintf("/nodes/%s/storage/%s/download-url", node, storage))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		diags = taskWarnings(exitStatus, fmt.Sprintf("node %s downloaded %s to %s with warnings", node, url, volId))
+	} else {
+		file, err := os.CreateTemp(os.TempDir(), fileName)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		defer os.Remove(file.Name())
+		defer file.Close()
+		var fileHash hash.Hash
+		if checksum != "" {
+			fileHash = checksumHash(algorithm)
+		}
+		err = _downloadFile(url, file, fileHash)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if fileHash != nil {
+			if err = verifyChecksum(fileHash, checksum); err != nil {
+				return diag.Errorf("verifying %s: %v", url, err)
+			}
+		}
+		file.Seek(0, 0)
+		err = client.Upload(ctx, node, storage, isoContentType, fileName, file)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
 	d.SetId(volId)
 
-	return resourceStorageIsoRead(ctx, d, meta)
+	return append(diags, resourceStorageIsoRead(ctx, d, meta)...)
 }
 
-func _downloadFile(url string, file *os.File) error {
+// _downloadFile downloads the url into file, when fileHash is not nil the content is also written to it.
+func _downloadFile(url string, file *os.File, fileHash hash.Hash) error {
 	client := http.Client{
 		CheckRedirect: func(r *http.Request, via []*http.Request) error {
 			r.URL.Opaque = r.URL.Path
@@ -103,28 +167,69 @@ func _downloadFile(url string, file *os.File) error {
 		return err
 	}
 	defer resp.Body.Close()
-	_, err = io.Copy(file, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading %s: %s", url, resp.Status)
+	}
+	var dst io.Writer = file
+	if fileHash != nil {
+		dst = io.MultiWriter(file, fileHash)
+	}
+	_, err = io.Copy(dst, resp.Body)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// checksumHash returns the hash for one of the checksum algorithms supported by PVE.
+func checksumHash(algorithm string) hash.Hash {
+	switch algorithm {
+	case "md5":
+		return md5.New()
+	case "sha1":
+		return sha1.New()
+	case "sha224":
+		return sha256.New224()
+	case "sha256":
+		return sha256.New()
+	case "sha384":
+		return sha512.New384()
+	case "sha512":
+		return sha512.New()
+	}
+	return nil
+}
+
+func verifyChecksum(fileHash hash.Hash, checksum string) error {
+	if sum := hex.EncodeToString(fileHash.Sum(nil)); !strings.EqualFold(sum, checksum) {
+		return fmt.Errorf("checksum mismatch: got '%s', expected '%s'", sum, checksum)
+	}
+	return nil
+}
+
 func resourceStorageIsoRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	pconf := meta.(*providerConfiguration)
 	client := pconf.Client
 
 	var isoFound bool
-	storageContent, err := client.GetStorageContent(ctx, d.Get("storage").(string), pveSDK.NodeName(d.Get("pve_node").(string)))
+	var diags diag.Diagnostics
+	volumes, err := storageContent(ctx, client, d.Get("storage").(string), pveSDK.NodeName(d.Get("pve_node").(string)))
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	contents := storageContent["data"].([]interface{})
-	for c := range contents {
-		contentMap := contents[c].(map[string]interface{})
+	for _, contentMap := range volumes {
 		if contentMap["volid"].(string) == d.Id() {
-			size := contentMap["size"].(float64)
-			d.Set("size", ByteCountIEC(int64(size)))
+			size := int64(contentMap["size"].(float64))
+			// PVE does not expose file checksums, a different size means the file was replaced after it was verified.
+			if recorded, ok := d.GetOk("size_bytes"); ok && int64(recorded.(int)) != size && d.Get("checksum").(string) != "" {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  "File no longer matches checksum",
+					Detail:   fmt.Sprintf("the size of %s changed from %d to %d bytes, so it no longer matches the checksum and will be replaced", d.Id(), recorded, size)})
+				d.Set("checksum", "")
+			}
+			d.Set("size", ByteCountIEC(size))
+			d.Set("size_bytes", size)
 			isoFound = true
 			break
 		}
@@ -134,7 +239,7 @@ func resourceStorageIsoRead(ctx context.Context, d *schema.ResourceData, meta in
 		d.SetId("")
 	}
 
-	return nil
+	return diags
 }
 
 func resourceStorageIsoDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -0,0 +1,158 @@
+package proxmox
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/require"
+)
+
+// testIsoServer serves a small fake ISO image for the download tests.
+func testIsoServer(t *testing.T, content string) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(content))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL + "/test.iso"
+}
+
+func testSha256(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func testAccExampleStorageIso(url, mode, checksum string) string {
+	return fmt.Sprintf(`
+resource "proxmox_storage_iso" "test" {
+  filename           = "test.iso"
+  storage            = "local"
+  pve_node           = "pve"
+  url                = "%s"
+  download_mode      = "%s"
+  checksum           = "%s"
+  checksum_algorithm = "sha256"
+}
+`, url, mode, checksum)
+}
+
+func TestAccProxmoxStorageIso_Fake(t *testing.T) {
+	_, provider := testAccFakeProvider(t)
+	url := testIsoServer(t, "fake iso content")
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProxmoxProviderFactory(),
+		Steps: []resource.TestStep{
+			{
+				Config: provider + testAccExampleStorageIso(url, storageIsoDownloadLocal, testSha256("fake iso content")),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_storage_iso.test", "id", "local:iso/test.iso"),
+					resource.TestCheckResourceAttr("proxmox_storage_iso.test", "size_bytes", "16"),
+				),
+			},
+			{
+				Config: provider + testAccExampleStorageIso(url, storageIsoDownloadServer, testSha256("fake iso content")),
+				Check:  resource.TestCheckResourceAttr("proxmox_storage_iso.test", "download_mode", storageIsoDownloadServer),
+			},
+		},
+	})
+}
+
+func Test_ResourceStorageIso_Fake(t *testing.T) {
+	const content = "fake iso content"
+	tests := []struct {
+		name     string
+		mode     string
+		checksum string
+		err      bool
+	}{
+		{name: "local", mode: storageIsoDownloadLocal},
+		{name: "local checksum", mode: storageIsoDownloadLocal, checksum: testSha256(content)},
+		{name: "local checksum mismatch", mode: storageIsoDownloadLocal, checksum: testSha256("other"), err: true},
+		{name: "server", mode: storageIsoDownloadServer},
+		{name: "server checksum", mode: storageIsoDownloadServer, checksum: testSha256(content)},
+		{name: "server checksum mismatch", mode: storageIsoDownloadServer, checksum: testSha256("other"), err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake, _ := testAccFakeProvider(t)
+			meta := testFakeMeta(t, fake)
+			r := resourceStorageIso()
+			config := map[string]any{
+				"filename":      "test.iso",
+				"storage":       "local",
+				"pve_node":      "pve",
+				"download_mode": test.mode,
+				"url":           testIsoServer(t, content)}
+			if test.checksum != "" {
+				config["checksum"] = test.checksum
+				config["checksum_algorithm"] = "sha256"
+			}
+			if test.err {
+				d := schema.TestResourceDataRaw(t, r.Schema, config)
+				require.True(t, r.CreateContext(context.Background(), d, meta).HasError())
+				require.Equal(t, "", d.Id())
+				testFakeUnknown(t, fake)
+				return
+			}
+			d := testFakeCreate(t, r, meta, config)
+			require.Equal(t, "local:iso/test.iso", d.Id())
+			require.Equal(t, len(content), d.Get("size_bytes"))
+
+			testFakeDelete(t, r, meta, d)
+			testFakeRead(t, r, meta, d)
+			require.Equal(t, "", d.Id())
+			testFakeUnknown(t, fake)
+		})
+	}
+}
+
+func Test_ResourceStorageIso_Drift(t *testing.T) {
+	const content = "fake iso content"
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceStorageIso()
+
+	d := testFakeCreate(t, r, meta, map[string]any{
+		"filename":           "test.iso",
+		"storage":            "local",
+		"pve_node":           "pve",
+		"url":                testIsoServer(t, content),
+		"checksum":           testSha256(content),
+		"checksum_algorithm": "sha256"})
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, testSha256(content), d.Get("checksum"))
+
+	fake.PutFile("pve", "local", "iso", "test.iso", []byte("replaced out of band"))
+	diags := r.ReadContext(context.Background(), d, meta)
+	require.False(t, diags.HasError())
+	require.Len(t, diags, 1)
+	require.Equal(t, "", d.Get("checksum"))
+	require.Equal(t, "local:iso/test.iso", d.Id())
+}
+
+func Test_ResourceStorageIso_Warnings(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceStorageIso()
+
+	// A download that finishes with warnings succeeded, the ISO has to be tracked.
+	fake.FailTask("download", "WARNINGS: 1")
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]any{
+		"filename":      "test.iso",
+		"storage":       "local",
+		"pve_node":      "pve",
+		"download_mode": storageIsoDownloadServer,
+		"url":           testIsoServer(t, "fake iso content")})
+	diags := r.CreateContext(context.Background(), d, meta)
+	require.False(t, diags.HasError(), diags)
+	require.Len(t, diags, 1)
+	require.Equal(t, diag.Warning, diags[0].Severity)
+	require.Equal(t, "local:iso/test.iso", d.Id())
+}
@@ -0,0 +1,72 @@
+package proxmox
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// storageGuestContent are the content types of the storage types that only hold guest disks.
+var storageGuestContent = []string{"images", "rootdir"}
+
+func resourceStorageLvm() *schema.Resource {
+	config := storageConfig{
+		storageType: "lvm",
+		content:     storageGuestContent,
+		options: map[string]string{
+			"volume_group": "vgname",
+			"base":         "base",
+			"shared":       "shared",
+			"saferemove":   "saferemove",
+		},
+	}
+	return config.resource(map[string]*schema.Schema{
+		"volume_group": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The LVM volume group.",
+		},
+		"base": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			ForceNew:    true,
+			Description: "The base volume the volume group is on, e.g. an iSCSI LUN.",
+		},
+		"shared": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "The volume group is on shared block storage that every node can access.",
+		},
+		"saferemove": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Zero out the data of removed volumes.",
+		},
+	})
+}
+
+func resourceStorageLvmThin() *schema.Resource {
+	config := storageConfig{
+		storageType: "lvmthin",
+		content:     storageGuestContent,
+		options: map[string]string{
+			"volume_group": "vgname",
+			"thin_pool":    "thinpool",
+		},
+	}
+	return config.resource(map[string]*schema.Schema{
+		"volume_group": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The LVM volume group.",
+		},
+		"thin_pool": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The LVM thin pool in the volume group.",
+		},
+	})
+}
@@ -0,0 +1,38 @@
+package proxmox
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceStorageNfs() *schema.Resource {
+	config := storageConfig{
+		storageType: "nfs",
+		content:     storageFileContent,
+		options: map[string]string{
+			"server":  "server",
+			"export":  "export",
+			"path":    "path",
+			"options": "options",
+		},
+	}
+	return config.resource(map[string]*schema.Schema{
+		"server": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The address of the NFS server.",
+		},
+		"export": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The exported path on the NFS server.",
+		},
+		"path": storageMountPathSchema(),
+		"options": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The NFS mount options, e.g. `vers=4.2`.",
+		},
+	})
+}
@@ -0,0 +1,71 @@
+package proxmox
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceStoragePbs() *schema.Resource {
+	config := storageConfig{
+		storageType: "pbs",
+		content:     []string{"backup"},
+		options: map[string]string{
+			"server":      "server",
+			"port":        "port",
+			"datastore":   "datastore",
+			"namespace":   "namespace",
+			"username":    "username",
+			"fingerprint": "fingerprint",
+		},
+		secrets: map[string]string{
+			"password":       "password",
+			"encryption_key": "encryption-key",
+		},
+	}
+	return config.resource(map[string]*schema.Schema{
+		"server": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The address of the Proxmox Backup Server.",
+		},
+		"port": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IsPortNumberOrZero,
+			Description:  "The port of the server, 0 uses the default port 8007.",
+		},
+		"datastore": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The datastore on the server.",
+		},
+		"namespace": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"username": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The user or API token on the server, e.g. `backup@pbs` or `backup@pbs!pve`.",
+		},
+		"password": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Sensitive:   true,
+			Description: "The password of the user or the secret of the API token.",
+		},
+		"fingerprint": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The SHA256 fingerprint of the certificate of the server, needed when the certificate is not trusted.",
+		},
+		"encryption_key": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "The key the backups are encrypted with on the client side, in JSON format.",
+		},
+	})
+}
@@ -0,0 +1,164 @@
+package proxmox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResourceStorageDir_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceStorageDir()
+
+	config := map[string]any{
+		"storage":   "backups",
+		"path":      "/srv/backups",
+		"content":   []any{"iso", "backup"},
+		"nodes":     []any{"pve"},
+		"retention": []any{map[string]any{"keep_last": 3, "keep_daily": 7}}}
+	d := testFakeCreate(t, r, meta, config)
+	require.Equal(t, "storage/backups", d.Id())
+	storage, ok := fake.StorageConfig("backups")
+	require.True(t, ok)
+	require.Equal(t, "dir", storage["type"])
+	require.Equal(t, "/srv/backups", storage["path"])
+	require.Equal(t, "backup,iso", storage["content"])
+	require.Equal(t, "pve", storage["nodes"])
+	require.Equal(t, "keep-daily=7,keep-last=3", storage["prune-backups"])
+	require.Equal(t, "0", storage["disable"])
+	require.True(t, d.Get("enabled").(bool))
+	require.False(t, d.Get("shared").(bool))
+	require.Equal(t, 3, d.Get("retention.0.keep_last"))
+
+	// The path can not be changed, so it is not sent on update.
+	config["shared"] = true
+	config["enabled"] = false
+	delete(config, "nodes")
+	delete(config, "retention")
+	d = testFakeUpdate(t, r, meta, d, config)
+	storage, _ = fake.StorageConfig("backups")
+	require.Equal(t, "1", storage["shared"])
+	require.Equal(t, "1", storage["disable"])
+	require.NotContains(t, storage, "nodes")
+	require.NotContains(t, storage, "prune-backups")
+	require.False(t, d.Get("enabled").(bool))
+	require.Empty(t, d.Get("retention"))
+
+	// A storage of another type is not read as a directory storage.
+	d.SetId(clusterResourceId(storageResourceType, "local-lvm"))
+	require.True(t, r.ReadContext(context.Background(), d, meta).HasError())
+	d.SetId(clusterResourceId(storageResourceType, "backups"))
+
+	testFakeDelete(t, r, meta, d)
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "", d.Id())
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceStorageCifs_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceStorageCifs()
+
+	config := map[string]any{
+		"storage":  "share",
+		"server":   "files.example.com",
+		"share":    "pve",
+		"username": "pve",
+		"password": "secret",
+		"content":  []any{"iso", "vztmpl"}}
+	d := testFakeCreate(t, r, meta, config)
+	storage, _ := fake.StorageConfig("share")
+	require.Equal(t, "secret", storage["password"])
+	require.Equal(t, "1", storage["shared"])
+	require.Equal(t, "/mnt/pve/share", d.Get("path"))
+	require.Equal(t, "default", d.Get("smb_version"))
+	require.Equal(t, "secret", d.Get("password"))
+
+	// The password is only sent when it changes.
+	config["domain"] = "EXAMPLE"
+	d = testFakeUpdate(t, r, meta, d, config)
+	storage, _ = fake.StorageConfig("share")
+	require.Equal(t, "EXAMPLE", storage["domain"])
+	require.Equal(t, "secret", storage["password"])
+
+	config["password"] = "rotated"
+	d = testFakeUpdate(t, r, meta, d, config)
+	storage, _ = fake.StorageConfig("share")
+	require.Equal(t, "rotated", storage["password"])
+
+	testFakeDelete(t, r, meta, d)
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceStorageLvmThin_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceStorageLvmThin()
+
+	d := testFakeCreate(t, r, meta, map[string]any{
+		"storage":      "fast",
+		"volume_group": "pve",
+		"thin_pool":    "data"})
+	storage, _ := fake.StorageConfig("fast")
+	require.Equal(t, "pve", storage["vgname"])
+	require.Equal(t, "data", storage["thinpool"])
+	require.Equal(t, []any{"images"}, d.Get("content").(*schema.Set).List())
+	require.NotContains(t, d.State().Attributes, "retention.#")
+
+	testFakeDelete(t, r, meta, d)
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceStoragePbs_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceStoragePbs()
+
+	d := testFakeCreate(t, r, meta, map[string]any{
+		"storage":        "pbs",
+		"server":         "pbs.example.com",
+		"datastore":      "store1",
+		"username":       "backup@pbs!pve",
+		"password":       "token-secret",
+		"encryption_key": `{"kdf":null}`,
+		"port":           8007})
+	storage, _ := fake.StorageConfig("pbs")
+	require.Equal(t, "token-secret", storage["password"])
+	require.Equal(t, `{"kdf":null}`, storage["encryption-key"])
+	require.Equal(t, "8007", storage["port"])
+	require.Equal(t, []any{"backup"}, d.Get("content").(*schema.Set).List())
+	require.Equal(t, 8007, d.Get("port"))
+
+	testFakeDelete(t, r, meta, d)
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceStorage_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource *schema.Resource
+		config   map[string]any
+	}{
+		{name: "invalid id", resource: resourceStorageDir(), config: map[string]any{"storage": "1st", "path": "/srv"}},
+		{name: "unsupported content", resource: resourceStorageLvm(), config: map[string]any{"storage": "lvm", "volume_group": "pve", "content": []any{"iso"}}},
+		{name: "keep all with keep last", resource: resourceStorageNfs(), config: map[string]any{"storage": "nfs", "server": "nas", "export": "/pve",
+			"retention": []any{map[string]any{"keep_all": true, "keep_last": 1}}}},
+		{name: "retention without backups", resource: resourceStorageZfsPool(), config: map[string]any{"storage": "zfs", "pool": "rpool/data",
+			"retention": []any{map[string]any{"keep_last": 1}}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := terraform.NewResourceConfigRaw(test.config)
+			diags := test.resource.Validate(config)
+			if !diags.HasError() {
+				_, err := test.resource.Diff(context.Background(), nil, config, nil)
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package proxmox
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceStorageZfsPool() *schema.Resource {
+	config := storageConfig{
+		storageType: "zfspool",
+		content:     storageGuestContent,
+		options: map[string]string{
+			"pool":      "pool",
+			"sparse":    "sparse",
+			"blocksize": "blocksize",
+		},
+	}
+	return config.resource(map[string]*schema.Schema{
+		"pool": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The ZFS pool or dataset, e.g. `rpool/data`.",
+		},
+		"sparse": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Create thin provisioned volumes.",
+		},
+		"blocksize": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The block size of new volumes, e.g. `16k`.",
+		},
+	})
+}
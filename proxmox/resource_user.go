@@ -0,0 +1,205 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/util"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const userResourceType = "user"
+
+func resourceUser() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceUserCreate,
+		ReadContext:   resourceUserRead,
+		UpdateContext: resourceUserUpdate,
+		DeleteContext: resourceUserDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"user_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateDiagFunc: func(i interface{}, path cty.Path) diag.Diagnostics {
+					var id pveSDK.UserID
+					return diag.FromErr(id.Parse(i.(string)))
+				},
+				Description: "The ID of the user in the format `name@realm`.",
+			},
+			"password": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validation.StringLenBetween(8, 64),
+				Description:  "The password of the user, only used by the `pve` realm. The password is not read back from Proxmox.",
+			},
+			"enable": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"expire": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "When the account expires as a unix timestamp, 0 never expires.",
+			},
+			"groups": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The groups the user is a member of.",
+			},
+			"first_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"last_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"email": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+func userConfig(d *schema.ResourceData) (pveSDK.ConfigUser, error) {
+	var id pveSDK.UserID
+	if err := id.Parse(d.Get("user_id").(string)); err != nil {
+		return pveSDK.ConfigUser{}, err
+	}
+	groups := make([]pveSDK.GroupName, 0)
+	for _, e := range d.Get("groups").(*schema.Set).List() {
+		groups = append(groups, pveSDK.GroupName(e.(string)))
+	}
+	return pveSDK.ConfigUser{
+		User:      id,
+		Comment:   util.Pointer(d.Get("comment").(string)),
+		Email:     util.Pointer(d.Get("email").(string)),
+		Enable:    util.Pointer(d.Get("enable").(bool)),
+		Expire:    util.Pointer(uint(d.Get("expire").(int))),
+		FirstName: util.Pointer(d.Get("first_name").(string)),
+		LastName:  util.Pointer(d.Get("last_name").(string)),
+		Groups:    &groups,
+	}, nil
+}
+
+func resourceUserCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	config, err := userConfig(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if v := d.Get("password").(string); v != "" {
+		config.Password = util.Pointer(pveSDK.UserPassword(v))
+	}
+	if err = pconf.NewClient.User.Create(ctx, config); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(clusterResourceId(userResourceType, config.User.String()))
+	return diag.FromErr(_resourceUserRead(ctx, d, pconf.NewClient))
+}
+
+func resourceUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return diag.FromErr(_resourceUserRead(ctx, d, pconf.NewClient))
+}
+
+func _resourceUserRead(ctx context.Context, d *schema.ResourceData, client *pveSDK.ClientNew) error {
+	_, rawID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+	var id pveSDK.UserID
+	if err = id.Parse(rawID); err != nil {
+		d.SetId("")
+		return fmt.Errorf("unexpected error when trying to read and parse resource id: %v", err)
+	}
+	exists, err := client.User.Exists(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		d.SetId("")
+		return nil
+	}
+	raw, err := client.User.Read(ctx, id)
+	if err != nil {
+		return err
+	}
+	groups := make([]string, 0)
+	for _, e := range *raw.GetGroups() {
+		groups = append(groups, e.String())
+	}
+	sort.Strings(groups)
+	d.Set("user_id", id.String())
+	d.Set("enable", raw.GetEnable())
+	d.Set("expire", int(raw.GetExpire()))
+	d.Set("groups", groups)
+	d.Set("first_name", raw.GetFirstName())
+	d.Set("last_name", raw.GetLastName())
+	d.Set("email", raw.GetEmail())
+	d.Set("comment", raw.GetComment())
+	return nil
+}
+
+func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	config, err := userConfig(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if d.HasChange("password") {
+		if v := d.Get("password").(string); v != "" {
+			config.Password = util.Pointer(pveSDK.UserPassword(v))
+		}
+	}
+	if err = pconf.NewClient.User.Update(ctx, config); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(_resourceUserRead(ctx, d, pconf.NewClient))
+}
+
+func resourceUserDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, rawID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var id pveSDK.UserID
+	if err = id.Parse(rawID); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(pconf.NewClient.User.Delete(ctx, id))
+}
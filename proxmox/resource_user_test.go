@@ -0,0 +1,117 @@
+package proxmox
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func testAccExampleAccess(comment string) string {
+	return `
+resource "proxmox_group" "team" {
+  name    = "team-a"
+  comment = "` + comment + `"
+}
+
+resource "proxmox_user" "ci" {
+  user_id = "ci@pve"
+  groups  = [proxmox_group.team.name]
+  comment = "` + comment + `"
+}
+
+resource "proxmox_role" "deploy" {
+  name       = "Deploy"
+  privileges = ["VM.Audit", "VM.PowerMgmt"]
+}
+
+resource "proxmox_api_token" "ci" {
+  user_id = proxmox_user.ci.user_id
+  name    = "pipeline"
+}
+
+resource "proxmox_acl" "team" {
+  path  = "/pool/team-a"
+  role  = proxmox_role.deploy.name
+  group = proxmox_group.team.name
+}
+`
+}
+
+func TestAccProxmoxAccess_Fake(t *testing.T) {
+	_, provider := testAccFakeProvider(t)
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProxmoxProviderFactory(),
+		Steps: []resource.TestStep{
+			{
+				Config: provider + testAccExampleAccess("first"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_user.ci", "id", "user/ci@pve"),
+					resource.TestCheckResourceAttr("proxmox_group.team", "members.0", "ci@pve"),
+					resource.TestCheckResourceAttr("proxmox_api_token.ci", "token_id", "ci@pve!pipeline"),
+					resource.TestCheckResourceAttrSet("proxmox_api_token.ci", "secret"),
+					resource.TestCheckResourceAttr("proxmox_acl.team", "id", "acl/group/team-a/Deploy/pool/team-a"),
+				),
+			},
+			{
+				Config: provider + testAccExampleAccess("second"),
+				Check:  resource.TestCheckResourceAttr("proxmox_user.ci", "comment", "second"),
+			},
+			{
+				ResourceName:      "proxmox_user.ci",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "proxmox_acl.team",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func Test_ResourceUser_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	for _, name := range []string{"admins", "devs"} {
+		testFakeCreate(t, resourceGroup(), meta, map[string]any{"name": name})
+	}
+	r := resourceUser()
+
+	config := map[string]any{
+		"user_id":    "alice@pve",
+		"password":   "correct-horse",
+		"groups":     []any{"devs"},
+		"first_name": "Alice",
+		"email":      "alice@example.com"}
+	d := testFakeCreate(t, r, meta, config)
+	require.Equal(t, "user/alice@pve", d.Id())
+	user, ok := fake.AccessUser("alice@pve")
+	require.True(t, ok)
+	require.Equal(t, "correct-horse", user["password"])
+	require.Equal(t, "devs", user["groups"])
+	require.Equal(t, "Alice", user["firstname"])
+	require.True(t, d.Get("enable").(bool))
+
+	config = map[string]any{
+		"user_id":  "alice@pve",
+		"password": "battery-staple",
+		"groups":   []any{"admins", "devs"},
+		"enable":   false,
+		"expire":   1900000000}
+	d = testFakeUpdate(t, r, meta, d, config)
+	user, _ = fake.AccessUser("alice@pve")
+	require.Equal(t, "battery-staple", user["password"])
+	require.Equal(t, "admins,devs", user["groups"])
+	require.Equal(t, "0", user["enable"])
+	require.Equal(t, "1900000000", user["expire"])
+	require.Equal(t, "", user["firstname"])
+	require.Equal(t, 1900000000, d.Get("expire"))
+	require.Equal(t, 2, d.Get("groups.#"))
+
+	testFakeDelete(t, r, meta, d)
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "", d.Id())
+	testFakeUnknown(t, fake)
+}
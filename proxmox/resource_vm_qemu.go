@@ -9,6 +9,7 @@ import (
 	"net"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -22,8 +23,10 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/description"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/migration"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/name"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/node"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/placementgroup"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/pool"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/powerstate"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/qemu/cloudinit"
@@ -32,6 +35,7 @@ import (
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/qemu/efi"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/qemu/network"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/qemu/pci"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/qemu/restore"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/qemu/rng"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/qemu/serial"
 	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/qemu/tpm"
@@ -86,8 +90,10 @@ func resourceVmQemu() *schema.Resource {
 					return d.HasChange("vm_state")
 				},
 			),
+			disk.CustomizeDiff(),
 			efi.CustomizeDiff(),
 			reboot.CustomizeDiff(),
+			placementGroupCustomizeDiff(),
 		),
 
 		Schema: map[string]*schema.Schema{
@@ -119,6 +125,8 @@ func resourceVmQemu() *schema.Resource {
 			node.Computed:          node.SchemaComputed("qemu"),
 			node.RootNode:          node.SchemaNode(schema.Schema{ConflictsWith: []string{node.RootNodes}}, "qemu"),
 			node.RootNodes:         node.SchemaNodes("qemu"),
+			node.RootStrategy:      node.SchemaStrategy("qemu"),
+			placementgroup.Root:    placementgroup.Schema("qemu"),
 			"bios": {
 				Type:             schema.TypeString,
 				Optional:         true,
@@ -179,6 +187,8 @@ func resourceVmQemu() *schema.Resource {
 				ForceNew: true,
 				Default:  true,
 			},
+			restore.Root:   restore.Schema(),
+			migration.Root: migration.SchemaQemu(),
 			"hastate": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -534,7 +544,7 @@ func resourceVmQemuCreate(ctx context.Context, d *schema.ResourceData, meta inte
 		StartupShutdown:  startupshutdown.SDK(d),
 		TPM:              tpm.SDK(d),
 		Tablet:           util.Pointer(d.Get("tablet").(bool)),
-		Tags:             tags.SDK(d),
+		Tags:             placementgroup.AddTag(tags.SDK(d), placementgroup.SDK(d)),
 	}
 
 	var diags, tmpDiags diag.Diagnostics
@@ -588,10 +598,14 @@ func resourceVmQemuCreate(ctx context.Context, d *schema.ResourceData, meta inte
 	var rebootRequired bool
 
 	if vmr == nil { // Create new VM
-		targetNode, err := node.SdkCreate(d)
+		targetNode, err := node.SdkCreate(d, nodePlacement(ctx, pconf, disk.Storage(d)), nodeGroupFilter(ctx, client, placementgroup.SDK(d), 0))
 		if err != nil {
 			return append(diags, diag.FromErr(err)...)
 		}
+		diags = append(diags, network.CheckBridges(config.Networks, guestBridges(ctx, client, targetNode))...)
+		if diags.HasError() {
+			return diags
+		}
 
 		config.Node = &targetNode
 
@@ -649,6 +663,37 @@ func resourceVmQemuCreate(ctx context.Context, d *schema.ResourceData, meta inte
 				return append(diags, diag.FromErr(err)...)
 			}
 
+		} else if restoreConfig := restore.SDK(d); restoreConfig != nil { // Restore from backup
+			if guestID == nil {
+				newID, err := client.GetNextID(ctx, nil)
+				if err != nil {
+					return append(diags, diag.FromErr(err)...)
+				}
+				guestID = &newID
+			}
+
+			log.Print("[DEBUG][QemuVmCreate] restoring VM from backup")
+			logger.Debug().Int(vmID.Root, int(*guestID)).Msgf("Restoring VM from '%s'", restoreConfig.Archive)
+			exitStatus, err := client.PostWithTask(ctx, restoreConfig.Params(*guestID), "/nodes/"+targetNode.String()+"/qemu")
+			if err != nil {
+				return append(diags, diag.FromErr(err)...)
+			}
+			diags = append(diags, taskWarnings(exitStatus, fmt.Sprintf("restored VM %d from '%s' with warnings", *guestID, restoreConfig.Archive))...)
+			vmr = pveSDK.NewVmRef(*guestID)
+			vmr.SetNode(targetNode.String())
+			vmr.SetVmType(pveSDK.GuestQemu)
+
+			log.Print("[DEBUG][QemuVmCreate] update VM after restore")
+			err = clientNew.QemuGuest.Update(ctx, *vmr, false, true, config)
+			if err != nil {
+				// Set the id because when update config fail the vm is still created
+				d.SetId(id.Guest{
+					ID:   vmr.VmId(),
+					Node: targetNode,
+					Type: id.GuestQemu}.String())
+				return append(diags, diag.FromErr(err)...)
+			}
+
 		} else if d.Get("pxe").(bool) { // PXE boot
 			var found bool
 			bs := d.Get("boot").(string)
@@ -688,10 +733,14 @@ func resourceVmQemuCreate(ctx context.Context, d *schema.ResourceData, meta inte
 	} else { // Forcefully update an existing VM
 		log.Printf("[DEBUG][QemuVmCreate] recycling VM vmId: %d", vmr.VmId())
 
-		targetNode, err := node.SdkUpdate(d, vmr.Node())
+		targetNode, err := node.SdkUpdate(d, vmr.Node(), nodePlacement(ctx, pconf, disk.Storage(d)), nodeGroupFilter(ctx, client, placementgroup.SDK(d), vmr.VmId()))
 		if err != nil {
 			return append(diags, diag.FromErr(err)...)
 		}
+		diags = append(diags, network.CheckBridges(config.Networks, guestBridges(ctx, client, targetNode))...)
+		if diags.HasError() {
+			return diags
+		}
 		if err = clientNew.Guest.Stop(ctx, *vmr, true); err != nil {
 			return append(diags, diag.FromErr(err)...)
 		}
@@ -711,6 +760,10 @@ func resourceVmQemuCreate(ctx context.Context, d *schema.ResourceData, meta inte
 		Type: id.GuestQemu}.String())
 	logger.Debug().Int(vmID.Root, int(vmr.VmId())).Msgf("Set this vm (resource Id) to '%v'", d.Id())
 
+	if err := resizeImportedDisks(ctx, client, vmr, d); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+
 	// give sometime to proxmox to catchup
 	time.Sleep(time.Duration(d.Get(schemaAdditionalWait).(int)) * time.Second)
 
@@ -774,13 +827,20 @@ func resourceVmQemuUpdate(ctx context.Context, d *schema.ResourceData, meta inte
 		StartupShutdown:  startupshutdown.SDK(d),
 		TPM:              tpm.SDK(d),
 		Tablet:           util.Pointer(d.Get("tablet").(bool)),
-		Tags:             tags.SDK(d),
+		Tags:             placementgroup.AddTag(tags.SDK(d), placementgroup.SDK(d)),
 	}
 
-	tmpNode, err := node.SdkUpdate(d, vmr.Node())
+	tmpNode, err := node.SdkUpdate(d, vmr.Node(), nodePlacement(ctx, pconf, disk.Storage(d)), nodeGroupFilter(ctx, client, placementgroup.SDK(d), vmr.VmId()))
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	if tmpNode != vmr.Node() {
+		if err = guestMigrate(ctx, client, vmr, tmpNode, migration.SDK(d)); err != nil {
+			// Keep the node the guest is on in the state.
+			node.Terraform(vmr.Node(), d)
+			return diag.FromErr(err)
+		}
+	}
 	config.Node = &tmpNode
 
 	if len(qemuVgaList) > 0 {
@@ -797,6 +857,10 @@ func resourceVmQemuUpdate(ctx context.Context, d *schema.ResourceData, meta inte
 	if tmpDiags.HasError() {
 		return diags
 	}
+	diags = append(diags, network.CheckBridges(config.Networks, guestBridges(ctx, client, tmpNode))...)
+	if diags.HasError() {
+		return diags
+	}
 	config.PciDevices, tmpDiags = pci.SDK(d)
 	diags = append(diags, tmpDiags...)
 	if tmpDiags.HasError() {
@@ -835,6 +899,10 @@ func resourceVmQemuUpdate(ctx context.Context, d *schema.ResourceData, meta inte
 		return append(diags, diag.FromErr(err)...)
 	}
 
+	if err = resizeImportedDisks(ctx, client, vmr, d); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+
 	// We only have to handle the running state.
 	// The SDK will handle the stopped state correctly by shutting down the VM before applying the changes.
 	if desiredState != nil && *desiredState == pveSDK.PowerStateRunning && *config.State != pveSDK.PowerStateRunning {
@@ -960,7 +1028,7 @@ func resourceVmQemuRead(ctx context.Context, d *schema.ResourceData, vmr *pveSDK
 	d.Set("hastate", vmr.HaState())
 	d.Set("hagroup", vmr.HaGroup())
 	d.Set("qemu_os", config.QemuOs)
-	tags.Terraform(config.Tags, d)
+	tags.Terraform(placementgroup.Terraform(config.Tags, d), d)
 	d.Set("args", config.Args)
 	d.Set("smbios", ReadSmbiosArgs(config.Smbios1))
 	d.Set("linked_vmid", config.LinkedID)
@@ -1034,6 +1102,23 @@ func resourceVmQemuDelete(ctx context.Context, d *schema.ResourceData, meta any)
 	return guestDelete(ctx, d, meta, "Qemu")
 }
 
+// Disks imported from an image are created with the size of the image, grow them to the configured size.
+// Resizing a disk to its current size is a no-op, so this is safe to call for disks that already exist.
+func resizeImportedDisks(ctx context.Context, client *pveSDK.Client, vmr *pveSDK.VmRef, d *schema.ResourceData) error {
+	sizes := disk.ImportedSizes(d)
+	slots := make([]string, 0, len(sizes))
+	for slot := range sizes {
+		slots = append(slots, slot)
+	}
+	sort.Strings(slots)
+	for _, slot := range slots {
+		if _, err := client.ResizeQemuDiskRaw(ctx, vmr, slot, sizes[slot]); err != nil {
+			return fmt.Errorf("unable to resize imported disk %s to %s, the size must be at least the size of the image: %w", slot, sizes[slot], err)
+		}
+	}
+	return nil
+}
+
 // Converting from schema.TypeSet to map of id and conf for each device,
 // which will be sent to Proxmox API.
 func DevicesSetToMap(devicesSet *schema.Set) (pveSDK.QemuDevices, error) {
@@ -0,0 +1,384 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/qemu/disk"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/qemu/network"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/guest/vmid"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/resource/id"
+	"github.com/Telmate/terraform-provider-proxmox/v2/proxmox/Internal/util"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// resourceVmQemuTemplate builds a VM and converts it to a template.
+// PVE only allows a few settings of a template to change, so everything that defines the VM forces a new template.
+func resourceVmQemuTemplate() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceVmQemuTemplateCreate,
+		ReadContext:   resourceVmQemuTemplateRead,
+		UpdateContext: resourceVmQemuTemplateUpdate,
+		DeleteContext: resourceVmQemuTemplateDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: resourceVmQemuTemplateCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"target_node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the template is created on.",
+			},
+			vmid.Root: vmid.Schema(),
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the template, which clones can reference.",
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"protection": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Protect the template and its disks from being removed outside of Terraform.",
+			},
+			"bios": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "seabios",
+				ValidateDiagFunc: BIOSValidator(),
+			},
+			"machine": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: MachineTypeValidator(),
+			},
+			"qemu_os": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "l26",
+			},
+			"agent": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+			},
+			"memory": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      512,
+				ValidateFunc: validation.IntAtLeast(16),
+			},
+			"cores": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      1,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"sockets": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      1,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"cpu_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"scsihw": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"lsi", "lsi53c810", "megasas", "pvscsi", "virtio-scsi-pci", "virtio-scsi-single"}, false),
+			},
+			"boot": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			disk.RootDisks: vmQemuTemplateDisksSchema(),
+			network.Root:   network.Schema(),
+		},
+		Timeouts: resourceTimeouts(),
+	}
+}
+
+// vmQemuTemplateDisksSchema returns the disks schema of proxmox_vm_qemu, a template needs at least one disk.
+func vmQemuTemplateDisksSchema() *schema.Schema {
+	s := disk.SchemaDisks()
+	s.Optional = false
+	s.Required = true
+	return s
+}
+
+// resourceVmQemuTemplateCustomizeDiff replaces the template when its disks or network devices change.
+// Their schemas are shared with proxmox_vm_qemu, which changes them in place, so they don't force a new resource themselves.
+func resourceVmQemuTemplateCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	for _, prefix := range []string{disk.RootDisks, network.Root} {
+		for _, key := range d.GetChangedKeysPrefix(prefix) {
+			if strings.HasSuffix(key, ".#") || !d.HasChange(key) {
+				continue
+			}
+			if err := d.ForceNew(key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// vmQemuTemplateConfig returns the config of the VM the template is converted from.
+func vmQemuTemplateConfig(ctx context.Context, d *schema.ResourceData, client *pveSDK.Client) (pveSDK.ConfigQemu, diag.Diagnostics) {
+	config := pveSDK.ConfigQemu{
+		Agent: &pveSDK.QemuGuestAgent{Enable: util.Pointer(d.Get("agent").(bool))},
+		Bios:  d.Get("bios").(string),
+		Boot:  d.Get("boot").(string),
+		CPU: &pveSDK.QemuCPU{
+			Cores:   util.Pointer(pveSDK.QemuCpuCores(d.Get("cores").(int))),
+			Sockets: util.Pointer(pveSDK.QemuCpuSockets(d.Get("sockets").(int)))},
+		Machine:    d.Get("machine").(string),
+		Memory:     &pveSDK.QemuMemory{CapacityMiB: util.Pointer(pveSDK.QemuMemoryCapacity(d.Get("memory").(int)))},
+		Name:       util.Pointer(pveSDK.GuestName(d.Get("name").(string))),
+		Protection: util.Pointer(false),
+		QemuOs:     d.Get("qemu_os").(string),
+		Scsihw:     d.Get("scsihw").(string),
+	}
+	if v := d.Get("cpu_type").(string); v != "" {
+		config.CPU.Type = util.Pointer(pveSDK.CpuType(v))
+	}
+	if v := d.Get("description").(string); v != "" {
+		config.Description = &v
+	}
+
+	var diags, tmpDiags diag.Diagnostics
+	config.Disks, diags = disk.SDK(d)
+	if diags.HasError() {
+		return config, diags
+	}
+	config.Networks, tmpDiags = network.SDK(d)
+	diags = append(diags, tmpDiags...)
+	if tmpDiags.HasError() {
+		return config, diags
+	}
+	return config, append(diags, network.CheckBridges(config.Networks, guestBridges(ctx, client, pveSDK.NodeName(d.Get("target_node").(string))))...)
+}
+
+func resourceVmQemuTemplateCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client := pconf.Client
+	node := pveSDK.NodeName(d.Get("target_node").(string))
+	config, diags := vmQemuTemplateConfig(ctx, d, client)
+	if diags.HasError() {
+		return diags
+	}
+	config.Node = &node
+	if guestID := vmid.Get(d); guestID != 0 {
+		config.ID = &guestID
+	}
+
+	vmr, err := pconf.NewClient.QemuGuest.Create(ctx, config)
+	if err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	// From here on the VM exists, so it is tracked even when converting it fails.
+	d.SetId(id.Guest{
+		ID:   vmr.VmId(),
+		Node: node,
+		Type: id.GuestQemu}.String())
+
+	if err = resizeImportedDisks(ctx, client, vmr, d); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	if err = client.CreateTemplate(ctx, vmr); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	if d.Get("protection").(bool) {
+		if err = client.Put(ctx, map[string]interface{}{"protection": true}, "/nodes/"+node.String()+"/qemu/"+vmr.VmId().String()+"/config"); err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+	}
+	return append(diags, diag.FromErr(_resourceVmQemuTemplateRead(ctx, d, meta))...)
+}
+
+func resourceVmQemuTemplateUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	var guestID id.Guest
+	if err := guestID.Parse(d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+	vmr := pveSDK.NewVmRef(guestID.ID)
+	if err := pconf.Client.CheckVmRef(ctx, vmr); err != nil {
+		return diag.FromErr(err)
+	}
+	params := map[string]interface{}{"protection": d.Get("protection").(bool)}
+	if description := d.Get("description").(string); description != "" {
+		params["description"] = description
+	} else {
+		params["delete"] = "description"
+	}
+	if err := pconf.Client.Put(ctx, params, "/nodes/"+vmr.Node().String()+"/qemu/"+guestID.ID.String()+"/config"); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(_resourceVmQemuTemplateRead(ctx, d, meta))
+}
+
+func resourceVmQemuTemplateRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	var guestID id.Guest
+	if err := guestID.Parse(d.Id()); err != nil {
+		d.SetId("")
+		return diag.Errorf("unexpected error when trying to read and parse the resource: %v", err)
+	}
+	ok, err := guestID.ID.Exists(ctx, pconf.Client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if !ok {
+		return diag.Diagnostics{resourceDriftDeletionDiagnostic(d)}
+	}
+	return diag.FromErr(_resourceVmQemuTemplateRead(ctx, d, meta))
+}
+
+func _resourceVmQemuTemplateRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	client := pconf.Client
+
+	var guestID id.Guest
+	if err := guestID.Parse(d.Id()); err != nil {
+		return err
+	}
+	vmr := pveSDK.NewVmRef(guestID.ID)
+	if err := client.CheckVmRef(ctx, vmr); err != nil {
+		return err
+	}
+	if vmr.GetVmType() != pveSDK.GuestQemu {
+		return fmt.Errorf("guest %d is not a QEMU VM", guestID.ID)
+	}
+	raw, _, err := pveSDK.NewActiveRawConfigQemuFromApi(ctx, vmr, client)
+	if err != nil {
+		return err
+	}
+	config, err := raw.Get(*vmr)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(id.Guest{
+		ID:   guestID.ID,
+		Node: vmr.Node(),
+		Type: id.GuestQemu}.String())
+	// The template may have been migrated, only set the node when importing so the configured node is kept.
+	if d.Get("target_node").(string) == "" {
+		d.Set("target_node", vmr.Node().String())
+	}
+	vmid.Terraform(guestID.ID, d)
+	if config.Name != nil {
+		d.Set("name", string(*config.Name))
+	}
+	if config.Description != nil {
+		d.Set("description", *config.Description)
+	}
+	if config.Protection != nil {
+		d.Set("protection", *config.Protection)
+	}
+	if config.Bios != "" {
+		d.Set("bios", config.Bios)
+	} else {
+		d.Set("bios", "seabios")
+	}
+	d.Set("machine", config.Machine)
+	if config.QemuOs != "" {
+		d.Set("qemu_os", config.QemuOs)
+	} else {
+		d.Set("qemu_os", "other")
+	}
+	d.Set("agent", config.Agent != nil && config.Agent.Enable != nil && *config.Agent.Enable)
+	if config.Memory != nil && config.Memory.CapacityMiB != nil {
+		d.Set("memory", int(*config.Memory.CapacityMiB))
+	}
+	if config.CPU != nil {
+		if config.CPU.Cores != nil {
+			d.Set("cores", int(*config.CPU.Cores))
+		}
+		if config.CPU.Sockets != nil {
+			d.Set("sockets", int(*config.CPU.Sockets))
+		}
+		cpuType := ""
+		if config.CPU.Type != nil {
+			cpuType = string(*config.CPU.Type)
+		}
+		d.Set("cpu_type", cpuType)
+	}
+	d.Set("scsihw", config.Scsihw)
+	d.Set("boot", config.Boot)
+	if config.Disks != nil {
+		var ciDisk bool
+		disk.Terraform_Unsafe(d, config.Disks, &ciDisk)
+	}
+	network.Terraform(config.Networks, d)
+	return nil
+}
+
+func resourceVmQemuTemplateDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	var guestID id.Guest
+	if err := guestID.Parse(d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+	ok, err := guestID.ID.Exists(ctx, pconf.Client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if !ok {
+		return nil
+	}
+	vmr := pveSDK.NewVmRef(guestID.ID)
+	if err = pconf.Client.CheckVmRef(ctx, vmr); err != nil {
+		return diag.FromErr(err)
+	}
+	// Protection has to be lifted first, it only guards against removal outside of Terraform.
+	if err = pconf.Client.Put(ctx, map[string]interface{}{"protection": false}, "/nodes/"+vmr.Node().String()+"/qemu/"+guestID.ID.String()+"/config"); err != nil {
+		return diag.FromErr(err)
+	}
+	if _, err = pconf.NewClient.Guest.Delete(ctx, *vmr); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
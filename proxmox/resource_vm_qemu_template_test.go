@@ -0,0 +1,198 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func testAccExampleQemuTemplate(description string) string {
+	return fmt.Sprintf(`
+resource "proxmox_vm_qemu_template" "test" {
+  name        = "ubuntu-template"
+  target_node = "pve"
+  description = "%s"
+  memory      = 1024
+  agent       = true
+  scsihw      = "virtio-scsi-single"
+
+  disks {
+    ide {
+      ide2 {
+        cloudinit {
+          storage = "local-lvm"
+        }
+      }
+    }
+    scsi {
+      scsi0 {
+        disk {
+          storage     = "local-lvm"
+          import_from = "local:import/noble-server-cloudimg-amd64.qcow2"
+          size        = "10G"
+          iothread    = true
+        }
+      }
+    }
+  }
+
+  network {
+    id     = 0
+    model  = "virtio"
+    bridge = "vmbr0"
+  }
+}
+
+resource "proxmox_vm_qemu" "clone" {
+  name        = "clone"
+  target_node = "pve"
+  clone_id    = proxmox_vm_qemu_template.test.vmid
+  agent       = 1
+  memory      = 1024
+  scsihw      = "virtio-scsi-single"
+  disks {
+    scsi {
+      scsi0 {
+        disk {
+          size     = "10G"
+          storage  = "local-lvm"
+          iothread = true
+        }
+      }
+    }
+  }
+}
+`, description)
+}
+
+func TestAccProxmoxVmQemuTemplate_Fake(t *testing.T) {
+	fake, provider := testAccFakeProvider(t)
+	fake.PutFile("pve", "local", "import", "noble-server-cloudimg-amd64.qcow2", []byte("qcow2 image"))
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProxmoxProviderFactory(),
+		Steps: []resource.TestStep{
+			{
+				Config: provider + testAccExampleQemuTemplate("golden image"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_vm_qemu_template.test", "id", "pve/qemu/100"),
+					resource.TestCheckResourceAttr("proxmox_vm_qemu_template.test", "vmid", "100"),
+					resource.TestCheckResourceAttr("proxmox_vm_qemu_template.test", "disks.0.scsi.0.scsi0.0.disk.0.size", "10G"),
+					resource.TestCheckResourceAttr("proxmox_vm_qemu_template.test", "protection", "true"),
+					resource.TestCheckResourceAttr("proxmox_vm_qemu.clone", "id", "pve/qemu/101"),
+				),
+			},
+			{
+				Config: provider + testAccExampleQemuTemplate("golden image v2"),
+				Check:  resource.TestCheckResourceAttr("proxmox_vm_qemu_template.test", "id", "pve/qemu/100"),
+			},
+			{
+				ResourceName:            "proxmox_vm_qemu_template.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"disks.0.scsi.0.scsi0.0.disk.0.import_from"},
+			},
+		},
+	})
+}
+
+func Test_ResourceVmQemuTemplate_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceVmQemuTemplate()
+
+	config := map[string]any{
+		"name":        "debian-template",
+		"target_node": "pve",
+		"vmid":        9000,
+		"disks": []any{map[string]any{
+			"ide": []any{map[string]any{"ide2": []any{map[string]any{
+				"cloudinit": []any{map[string]any{"storage": "local-lvm"}}}}}},
+			"scsi": []any{map[string]any{"scsi1": []any{map[string]any{
+				"disk": []any{map[string]any{"storage": "local-lvm", "size": "2G"}}}}}},
+			"virtio": []any{map[string]any{"virtio0": []any{map[string]any{
+				"disk": []any{map[string]any{"storage": "local-lvm", "size": "512M", "discard": true}}}}}}}},
+		"network": []any{map[string]any{"id": 0, "model": "virtio", "bridge": "vmbr0", "tag": 20}}}
+	d := testFakeCreate(t, r, meta, config)
+	require.Equal(t, "pve/qemu/9000", d.Id())
+	require.Equal(t, 9000, d.Get("vmid"))
+	require.Equal(t, "512M", d.Get("disks.0.virtio.0.virtio0.0.disk.0.size"))
+	require.True(t, d.Get("disks.0.virtio.0.virtio0.0.disk.0.discard").(bool))
+	require.Equal(t, "2G", d.Get("disks.0.scsi.0.scsi1.0.disk.0.size"))
+	require.Equal(t, "local-lvm", d.Get("disks.0.ide.0.ide2.0.cloudinit.0.storage"))
+	require.Equal(t, 20, d.Get("network.0.tag"))
+	require.Equal(t, "virtio", d.Get("network.0.model"))
+
+	// The disks and network devices can't change in place, the shared schemas are forced to replace the template.
+	state := d.State()
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), meta)
+	require.NoError(t, err)
+	require.True(t, diff == nil || diff.Empty(), diff)
+	changed := map[string]any{}
+	for k, v := range config {
+		changed[k] = v
+	}
+	changed["network"] = []any{map[string]any{"id": 0, "model": "virtio", "bridge": "vmbr0", "tag": 30}}
+	diff, err = r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(changed), meta)
+	require.NoError(t, err)
+	require.True(t, diff.RequiresNew(), diff)
+	changed["network"] = config["network"]
+	changed["disks"] = []any{map[string]any{
+		"virtio": []any{map[string]any{"virtio0": []any{map[string]any{
+			"disk": []any{map[string]any{"storage": "local-lvm", "size": "1G", "discard": true}}}}}}}}
+	diff, err = r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(changed), meta)
+	require.NoError(t, err)
+	require.True(t, diff.RequiresNew(), diff)
+
+	vmConfig, err := meta.Client.GetVmConfig(context.Background(), pveSDK.NewVmRef(9000))
+	require.NoError(t, err)
+	require.EqualValues(t, 1, vmConfig["template"])
+	require.EqualValues(t, 1, vmConfig["protection"])
+
+	// Only the description and the protection are changed in place.
+	config["description"] = "base image"
+	config["protection"] = false
+	d = testFakeUpdate(t, r, meta, d, config)
+	require.Equal(t, "base image", d.Get("description"))
+	vmConfig, err = meta.Client.GetVmConfig(context.Background(), pveSDK.NewVmRef(9000))
+	require.NoError(t, err)
+	require.EqualValues(t, 0, vmConfig["protection"])
+
+	// Protection is lifted before the template is removed.
+	config["protection"] = true
+	d = testFakeUpdate(t, r, meta, d, config)
+	testFakeDelete(t, r, meta, d)
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "", d.Id())
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceVmQemuTemplate_Validation(t *testing.T) {
+	r := resourceVmQemuTemplate()
+	tests := []struct {
+		name   string
+		config map[string]any
+	}{
+		{name: "no disks", config: map[string]any{"name": "template", "target_node": "pve"}},
+		{name: "missing size", config: map[string]any{"name": "template", "target_node": "pve", "disks": []any{map[string]any{
+			"scsi": []any{map[string]any{"scsi0": []any{map[string]any{
+				"disk": []any{map[string]any{"storage": "local-lvm"}}}}}}}}}},
+		{name: "network model", config: map[string]any{"name": "template", "target_node": "pve", "network": []any{map[string]any{"id": 0}},
+			"disks": []any{map[string]any{"scsi": []any{map[string]any{"scsi0": []any{map[string]any{
+				"disk": []any{map[string]any{"storage": "local-lvm", "size": "1G"}}}}}}}}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := terraform.NewResourceConfigRaw(test.config)
+			diags := r.Validate(config)
+			if !diags.HasError() {
+				_, err := r.Diff(context.Background(), nil, config, nil)
+				require.Error(t, err)
+			}
+		})
+	}
+}
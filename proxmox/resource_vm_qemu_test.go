@@ -1,14 +1,20 @@
 package proxmox
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
 	"testing"
 
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
 )
 
 // TODO is there a better place for this config?
@@ -398,3 +404,367 @@ func TestAccProxmoxVmQemu_UpdateRebootRequired(t *testing.T) {
 		},
 	})
 }
+
+func testAccExampleQemuFake(name string, memory int) string {
+	return fmt.Sprintf(`
+resource "proxmox_vm_qemu" "test" {
+  name        = "%s"
+  target_node = "pve"
+  memory      = %d
+  agent       = 0
+  disks {
+    scsi {
+      scsi0 {
+        disk {
+          size    = "10G"
+          storage = "local-lvm"
+        }
+      }
+    }
+  }
+}
+`, name, memory)
+}
+
+// TestAccProxmoxVmQemu_Fake runs a create, update and import cycle against the in-process fake API.
+func TestAccProxmoxVmQemu_Fake(t *testing.T) {
+	_, provider := testAccFakeProvider(t)
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProxmoxProviderFactory(),
+		Steps: []resource.TestStep{
+			{
+				Config: provider + testAccExampleQemuFake("test-vm", 512),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_vm_qemu.test", "id", "pve/qemu/100"),
+					resource.TestCheckResourceAttr("proxmox_vm_qemu.test", "memory", "512"),
+					resource.TestCheckResourceAttr("proxmox_vm_qemu.test", "current_node", "pve"),
+				),
+			},
+			{
+				Config: provider + testAccExampleQemuFake("test-vm", 1024),
+				Check:  resource.TestCheckResourceAttr("proxmox_vm_qemu.test", "memory", "1024"),
+			},
+		},
+	})
+}
+
+func Test_ResourceVmQemu_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	meta := testFakeMeta(t, fake)
+	r := resourceVmQemu()
+
+	d := testFakeCreate(t, r, meta, map[string]any{
+		"name":        "test-vm",
+		"target_node": "pve",
+		"agent":       0,
+		"disks": []any{map[string]any{"scsi": []any{map[string]any{"scsi0": []any{map[string]any{
+			"disk": []any{map[string]any{"size": "10G", "storage": "local-lvm"}}}}}}}}})
+	require.Equal(t, "pve/qemu/100", d.Id())
+	require.Equal(t, "pve", d.Get("current_node"))
+	require.Equal(t, "10G", d.Get("disks.0.scsi.0.scsi0.0.disk.0.size"))
+
+	testFakeDelete(t, r, meta, d)
+	testFakeRead(t, r, meta, d)
+	require.Equal(t, "", d.Id())
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceVmQemu_Migration_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t, "pve1", "pve2")
+	meta := testFakeMeta(t, fake)
+	r := resourceVmQemu()
+
+	config := map[string]any{
+		"name":        "test-vm",
+		"target_node": "pve1",
+		"agent":       0,
+		"disks": []any{map[string]any{"scsi": []any{map[string]any{"scsi0": []any{map[string]any{
+			"disk": []any{map[string]any{"size": "10G", "storage": "local-lvm"}}}}}}}},
+		"migration": []any{map[string]any{"with_local_disks": false}}}
+	d := testFakeCreate(t, r, meta, config)
+	require.Equal(t, "pve1/qemu/100", d.Id())
+
+	// The local disk is not copied, so the migration fails and the guest stays where it is.
+	config["target_node"] = "pve2"
+	state := d.State()
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), meta)
+	require.NoError(t, err)
+	state, diags := r.Apply(context.Background(), state, diff, meta)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[0].Summary, "the guest is on node 'pve1'")
+	require.Contains(t, diags[0].Summary, "can't migrate local disk 'local-lvm:vm-100-disk-")
+	require.Equal(t, "pve1", r.Data(state).Get("target_node"))
+
+	config["migration"] = []any{map[string]any{"bandwidth_limit": 10240, "network": "10.0.0.0/24", "type": "insecure"}}
+	d = testFakeUpdate(t, r, meta, r.Data(state), config)
+	require.Equal(t, "pve2/qemu/100", d.Id())
+	require.Equal(t, "pve2", d.Get("current_node"))
+	migrations := fake.Migrations(100)
+	require.Len(t, migrations, 2)
+	require.Equal(t, map[string]string{
+		"target":            "pve2",
+		"online":            "1",
+		"with-local-disks":  "1",
+		"bwlimit":           "10240",
+		"migration_network": "10.0.0.0/24",
+		"migration_type":    "insecure"}, migrations[1])
+
+	testFakeDelete(t, r, meta, d)
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceVmQemu_PlacementStrategy_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t, "pve1", "pve2", "pve3")
+	const gib = 1024 * 1024 * 1024
+	// pve3 uses the most memory right now, but the least over the last hour.
+	fake.SetNodeMemory("pve1", 30*gib, 30*gib, 30*gib)
+	fake.SetNodeMemory("pve2", 60*gib, 60*gib, 1*gib)
+	fake.SetNodeMemory("pve3", 10*gib, 10*gib, 50*gib)
+	meta := testFakeMeta(t, fake)
+	r := resourceVmQemu()
+
+	vmConfig := func(name string, nodes ...any) map[string]any {
+		return map[string]any{
+			"name":               name,
+			"target_nodes":       nodes,
+			"placement_strategy": "memory",
+			"agent":              0,
+			"additional_wait":    0,
+			"disks": []any{map[string]any{"scsi": []any{map[string]any{"scsi0": []any{map[string]any{
+				"disk": []any{map[string]any{"size": "10G", "storage": "local-lvm"}}}}}}}}}
+	}
+	first := vmConfig("first", "pve1", "pve2", "pve3")
+	d1 := testFakeCreate(t, r, meta, first)
+	require.Equal(t, "pve3/qemu/100", d1.Id())
+
+	// Offline nodes are skipped.
+	fake.SetNodeOnline("pve3", false)
+	d2 := testFakeCreate(t, r, meta, vmConfig("second", "pve1", "pve2", "pve3"))
+	require.Equal(t, "pve1/qemu/101", d2.Id())
+	fake.SetNodeOnline("pve3", true)
+
+	// A guest on a node that is still in target_nodes stays there.
+	first["placement_strategy"] = "guests"
+	d1 = testFakeUpdate(t, r, meta, d1, first)
+	require.Equal(t, "pve3/qemu/100", d1.Id())
+	require.Empty(t, fake.Migrations(100))
+
+	// Otherwise it moves to the node with the fewest guests.
+	first["target_nodes"] = []any{"pve1", "pve2"}
+	d1 = testFakeUpdate(t, r, meta, d1, first)
+	require.Equal(t, "pve2/qemu/100", d1.Id())
+	require.Equal(t, "pve2", d1.Get("current_node"))
+
+	// The storage strategy needs the storage of the disk on one of the nodes.
+	fake.SetNodeOnline("pve1", false)
+	fake.SetNodeOnline("pve2", false)
+	third := vmConfig("third", "pve1", "pve2")
+	third["placement_strategy"] = "storage"
+	diff, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(third), meta)
+	require.NoError(t, err)
+	_, diags := r.Apply(context.Background(), nil, diff, meta)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[len(diags)-1].Summary, "none of the nodes in target_nodes is online and has the storage of the guest")
+	fake.SetNodeOnline("pve1", true)
+	fake.SetNodeOnline("pve2", true)
+
+	testFakeDelete(t, r, meta, d1)
+	testFakeDelete(t, r, meta, d2)
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceVmQemu_PlacementGroup_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t, "pve1", "pve2")
+	meta := testFakeMeta(t, fake)
+	r := resourceVmQemu()
+
+	vmConfig := func(name, group, policy string) map[string]any {
+		return map[string]any{
+			"name":            name,
+			"target_nodes":    []any{"pve1", "pve2"},
+			"agent":           0,
+			"additional_wait": 0,
+			"tags":            "prod",
+			"placement_group": []any{map[string]any{"name": group, "policy": policy}}}
+	}
+	db1 := vmConfig("db1", "db", "anti-affinity")
+	d1 := testFakeCreate(t, r, meta, db1)
+	require.Equal(t, "db", d1.Get("placement_group.0.name"))
+	require.Equal(t, "prod", d1.Get("tags"))
+	vmConfig1, err := meta.Client.GetVmConfig(context.Background(), pveSDK.NewVmRef(100))
+	require.NoError(t, err)
+	require.Equal(t, "pg.anti-affinity.db,prod", vmConfig1["tags"])
+
+	d2 := testFakeCreate(t, r, meta, vmConfig("db2", "db", "anti-affinity"))
+	require.NotEqual(t, d1.Get("current_node"), d2.Get("current_node"))
+
+	// The guest does not conflict with itself.
+	db1["description"] = "primary"
+	d1 = testFakeUpdate(t, r, meta, d1, db1)
+	require.Equal(t, "primary", d1.Get("description"))
+
+	// Both nodes already have a member of the group.
+	_, err = r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(vmConfig("db3", "db", "anti-affinity")), meta)
+	require.ErrorContains(t, err, "placement_group 'db' with policy 'anti-affinity' can not be satisfied")
+	_, err = r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(vmConfig("db3", "db", "affinity")), meta)
+	require.ErrorContains(t, err, "but guest 100 is in the group with policy 'anti-affinity'")
+
+	web1 := testFakeCreate(t, r, meta, vmConfig("web1", "web", "affinity"))
+	web2 := testFakeCreate(t, r, meta, vmConfig("web2", "web", "affinity"))
+	require.Equal(t, web1.Get("current_node"), web2.Get("current_node"))
+	// The other members of the group are on a node that is not allowed.
+	web3 := vmConfig("web3", "web", "affinity")
+	delete(web3, "target_nodes")
+	web3["target_node"] = "pve1"
+	if web1.Get("current_node") == "pve1" {
+		web3["target_node"] = "pve2"
+	}
+	_, err = r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(web3), meta)
+	require.ErrorContains(t, err, "placement_group 'web' with policy 'affinity' can not be satisfied")
+
+	for _, d := range []*schema.ResourceData{d1, d2, web1, web2} {
+		testFakeDelete(t, r, meta, d)
+	}
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceVmQemu_ImportFrom_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	fake.PutFile("pve", "local", "import", "noble-server-cloudimg-amd64.qcow2", make([]byte, 2<<20))
+	meta := testFakeMeta(t, fake)
+	r := resourceVmQemu()
+
+	image := "local:import/noble-server-cloudimg-amd64.qcow2"
+	config := func(scsi map[string]any) map[string]any {
+		return map[string]any{
+			"name":        "cloud-vm",
+			"target_node": "pve",
+			"agent":       0,
+			"disks": []any{map[string]any{
+				"scsi": []any{scsi},
+				"virtio": []any{map[string]any{"virtio0": []any{map[string]any{
+					"disk": []any{map[string]any{"size": "20M", "storage": "local-lvm", "import_from": image}}}}}}}}}
+	}
+	scsi0 := []any{map[string]any{"disk": []any{map[string]any{"size": "10G", "storage": "local-lvm", "import_from": image}}}}
+	d := testFakeCreate(t, r, meta, config(map[string]any{"scsi0": scsi0}))
+	require.Equal(t, "pve/qemu/100", d.Id())
+	// The disks are grown from the size of the image to the configured size.
+	require.Equal(t, "10G", d.Get("disks.0.scsi.0.scsi0.0.disk.0.size"))
+	require.Equal(t, "20M", d.Get("disks.0.virtio.0.virtio0.0.disk.0.size"))
+	// PVE does not return where a disk was imported from.
+	require.Equal(t, image, d.Get("disks.0.scsi.0.scsi0.0.disk.0.import_from"))
+	vmConfig, err := meta.Client.GetVmConfig(context.Background(), pveSDK.NewVmRef(100))
+	require.NoError(t, err)
+	require.NotContains(t, vmConfig["scsi0"], "import-from")
+
+	// The image of an existing disk can't change, a new disk can still be imported.
+	_, err = r.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(config(map[string]any{"scsi0": []any{map[string]any{
+		"disk": []any{map[string]any{"size": "10G", "storage": "local-lvm", "import_from": "local:import/jammy.qcow2"}}}}})), meta)
+	require.ErrorContains(t, err, "import_from can't be changed once the disk exists")
+	scsi1 := []any{map[string]any{"disk": []any{map[string]any{"size": "5G", "storage": "local-lvm", "import_from": image}}}}
+	_, err = r.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(config(map[string]any{"scsi0": scsi0, "scsi1": scsi1})), meta)
+	require.NoError(t, err)
+
+	// Only the new disk is resized, the disks imported by an earlier apply are left alone.
+	resizes := fake.Resizes(100)
+	require.Contains(t, resizes, "scsi0=10G")
+	require.Contains(t, resizes, "virtio0=20M")
+	d = testFakeUpdate(t, r, meta, d, config(map[string]any{"scsi0": scsi0, "scsi1": scsi1}))
+	resizes = fake.Resizes(100)[len(resizes):]
+	require.Contains(t, resizes, "scsi1=5G")
+	require.NotContains(t, resizes, "scsi0=10G")
+	require.NotContains(t, resizes, "virtio0=20M")
+	require.Equal(t, "5G", d.Get("disks.0.scsi.0.scsi1.0.disk.0.size"))
+
+	testFakeDelete(t, r, meta, d)
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceVmQemu_Restore_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	fake.AddStorage("backup", "dir", true, "backup")
+	archive := fake.PutBackup("pve", "backup", "qemu", 200, map[string]string{
+		"name":   "original",
+		"memory": "2048",
+		"scsi0":  "local-lvm:vm-200-disk-0,iothread=1,size=10G",
+		"net0":   "virtio=BC:24:11:00:00:01,bridge=vmbr0"})
+	meta := testFakeMeta(t, fake)
+	r := resourceVmQemu()
+
+	// A restore that finishes with warnings, e.g. for a skipped disk, still created the VM.
+	fake.FailTask("qmrestore", "WARNINGS: 1")
+	d := testFakeCreate(t, r, meta, map[string]any{
+		"name":        "restored-vm",
+		"target_node": "pve",
+		"agent":       0,
+		"memory":      1024,
+		// Disks and network devices of the backup that are not declared are removed, the same as for clones.
+		"disks": []any{map[string]any{"scsi": []any{map[string]any{"scsi0": []any{map[string]any{
+			"disk": []any{map[string]any{"size": "10G", "storage": "local-lvm", "iothread": true}}}}}}}},
+		"network": []any{map[string]any{"id": 0, "model": "virtio", "bridge": "vmbr0"}},
+		"restore": []any{map[string]any{
+			"archive": archive,
+			"storage": "local-lvm",
+			"unique":  true}}})
+	require.Equal(t, "pve/qemu/100", d.Id())
+	require.Equal(t, "restored-vm", d.Get("name"))
+	require.Equal(t, 1024, d.Get("memory"))
+	require.Equal(t, "10G", d.Get("disks.0.scsi.0.scsi0.0.disk.0.size"))
+	require.Equal(t, "local-lvm", d.Get("disks.0.scsi.0.scsi0.0.disk.0.storage"))
+	require.True(t, d.Get("disks.0.scsi.0.scsi0.0.disk.0.iothread").(bool))
+	require.NotEqual(t, "bc:24:11:00:00:01", strings.ToLower(d.Get("network.0.macaddr").(string)))
+
+	testFakeDelete(t, r, meta, d)
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceVmQemu_Restore_Errors(t *testing.T) {
+	fake, _ := testAccFakeProvider(t)
+	fake.AddStorage("backup", "dir", true, "backup")
+	meta := testFakeMeta(t, fake)
+	r := resourceVmQemu()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]any{
+		"name":        "restored-vm",
+		"target_node": "pve",
+		"restore":     []any{map[string]any{"archive": "backup:backup/vzdump-qemu-404.vma.zst"}}})
+	diags := r.CreateContext(context.Background(), d, meta)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[0].Summary, "unable to parse volume ID")
+	require.Equal(t, "", d.Id())
+	testFakeUnknown(t, fake)
+}
+
+func Test_ResourceVmQemu_VNet_Fake(t *testing.T) {
+	fake, _ := testAccFakeProvider(t, "pve1", "pve2")
+	meta := testFakeMeta(t, fake)
+	r := resourceVmQemu()
+
+	config := map[string]any{
+		"name":        "test-vm",
+		"target_node": "pve1",
+		"agent":       0,
+		"network":     []any{map[string]any{"id": 0, "bridge": "tenant1", "model": "virtio"}}}
+	d := schema.TestResourceDataRaw(t, r.Schema, config)
+	diags := r.CreateContext(context.Background(), d, meta)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[len(diags)-1].Summary, "'tenant1' is neither an SDN VNet nor a bridge of the node")
+
+	// Only the bridges of the node of the guest are looked up.
+	fake.Forbid("/nodes/pve2/network")
+	testFakeCreate(t, resourceSDNZoneSimple(), meta, map[string]any{"zone": "tenants"})
+	testFakeCreate(t, resourceSDNVNet(), meta, map[string]any{"vnet": "tenant1", "zone": "tenants"})
+	d = testFakeCreate(t, r, meta, config)
+	require.Equal(t, "tenant1", d.Get("network.0.bridge"))
+
+	// Without the privileges to look up the bridges, they are not checked.
+	fake.Forbid("/cluster/sdn/vnets")
+	config["name"] = "other-vm"
+	d = schema.TestResourceDataRaw(t, r.Schema, config)
+	diags = r.CreateContext(context.Background(), d, meta)
+	require.False(t, diags.HasError(), diags)
+	require.Len(t, diags, 1)
+	require.Equal(t, diag.Warning, diags[0].Severity)
+	require.Contains(t, diags[0].Summary, "are not checked")
+	testFakeUnknown(t, fake)
+}
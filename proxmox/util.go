@@ -1,22 +1,38 @@
 package proxmox
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/rs/zerolog"
 )
 
 const defaultDescription = "Managed by Terraform."
 
+// taskWarnings returns a warning for a task that finished with warnings.
+// The task calls of the SDK already return an error for tasks that failed, a task with warnings succeeded.
+func taskWarnings(exitStatus, summary string) diag.Diagnostics {
+	if !strings.HasPrefix(exitStatus, "WARNINGS") {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  summary,
+		Detail:   "the task finished with " + exitStatus}}
+}
+
 var rxClusterRsId = regexp.MustCompile(`([^/]+)/([^/]+)`)
 
 var machineModelsRegex = regexp.MustCompile(`(^pc|^q35|^virt)`)
@@ -510,3 +526,82 @@ func ByteCountIEC(b int64) string {
 	return fmt.Sprintf("%0.f%c",
 		float64(b)/float64(div), "KMGTPE"[exp])
 }
+
+// postString returns a parameter for Client.Post that is sent even when it is empty, the SDK drops empty strings
+// from POST requests but sends every element of a list. This is needed to clear a property through a POST.
+func postString(value string) []string {
+	return []string{value}
+}
+
+// itemValue returns the value of a key from an API item as a string, missing keys become an empty string.
+func itemValue(item map[string]interface{}, key string) string {
+	switch v := item[key].(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// listItem returns the item with the ID from a list endpoint, or nil when it does not exist.
+func listItem(ctx context.Context, client *pveSDK.Client, url, idKey, id string) (map[string]interface{}, error) {
+	list, err := client.GetItemListInterfaceArray(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range list {
+		if item, ok := e.(map[string]interface{}); ok && itemValue(item, idKey) == id {
+			return item, nil
+		}
+	}
+	return nil, nil
+}
+
+// optionParams adds the arguments of options, which maps arguments to API options, to the parameters of a create or update request.
+// Arguments that force a new resource are skipped on update, as the API does not allow to change them.
+func optionParams(d *schema.ResourceData, params map[string]interface{}, options map[string]string, s map[string]*schema.Schema, update bool) {
+	for key, option := range options {
+		if update && s[key].ForceNew {
+			continue
+		}
+		params[option] = d.Get(key)
+	}
+}
+
+// deleteEmptyParams removes the empty parameters, on update they are listed in `delete` so the API removes them.
+func deleteEmptyParams(params map[string]interface{}, update bool) {
+	deleteKeys := make([]string, 0)
+	for option, v := range params {
+		if v == "" || v == 0 {
+			delete(params, option)
+			deleteKeys = append(deleteKeys, option)
+		}
+	}
+	if update && len(deleteKeys) > 0 {
+		sort.Strings(deleteKeys)
+		params["delete"] = strings.Join(deleteKeys, ",")
+	}
+}
+
+// optionRead sets the arguments of options, which maps arguments to API options, from an API item.
+// Options that are not returned get the default of their argument.
+func optionRead(d *schema.ResourceData, item map[string]interface{}, options map[string]string, s map[string]*schema.Schema) {
+	for key, option := range options {
+		value := itemValue(item, option)
+		if value == "" && s[key].Default != nil {
+			d.Set(key, s[key].Default)
+			continue
+		}
+		switch s[key].Type {
+		case schema.TypeBool:
+			d.Set(key, value == "1")
+		case schema.TypeInt:
+			v, _ := strconv.Atoi(value)
+			d.Set(key, v)
+		default:
+			d.Set(key, value)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"strconv"
 	"strings"
 
+	pveSDK "github.com/Telmate/proxmox-api-go/proxmox"
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -65,3 +66,34 @@ func BIOSValidator() schema.SchemaValidateDiagFunc {
 		"seabios",
 	}, false))
 }
+
+func ChecksumAlgorithmValidator() schema.SchemaValidateDiagFunc {
+	return validation.ToDiagFunc(validation.StringInSlice([]string{
+		"md5",
+		"sha1",
+		"sha224",
+		"sha256",
+		"sha384",
+		"sha512",
+	}, false))
+}
+
+func DownloadModeValidator() schema.SchemaValidateDiagFunc {
+	return validation.ToDiagFunc(validation.StringInSlice([]string{
+		storageIsoDownloadLocal,
+		storageIsoDownloadServer,
+	}, false))
+}
+
+func SnapshotNameValidator() schema.SchemaValidateDiagFunc {
+	return func(i interface{}, k cty.Path) diag.Diagnostics {
+		value, ok := i.(string)
+		if !ok {
+			return diag.Errorf("expected type of %v to be string", k)
+		}
+		if err := pveSDK.SnapshotName(value).Validate(); err != nil {
+			return diag.FromErr(err)
+		}
+		return nil
+	}
+}